@@ -0,0 +1,393 @@
+// mdcli math renderer: converts the TeX emitted by goldmark-mathjax
+// (<span class="math inline|display">) into native MathML, with no network
+// access. Unsupported input is left as TeX and marked with .math-error.
+(function () {
+  "use strict";
+
+  const greek = {
+    alpha: "α", beta: "β", gamma: "γ", delta: "δ", epsilon: "ϵ", varepsilon: "ε",
+    zeta: "ζ", eta: "η", theta: "θ", vartheta: "ϑ", iota: "ι", kappa: "κ",
+    lambda: "λ", mu: "μ", nu: "ν", xi: "ξ", pi: "π", varpi: "ϖ", rho: "ρ",
+    varrho: "ϱ", sigma: "σ", varsigma: "ς", tau: "τ", upsilon: "υ", phi: "ϕ",
+    varphi: "φ", chi: "χ", psi: "ψ", omega: "ω",
+    Gamma: "Γ", Delta: "Δ", Theta: "Θ", Lambda: "Λ", Xi: "Ξ", Pi: "Π",
+    Sigma: "Σ", Upsilon: "Υ", Phi: "Φ", Psi: "Ψ", Omega: "Ω",
+  };
+
+  const identifiers = {
+    infty: "∞", partial: "∂", nabla: "∇", ell: "ℓ", hbar: "ℏ", emptyset: "∅",
+    varnothing: "∅", aleph: "ℵ", Re: "ℜ", Im: "ℑ", wp: "℘", dots: "…",
+    ldots: "…", cdots: "⋯", vdots: "⋮", ddots: "⋱", prime: "′", top: "⊤", bot: "⊥",
+  };
+
+  const operators = {
+    pm: "±", mp: "∓", times: "×", div: "÷", cdot: "⋅", ast: "∗", star: "⋆",
+    circ: "∘", bullet: "∙", oplus: "⊕", ominus: "⊖", otimes: "⊗", wedge: "∧",
+    land: "∧", vee: "∨", lor: "∨", cap: "∩", cup: "∪", setminus: "∖",
+    leq: "≤", le: "≤", geq: "≥", ge: "≥", neq: "≠", ne: "≠", approx: "≈",
+    equiv: "≡", sim: "∼", simeq: "≃", cong: "≅", propto: "∝", ll: "≪", gg: "≫",
+    in: "∈", notin: "∉", ni: "∋", subset: "⊂", supset: "⊃", subseteq: "⊆",
+    supseteq: "⊇", mid: "∣", parallel: "∥", perp: "⊥", forall: "∀", exists: "∃",
+    neg: "¬", lnot: "¬", to: "→", rightarrow: "→", leftarrow: "←", gets: "←",
+    leftrightarrow: "↔", Rightarrow: "⇒", Leftarrow: "⇐", Leftrightarrow: "⇔",
+    implies: "⟹", iff: "⟺", mapsto: "↦", uparrow: "↑", downarrow: "↓",
+    langle: "⟨", rangle: "⟩", lceil: "⌈", rceil: "⌉", lfloor: "⌊", rfloor: "⌋",
+    colon: ":", vert: "|", Vert: "‖",
+  };
+
+  // Operators whose limits go above and below in display mode
+  const largeOperators = {
+    sum: "∑", prod: "∏", coprod: "∐", bigcup: "⋃", bigcap: "⋂", bigoplus: "⨁",
+    bigotimes: "⨂", int: "∫", iint: "∬", iiint: "∭", oint: "∮",
+  };
+
+  const functions = [
+    "sin", "cos", "tan", "cot", "sec", "csc", "arcsin", "arccos", "arctan",
+    "sinh", "cosh", "tanh", "log", "ln", "lg", "exp", "det", "dim", "ker",
+    "deg", "gcd", "hom", "arg", "Pr",
+  ];
+  // Functions whose subscripts go below in display mode
+  const limitFunctions = ["lim", "liminf", "limsup", "max", "min", "sup", "inf"];
+
+  const accents = {
+    hat: "^", widehat: "^", bar: "¯", overline: "¯", vec: "→", dot: "˙",
+    ddot: "¨", tilde: "~", widetilde: "~",
+  };
+
+  const fonts = {
+    mathbf: "bold", boldsymbol: "bold-italic", mathit: "italic",
+    mathbb: "double-struck", mathcal: "script", mathscr: "script",
+    mathfrak: "fraktur", mathsf: "sans-serif", mathtt: "monospace", mathrm: "normal",
+  };
+
+  const spaces = { ",": "0.1667em", ":": "0.2222em", ";": "0.2778em", "!": "-0.1667em", quad: "1em", qquad: "2em" };
+
+  const fences = {
+    pmatrix: ["(", ")"], bmatrix: ["[", "]"], Bmatrix: ["{", "}"],
+    vmatrix: ["|", "|"], Vmatrix: ["‖", "‖"], cases: ["{", ""],
+  };
+
+  function escape(s) {
+    return s.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+  }
+
+  function tokenize(tex) {
+    const tokens = [];
+    let i = 0;
+    while (i < tex.length) {
+      const c = tex[i];
+      if (/\s/.test(c)) {
+        i++;
+      } else if (c === "\\") {
+        let j = i + 1;
+        if (/[a-zA-Z]/.test(tex[j] || "")) {
+          while (j < tex.length && /[a-zA-Z]/.test(tex[j])) j++;
+        } else {
+          j++;
+        }
+        tokens.push({ type: "cmd", value: tex.slice(i + 1, j), at: i });
+        i = j;
+      } else if (/[0-9.]/.test(c)) {
+        let j = i;
+        while (j < tex.length && /[0-9.]/.test(tex[j])) j++;
+        tokens.push({ type: "num", value: tex.slice(i, j), at: i });
+        i = j;
+      } else {
+        tokens.push({ type: /[a-zA-Z]/.test(c) ? "id" : "char", value: c, at: i });
+        i++;
+      }
+    }
+    return tokens;
+  }
+
+  function Parser(tex, display) {
+    this.tokens = tokenize(tex);
+    this.tex = tex;
+    this.pos = 0;
+    this.display = display;
+  }
+
+  Parser.prototype.peek = function () {
+    return this.tokens[this.pos];
+  };
+
+  Parser.prototype.next = function () {
+    return this.tokens[this.pos++];
+  };
+
+  Parser.prototype.expect = function (value) {
+    const tok = this.next();
+    if (!tok || tok.value !== value) {
+      throw new Error("expected '" + value + "'");
+    }
+  };
+
+  // parseRow reads atoms until one of the stop tokens
+  Parser.prototype.parseRow = function (stops) {
+    const items = [];
+    for (;;) {
+      const tok = this.peek();
+      if (!tok) break;
+      if (tok.type === "char" && stops.indexOf(tok.value) >= 0) break;
+      if (tok.type === "cmd" && stops.indexOf("\\" + tok.value) >= 0) break;
+      items.push(this.parseScripts());
+    }
+    return items.join("");
+  };
+
+  // parseGroup reads a braced group or a single atom
+  Parser.prototype.parseGroup = function () {
+    const tok = this.peek();
+    if (tok && tok.type === "char" && tok.value === "{") {
+      this.next();
+      const row = this.parseRow(["}"]);
+      this.expect("}");
+      return "<mrow>" + row + "</mrow>";
+    }
+    return this.parseAtom();
+  };
+
+  // readText reads the raw source of a braced group
+  Parser.prototype.readText = function () {
+    const open = this.peek();
+    this.expect("{");
+    let depth = 1;
+    for (;;) {
+      const tok = this.next();
+      if (!tok) throw new Error("unclosed group");
+      if (tok.type !== "char") continue;
+      if (tok.value === "{") depth++;
+      if (tok.value === "}" && --depth === 0) {
+        return this.tex.slice(open.at + 1, tok.at);
+      }
+    }
+  };
+
+  Parser.prototype.parseScripts = function () {
+    const start = this.peek();
+    const base = this.parseAtom();
+    const limits =
+      this.display && start && start.type === "cmd" &&
+      ((start.value in largeOperators && !/^i*int$|^oint$/.test(start.value)) ||
+        limitFunctions.indexOf(start.value) >= 0);
+
+    // Function names are followed by an invisible function application
+    const apply =
+      start && start.type === "cmd" &&
+      (functions.indexOf(start.value) >= 0 || limitFunctions.indexOf(start.value) >= 0 || start.value === "operatorname")
+        ? "<mo>&#x2061;</mo>"
+        : "";
+
+    let sub = null;
+    let sup = null;
+    for (;;) {
+      const tok = this.peek();
+      if (!tok || tok.type !== "char") break;
+      if (tok.value === "_" && sub === null) {
+        this.next();
+        sub = this.parseGroup();
+      } else if (tok.value === "^" && sup === null) {
+        this.next();
+        sup = this.parseGroup();
+      } else if (tok.value === "'") {
+        this.next();
+        sup = (sup || "") + "<mo>′</mo>";
+      } else {
+        break;
+      }
+    }
+
+    return this.attach(base, sub, sup, limits) + apply;
+  };
+
+  // attach adds sub- and superscripts to base
+  Parser.prototype.attach = function (base, sub, sup, limits) {
+    if (sup !== null) sup = "<mrow>" + sup + "</mrow>";
+    if (sub !== null && sup !== null) {
+      return limits
+        ? "<munderover>" + base + sub + sup + "</munderover>"
+        : "<msubsup>" + base + sub + sup + "</msubsup>";
+    }
+    if (sub !== null) {
+      return limits ? "<munder>" + base + sub + "</munder>" : "<msub>" + base + sub + "</msub>";
+    }
+    if (sup !== null) {
+      return limits ? "<mover>" + base + sup + "</mover>" : "<msup>" + base + sup + "</msup>";
+    }
+    return base;
+  };
+
+  Parser.prototype.parseAtom = function () {
+    const tok = this.next();
+    if (!tok) throw new Error("unexpected end of input");
+
+    switch (tok.type) {
+      case "num":
+        return "<mn>" + tok.value + "</mn>";
+      case "id":
+        return "<mi>" + tok.value + "</mi>";
+      case "char":
+        if (tok.value === "{") {
+          const row = this.parseRow(["}"]);
+          this.expect("}");
+          return "<mrow>" + row + "</mrow>";
+        }
+        if (tok.value === "}" || tok.value === "&" || tok.value === "^" || tok.value === "_") {
+          throw new Error("unexpected '" + tok.value + "'");
+        }
+        if (tok.value === "~") return '<mspace width="0.2778em"></mspace>';
+        return "<mo>" + escape(tok.value === "-" ? "−" : tok.value) + "</mo>";
+      default:
+        return this.parseCommand(tok.value);
+    }
+  };
+
+  Parser.prototype.parseCommand = function (name) {
+    if (name in greek) {
+      // Capital Greek letters are upright
+      const upright = name[0] === name[0].toUpperCase();
+      return (upright ? '<mi mathvariant="normal">' : "<mi>") + greek[name] + "</mi>";
+    }
+    if (name in identifiers) return "<mi>" + identifiers[name] + "</mi>";
+    if (name in operators) return "<mo>" + escape(operators[name]) + "</mo>";
+    if (name in largeOperators) return '<mo largeop="true">' + largeOperators[name] + "</mo>";
+    if (name in spaces) return '<mspace width="' + spaces[name] + '"></mspace>';
+    if (functions.indexOf(name) >= 0 || limitFunctions.indexOf(name) >= 0) {
+      return "<mi>" + name.replace(/^lim(inf|sup)$/, "lim $1") + "</mi>";
+    }
+    if ("{}|#$%&_".indexOf(name) >= 0 && name.length === 1) {
+      return "<mo>" + escape(name === "|" ? "‖" : name) + "</mo>";
+    }
+    if (name === " ") return '<mspace width="0.2778em"></mspace>';
+    if (name === "\\") throw new Error("line break outside of an environment");
+
+    switch (name) {
+      case "frac":
+      case "dfrac":
+      case "tfrac":
+        return "<mfrac>" + this.parseGroup() + this.parseGroup() + "</mfrac>";
+      case "binom":
+        return '<mrow><mo>(</mo><mfrac linethickness="0">' + this.parseGroup() + this.parseGroup() + "</mfrac><mo>)</mo></mrow>";
+      case "sqrt": {
+        const tok = this.peek();
+        if (tok && tok.type === "char" && tok.value === "[") {
+          this.next();
+          const index = this.parseRow(["]"]);
+          this.expect("]");
+          return "<mroot>" + this.parseGroup() + "<mrow>" + index + "</mrow></mroot>";
+        }
+        return "<msqrt>" + this.parseGroup() + "</msqrt>";
+      }
+      case "text":
+      case "textrm":
+      case "textit":
+      case "textbf":
+      case "mbox":
+        return "<mtext>" + escape(this.readText()) + "</mtext>";
+      case "operatorname":
+        return "<mi>" + escape(this.readText()) + "</mi>";
+      case "left": {
+        const open = this.delimiter();
+        const row = this.parseRow(["\\right"]);
+        this.expect("right");
+        const close = this.delimiter();
+        return '<mrow><mo stretchy="true">' + open + "</mo>" + row + '<mo stretchy="true">' + close + "</mo></mrow>";
+      }
+      case "begin":
+        return this.parseEnvironment();
+      case "underline":
+        return '<munder accentunder="true">' + this.parseGroup() + "<mo>_</mo></munder>";
+      case "overbrace":
+        return '<mover accent="true">' + this.parseGroup() + "<mo>⏞</mo></mover>";
+      case "underbrace":
+        return '<munder accentunder="true">' + this.parseGroup() + "<mo>⏟</mo></munder>";
+    }
+
+    if (name in accents) {
+      return '<mover accent="true">' + this.parseGroup() + "<mo>" + accents[name] + "</mo></mover>";
+    }
+    if (name in fonts) {
+      const group = this.parseGroup();
+      return '<mstyle mathvariant="' + fonts[name] + '">' + group + "</mstyle>";
+    }
+
+    throw new Error("unsupported command \\" + name);
+  };
+
+  // delimiter reads the token after \left or \right
+  Parser.prototype.delimiter = function () {
+    const tok = this.next();
+    if (!tok) throw new Error("missing delimiter");
+    if (tok.type === "char") return tok.value === "." ? "" : escape(tok.value);
+    if (tok.value === "{" || tok.value === "}") return tok.value;
+    if (tok.value === "|") return "‖";
+    if (tok.value in operators) return operators[tok.value];
+    throw new Error("unsupported delimiter \\" + tok.value);
+  };
+
+  Parser.prototype.parseEnvironment = function () {
+    const env = this.readText();
+    if (env === "array") this.readText(); // column spec
+
+    const rows = [];
+    let cells = [];
+    for (;;) {
+      const tok = this.peek();
+      if (!tok) throw new Error("unclosed environment " + env);
+      if (tok.type === "cmd" && tok.value === "end") {
+        this.next();
+        if (this.readText() !== env) throw new Error("mismatched \\end");
+        break;
+      }
+      cells.push("<mtd>" + this.parseRow(["&", "\\\\", "\\end"]) + "</mtd>");
+      const sep = this.peek();
+      if (sep && sep.value === "&") {
+        this.next();
+      } else if (sep && sep.type === "cmd" && sep.value === "\\") {
+        this.next();
+        rows.push("<mtr>" + cells.join("") + "</mtr>");
+        cells = [];
+      }
+    }
+    if (cells.length > 0) rows.push("<mtr>" + cells.join("") + "</mtr>");
+
+    const align = env === "cases" || env === "aligned" || env === "align" ? ' columnalign="left"' : "";
+    const table = "<mtable" + align + ">" + rows.join("") + "</mtable>";
+    const fence = fences[env];
+    if (!fence) return table;
+    return '<mrow><mo stretchy="true">' + escape(fence[0]) + "</mo>" + table +
+      (fence[1] ? '<mo stretchy="true">' + escape(fence[1]) + "</mo>" : "") + "</mrow>";
+  };
+
+  // toMathML converts a TeX expression. It throws on unsupported input.
+  function toMathML(tex, display) {
+    const parser = new Parser(tex, display);
+    const row = parser.parseRow([]);
+    return '<math xmlns="http://www.w3.org/1998/Math/MathML"' +
+      (display ? ' display="block"' : "") + "><mrow>" + row + "</mrow></math>";
+  }
+
+  // stripDelimiters removes the \( \) or \[ \] added by goldmark-mathjax
+  function stripDelimiters(text) {
+    const m = text.trim().match(/^\\[([]([\s\S]*)\\[)\]]$/);
+    return m ? m[1] : text;
+  }
+
+  // renderAll converts every unrendered math span below root
+  function renderAll(root) {
+    (root || document).querySelectorAll("span.math:not([data-math-rendered])").forEach((el) => {
+      const display = el.classList.contains("display");
+      const tex = stripDelimiters(el.textContent);
+      el.setAttribute("data-math-rendered", "");
+      try {
+        el.innerHTML = toMathML(tex, display);
+      } catch (err) {
+        el.classList.add("math-error");
+        el.style.borderBottom = "1px dashed currentColor";
+        el.title = "Could not render math: " + err.message;
+      }
+    });
+  }
+
+  window.mdcliMath = { toMathML: toMathML, renderAll: renderAll };
+})();
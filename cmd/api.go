@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tacheraSasi/mdcli/renderer"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Start an HTTP server that renders Markdown",
+	Long: `Start an HTTP server exposing mdcli's rendering pipeline at /api/render,
+so other services can render Markdown without shelling out.
+
+POST /api/render accepts either a JSON body:
+
+  {"markdown": "# Hello", "format": "html", "theme": "github", "width": 80, "autolink": true}
+
+or a form (multipart or URL-encoded) with the Markdown in a "markdown"
+field or a "file" upload, and the options as fields of the same names.
+The format is html (default), text or terminal, and the width, for text
+and terminal output, is between 20 and 500. The response holds the
+output, the title (from front matter or the first heading), the heading
+outline and the front matter:
+
+  {"output": "...", "format": "html", "title": "Hello", "outline": [...], "frontMatter": {...}}
+
+Examples:
+  mdcli api                                  # Listen on localhost:8090
+  mdcli api --port 9000 --concurrency 8      # Custom port and worker cap
+  curl -F file=@README.md -F format=text localhost:8090/api/render`,
+	Args: cobra.NoArgs,
+	Run:  runAPI,
+}
+
+var (
+	apiPort        int
+	apiBind        string
+	apiTheme       string
+	apiWidth       int
+	apiMaxBody     int64
+	apiTimeout     time.Duration
+	apiConcurrency int
+)
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().IntVarP(&apiPort, "port", "p", 8090, "Port to listen on")
+	apiCmd.Flags().StringVarP(&apiBind, "bind", "b", "localhost", "Bind address")
+	apiCmd.Flags().StringVarP(&apiTheme, "theme", "t", "github", "Default theme")
+	apiCmd.Flags().IntVarP(&apiWidth, "width", "w", 80, "Default width")
+	apiCmd.Flags().Int64Var(&apiMaxBody, "max-body", defaultMaxRenderBody, "Maximum request size in bytes")
+	apiCmd.Flags().DurationVar(&apiTimeout, "timeout", defaultRenderTimeout, "Maximum time to render one request")
+	apiCmd.Flags().IntVar(&apiConcurrency, "concurrency", runtime.NumCPU(), "Maximum number of concurrent renders")
+}
+
+const (
+	defaultMaxRenderBody = 10 << 20
+	defaultRenderTimeout = 10 * time.Second
+
+	// minAPIWidth and maxAPIWidth bound the width a request may ask for
+	minAPIWidth = 20
+	maxAPIWidth = 500
+)
+
+// renderAPI renders Markdown posted to /api/render
+type renderAPI struct {
+	theme   string
+	width   int
+	maxBody int64
+	timeout time.Duration
+	// slots holds a token for every render in progress
+	slots chan struct{}
+	// preview renders HTML for the serve page: chroma classes and no CDN
+	// scripts, since the page provides both
+	preview bool
+}
+
+func newRenderAPI(theme string, width int, maxBody int64, timeout time.Duration, concurrency int, preview bool) *renderAPI {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &renderAPI{
+		theme:   theme,
+		width:   width,
+		maxBody: maxBody,
+		timeout: timeout,
+		slots:   make(chan struct{}, concurrency),
+		preview: preview,
+	}
+}
+
+// renderRequest is the body of a /api/render request
+type renderRequest struct {
+	Markdown string `json:"markdown"`
+	Format   string `json:"format"`
+	Theme    string `json:"theme"`
+	Width    int    `json:"width"`
+	Autolink *bool  `json:"autolink"`
+}
+
+// renderResponse is returned by /api/render
+type renderResponse struct {
+	Output      string                 `json:"output"`
+	Format      string                 `json:"format"`
+	Title       string                 `json:"title,omitempty"`
+	Outline     []renderer.Heading     `json:"outline"`
+	FrontMatter map[string]interface{} `json:"frontMatter,omitempty"`
+}
+
+// apiFormats are the output formats the API can return
+var apiFormats = map[string]bool{"html": true, "text": true, "plain": true, "terminal": true}
+
+func runAPI(cmd *cobra.Command, args []string) {
+	api := newRenderAPI(apiTheme, apiWidth, apiMaxBody, apiTimeout, apiConcurrency, false)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/render", api)
+
+	addr := fmt.Sprintf("%s:%d", apiBind, apiPort)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       apiTimeout + 10*time.Second,
+		WriteTimeout:      apiTimeout + 10*time.Second,
+	}
+
+	fmt.Printf("🚀 Starting render API...\n")
+	fmt.Printf("🌐 URL: http://%s/api/render\n", addr)
+	fmt.Printf("🎨 Theme: %s\n", apiTheme)
+	fmt.Printf("⚙️  Limits: %d bytes, %s per request, %d concurrent renders\n", apiMaxBody, apiTimeout, cap(api.slots))
+	fmt.Printf("Press Ctrl+C to stop\n\n")
+
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (a *renderAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxBody)
+	req, err := a.parseRequest(r)
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		// Multipart errors do not always wrap the MaxBytesError
+		if errors.As(err, &tooLarge) || strings.Contains(err.Error(), "request body too large") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeAPIError(w, status, err.Error())
+		return
+	}
+
+	if req.Width != 0 && (req.Width < minAPIWidth || req.Width > maxAPIWidth) {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("width must be between %d and %d", minAPIWidth, maxAPIWidth))
+		return
+	}
+
+	format := strings.ToLower(req.Format)
+	if format == "" {
+		format = "html"
+	}
+	if !apiFormats[format] {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unsupported format '%s' (use html, text or terminal)", req.Format))
+		return
+	}
+
+	opts := renderer.RenderOptions{
+		Input:            req.Markdown,
+		Autolink:         req.Autolink == nil || *req.Autolink,
+		Theme:            req.Theme,
+		Width:            req.Width,
+		OutputFormat:     format,
+		NoScripts:        a.preview,
+		HighlightClasses: a.preview,
+	}
+	if opts.Theme == "" {
+		opts.Theme = a.theme
+	}
+	if opts.Width == 0 {
+		opts.Width = a.width
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), a.timeout)
+	defer cancel()
+
+	doc, err := a.render(ctx, opts)
+	switch {
+	case errors.Is(err, errRenderBusy):
+		w.Header().Set("Retry-After", "1")
+		writeAPIError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		writeAPIError(w, http.StatusGatewayTimeout, "render timed out")
+		return
+	case err != nil:
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	title := ""
+	if len(doc.Outline) > 0 {
+		title = doc.Outline[0].Text
+	}
+	resp := renderResponse{
+		Output:      doc.Output,
+		Format:      format,
+		Title:       doc.Title(title),
+		Outline:     doc.Outline,
+		FrontMatter: doc.FrontMatter.Params,
+	}
+	if resp.Outline == nil {
+		resp.Outline = []renderer.Heading{}
+	}
+
+	writeAPIJSON(w, http.StatusOK, resp)
+}
+
+var errRenderBusy = errors.New("too many concurrent renders, try again later")
+
+// render runs one render once a slot is free. Rendering cannot be
+// interrupted, so a render that outlives ctx keeps its slot until it
+// finishes; this keeps the cap on actual CPU use.
+func (a *renderAPI) render(ctx context.Context, opts renderer.RenderOptions) (*renderer.Document, error) {
+	select {
+	case a.slots <- struct{}{}:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errRenderBusy
+		}
+		return nil, ctx.Err()
+	}
+
+	type result struct {
+		doc *renderer.Document
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-a.slots }()
+		doc, err := renderer.Render(opts)
+		done <- result{doc, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.doc, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// parseRequest reads a JSON or form request body
+func (a *renderAPI) parseRequest(r *http.Request) (renderRequest, error) {
+	var req renderRequest
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data", "application/x-www-form-urlencoded":
+		if err := r.ParseMultipartForm(a.maxBody); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return req, err
+		}
+		req.Markdown = r.FormValue("markdown")
+		if r.MultipartForm != nil {
+			if file, _, err := r.FormFile("file"); err == nil {
+				defer file.Close()
+				data, err := io.ReadAll(file)
+				if err != nil {
+					return req, err
+				}
+				req.Markdown = string(data)
+			} else if !errors.Is(err, http.ErrMissingFile) {
+				return req, err
+			}
+		}
+		req.Format = r.FormValue("format")
+		req.Theme = r.FormValue("theme")
+		if value := r.FormValue("width"); value != "" {
+			width, err := strconv.Atoi(value)
+			if err != nil {
+				return req, fmt.Errorf("invalid width '%s'", value)
+			}
+			req.Width = width
+		}
+		if value := r.FormValue("autolink"); value != "" {
+			autolink, err := strconv.ParseBool(value)
+			if err != nil {
+				return req, fmt.Errorf("invalid autolink '%s'", value)
+			}
+			req.Autolink = &autolink
+		}
+
+	case "application/json", "":
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return req, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+	default:
+		return req, fmt.Errorf("unsupported content type '%s'", mediaType)
+	}
+
+	return req, nil
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, map[string]string{"error": message})
+}
+
+// writeAPIJSON sends body as the response. The status is already sent when
+// encoding fails, usually because the client went away, so the error can
+// only be logged.
+func writeAPIJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing API response: %v\n", err)
+	}
+}
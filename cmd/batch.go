@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"html"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/ignore"
 	"github.com/tacheraSasi/mdcli/renderer"
 )
 
@@ -16,7 +19,14 @@ var batchCmd = &cobra.Command{
 	Use:   "batch [directory]",
 	Short: "Process all Markdown files in a directory",
 	Long: `Batch process all Markdown files in a directory and its subdirectories.
-Supports concurrent processing for better performance and various output formats.`,
+Supports concurrent processing for better performance and various output formats.
+Builds are incremental: a manifest in the output directory records how each
+output was produced, unchanged files are skipped and outputs of deleted files
+are removed. Use --force to rebuild everything.
+
+Files matched by .gitignore, .mdcliignore or the ignore_patterns config key
+are skipped. Unlike serve, batch does not skip hidden, node_modules, vendor
+or build directories by default.`,
 	Args: cobra.MinimumNArgs(1),
 	Run:  runBatch,
 }
@@ -29,6 +39,9 @@ var (
 	batchRecursive  bool
 	batchConcurrent int
 	batchExtension  string
+	batchForce      bool
+	batchMathML     bool
+	batchAutolink   bool
 )
 
 func init() {
@@ -41,65 +54,75 @@ func init() {
 	batchCmd.Flags().BoolVarP(&batchRecursive, "recursive", "r", false, "Process subdirectories recursively")
 	batchCmd.Flags().IntVarP(&batchConcurrent, "concurrent", "c", 4, "Number of concurrent workers")
 	batchCmd.Flags().StringVarP(&batchExtension, "ext", "e", ".html", "Output file extension")
+	batchCmd.Flags().BoolVar(&batchForce, "force", false, "Rebuild every file, ignoring the manifest")
+	batchCmd.Flags().BoolVar(&batchMathML, "mathml", false, "Convert math to MathML in HTML output")
 }
 
 type BatchJob struct {
 	InputFile  string
 	OutputFile string
 	Content    string
+	// Output is OutputFile relative to the output directory
+	Output string
+	Entry  manifestEntry
+}
+
+// batchResult reports the outcome of a single job
+type batchResult struct {
+	Job     BatchJob
+	Err     error
+	Skipped bool
+	// Unqueued is set when the job failed before rendering, leaving the
+	// previous output and its manifest entry in place
+	Unqueued bool
 }
 
 func runBatch(cmd *cobra.Command, args []string) {
 	inputDir := args[0]
+	if !cmd.Flags().Changed("mathml") {
+		batchMathML = viper.GetBool("render.mathml")
+	}
+	batchAutolink = viper.GetBool("autolink")
 
 	// Find all markdown files
-	var markdownFiles []string
-	err := filepath.Walk(inputDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if info.IsDir() {
-			if !batchRecursive && path != inputDir {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if strings.HasSuffix(strings.ToLower(path), ".md") ||
-			strings.HasSuffix(strings.ToLower(path), ".markdown") {
-			markdownFiles = append(markdownFiles, path)
-		}
-
-		return nil
-	})
-
+	markdownFiles, err := findMarkdownFiles(inputDir, loadIgnoreMatcher(inputDir, false), batchRecursive)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(markdownFiles) == 0 {
-		fmt.Println("No Markdown files found in the specified directory.")
-		return
-	}
-
-	fmt.Printf("Found %d Markdown files\n", len(markdownFiles))
-
 	// Prepare output directory
 	outputDir := batchOutput
 	if outputDir == "" {
 		outputDir = filepath.Join(inputDir, "output")
 	}
 
+	manifest := loadManifest(outputDir)
+
+	if len(markdownFiles) == 0 && len(manifest.Outputs) == 0 {
+		fmt.Println("No Markdown files found in the specified directory.")
+		return
+	}
+
+	fmt.Printf("Found %d Markdown files\n", len(markdownFiles))
+
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
 		os.Exit(1)
 	}
 
+	options := batchOptions{
+		Format:    batchFormat,
+		Theme:     batchTheme,
+		ThemeHash: hashTheme(batchTheme),
+		Width:     batchWidth,
+		Autolink:  batchAutolink,
+		MathML:    batchMathML,
+	}
+
 	// Create job queue
 	jobs := make(chan BatchJob, len(markdownFiles))
-	results := make(chan error, len(markdownFiles))
+	results := make(chan batchResult, len(markdownFiles))
 
 	// Progress bar
 	bar := progressbar.NewOptions(len(markdownFiles),
@@ -117,37 +140,59 @@ func runBatch(cmd *cobra.Command, args []string) {
 			defer wg.Done()
 			for job := range jobs {
 				err := processBatchJob(job)
-				results <- err
+				results <- batchResult{Job: job, Err: err}
 				bar.Add(1)
 			}
 		}()
 	}
 
-	// Queue jobs
+	// Queue jobs, skipping outputs that are up to date
 	go func() {
 		defer close(jobs)
 		for _, file := range markdownFiles {
 			relPath, _ := filepath.Rel(inputDir, file)
-			outputFile := filepath.Join(outputDir,
-				strings.TrimSuffix(relPath, filepath.Ext(relPath))+batchExtension)
-
-			// Ensure output subdirectory exists
-			if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating output subdirectory: %v\n", err)
-				continue
-			}
+			outputRel := strings.TrimSuffix(relPath, filepath.Ext(relPath)) + batchExtension
+			outputFile := filepath.Join(outputDir, outputRel)
 
 			content, err := renderer.ReadFile(file)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+				job := BatchJob{InputFile: file, OutputFile: outputFile, Output: filepath.ToSlash(outputRel)}
+				results <- batchResult{Job: job, Err: fmt.Errorf("error reading %s: %w", file, err), Unqueued: true}
+				bar.Add(1)
 				continue
 			}
 
-			jobs <- BatchJob{
+			job := BatchJob{
 				InputFile:  file,
 				OutputFile: outputFile,
 				Content:    content,
+				Output:     filepath.ToSlash(outputRel),
+				Entry: manifestEntry{
+					Source:  filepath.ToSlash(relPath),
+					Hash:    hashContent(content),
+					Options: options,
+					Version: version,
+				},
+			}
+			if batchFormat == "pdf" {
+				// Only PDFs embed images; other formats link to them
+				job.Entry.Deps = hashImages(content, filepath.Dir(file))
+			}
+
+			if !batchForce && manifest.upToDate(outputDir, job.Output, job.Entry) {
+				results <- batchResult{Job: job, Skipped: true}
+				bar.Add(1)
+				continue
+			}
+
+			// Ensure output subdirectory exists
+			if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+				results <- batchResult{Job: job, Err: fmt.Errorf("error creating output subdirectory: %w", err), Unqueued: true}
+				bar.Add(1)
+				continue
 			}
+
+			jobs <- job
 		}
 	}()
 
@@ -157,38 +202,137 @@ func runBatch(cmd *cobra.Command, args []string) {
 		close(results)
 	}()
 
-	// Collect results
-	var errorCount int
-	for err := range results {
-		if err != nil {
+	// Collect results. The queue reads the manifest until every result is
+	// in, so changes to it are applied afterwards.
+	var builtCount, skippedCount, errorCount int
+	keep := make(map[string]bool)
+	built := make(map[string]manifestEntry)
+	var failed []string
+	for result := range results {
+		// Failed outputs are kept too: the last good one beats none
+		keep[result.Job.Output] = true
+		switch {
+		case result.Err != nil:
 			errorCount++
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Processing error: %v\n", err)
+			if !result.Unqueued {
+				// Rebuild it next time
+				failed = append(failed, result.Job.Output)
+			}
+			if verbose || result.Unqueued {
+				fmt.Fprintf(os.Stderr, "Processing error: %v\n", result.Err)
 			}
+		case result.Skipped:
+			skippedCount++
+		default:
+			builtCount++
+			built[result.Job.Output] = result.Job.Entry
+		}
+	}
+	for _, output := range failed {
+		delete(manifest.Outputs, output)
+	}
+	for output, entry := range built {
+		manifest.Outputs[output] = entry
+	}
+
+	// Outputs whose source is gone (deleted, ignored or renamed) are removed
+	pruned := manifest.prune(outputDir, keep)
+	if verbose {
+		for _, output := range pruned {
+			fmt.Printf("🗑️  Pruned %s\n", output)
 		}
 	}
 
+	if err := manifest.save(outputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing manifest: %v\n", err)
+	}
+
 	bar.Finish()
-	fmt.Printf("\n✅ Processed %d files", len(markdownFiles)-errorCount)
+	fmt.Printf("\n✅ Built %d, skipped %d, pruned %d files", builtCount, skippedCount, len(pruned))
 	if errorCount > 0 {
 		fmt.Printf(" (%d errors)", errorCount)
 	}
 	fmt.Printf("\n📁 Output directory: %s\n", outputDir)
 }
 
+// findMarkdownFiles returns the Markdown files below dir that matcher does
+// not ignore, descending into subdirectories when recursive is set
+func findMarkdownFiles(dir string, matcher *ignore.Matcher, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if matcher.Match(ignore.Clean(dir, path), info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			if !recursive && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(strings.ToLower(path), ".md") ||
+			strings.HasSuffix(strings.ToLower(path), ".markdown") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	return files, err
+}
+
+// expandMarkdownArgs returns the files named by args, searching directories
+// recursively for Markdown files that are not ignored
+func expandMarkdownArgs(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		found, err := findMarkdownFiles(arg, loadIgnoreMatcher(arg, false), true)
+		if err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", arg, err)
+		}
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
 func processBatchJob(job BatchJob) error {
-	rendered, err := renderer.Render(renderer.RenderOptions{
+	doc, err := renderer.Render(renderer.RenderOptions{
 		Input:        job.Content,
-		Autolink:     true,
+		Autolink:     batchAutolink,
 		Theme:        batchTheme,
 		Width:        batchWidth,
 		OutputFormat: batchFormat,
+		BaseDir:      filepath.Dir(job.InputFile),
+		MathML:       batchMathML,
 	})
 	if err != nil {
 		return fmt.Errorf("error rendering %s: %w", job.InputFile, err)
 	}
+	warnFrontMatter(job.InputFile, doc.FrontMatter)
+
+	output := doc.Output
+	if batchFormat == "html" && doc.FrontMatter.Title != "" {
+		// Browsers hoist a leading <title> into the head, so the tab shows
+		// the document title instead of the file name
+		output = fmt.Sprintf("<title>%s</title>\n%s", html.EscapeString(doc.FrontMatter.Title), output)
+	}
 
-	err = os.WriteFile(job.OutputFile, []byte(rendered), 0644)
+	err = os.WriteFile(job.OutputFile, []byte(output), 0644)
 	if err != nil {
 		return fmt.Errorf("error writing %s: %w", job.OutputFile, err)
 	}
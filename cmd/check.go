@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/linkcheck"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check Markdown files for problems",
+	Long:  "Verify Markdown documents, for example in CI before publishing them.",
+}
+
+var checkLinksCmd = &cobra.Command{
+	Use:   "links [file-or-directory]",
+	Short: "Find broken links, anchors and images",
+	Long: `Check the links of every Markdown file in a directory, skipping the same
+ignored files as serve. Relative links must point to existing files,
+#anchors must match a heading ID (or an HTML id) of the target document, and
+images must exist. Root-relative links such as /docs/guide.md resolve against
+the checked directory.
+
+External http(s) links are only requested with --external. URLs matching
+--skip-url or the check.skip_urls config key are never requested.
+
+The command exits with status 1 when a link is broken.
+
+Examples:
+  mdcli check links                       # Check the current directory
+  mdcli check links docs/ --external      # Also request external URLs
+  mdcli check links docs/ --format json   # Machine readable report`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runCheckLinks,
+}
+
+var (
+	checkFormat      string
+	checkExternal    bool
+	checkTimeout     time.Duration
+	checkConcurrency int
+	checkSkipURLs    []string
+)
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.AddCommand(checkLinksCmd)
+
+	checkLinksCmd.Flags().StringVarP(&checkFormat, "format", "f", "text", "Output format (text, json)")
+	checkLinksCmd.Flags().BoolVar(&checkExternal, "external", false, "Request external http(s) links")
+	checkLinksCmd.Flags().DurationVar(&checkTimeout, "timeout", 10*time.Second, "Timeout for each external request")
+	checkLinksCmd.Flags().IntVarP(&checkConcurrency, "concurrent", "c", 8, "Number of concurrent external requests")
+	checkLinksCmd.Flags().StringSliceVar(&checkSkipURLs, "skip-url", nil, "Regular expression of external URLs not to request (repeatable)")
+}
+
+func runCheckLinks(cmd *cobra.Command, args []string) {
+	target := "."
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if checkFormat != "text" && checkFormat != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s' (use text or json)\n", checkFormat)
+		os.Exit(1)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	root := target
+	files := []string{target}
+	if info.IsDir() {
+		files, err = findMarkdownFiles(target, loadIgnoreMatcher(target, true), true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		root = filepath.Dir(target)
+	}
+
+	checker := &linkcheck.Checker{Root: root, Concurrency: checkConcurrency}
+	if checkExternal {
+		var skip []*regexp.Regexp
+		for _, pattern := range append(viper.GetStringSlice("check.skip_urls"), checkSkipURLs...) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid skip URL pattern '%s': %v\n", pattern, err)
+				os.Exit(1)
+			}
+			skip = append(skip, re)
+		}
+		checker.External = linkcheck.NewHTTPChecker(checkTimeout, skip)
+	}
+
+	report := checker.Check(context.Background(), files)
+
+	if checkFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		printLinkReport(report)
+	}
+
+	if len(report.Problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+// printLinkReport lists the problems in file:line:col form and a summary
+func printLinkReport(report *linkcheck.Report) {
+	for _, p := range report.Problems {
+		if p.Target == "" {
+			fmt.Printf("%s:%d:%d: %s\n", p.File, p.Line, p.Column, p.Reason)
+			continue
+		}
+		fmt.Printf("%s:%d:%d: broken %s %q: %s\n", p.File, p.Line, p.Column, p.Kind, p.Target, p.Reason)
+	}
+
+	checked := fmt.Sprintf("%d links in %d files", report.Links, report.Files)
+	if report.External > 0 {
+		checked += fmt.Sprintf(", %d external URLs requested", report.External)
+	}
+	if len(report.Problems) > 0 {
+		fmt.Printf("\n❌ %d problems found (%s)\n", len(report.Problems), checked)
+		return
+	}
+	fmt.Printf("✅ No broken links (%s)\n", checked)
+}
@@ -68,6 +68,8 @@ render:
   show_progress: true
   # Include metadata in output
   include_metadata: false
+  # Page long terminal output of a single file
+  pager: true
 
 # Watch mode settings
 watch:
@@ -76,14 +78,50 @@ watch:
   # Clear screen on update
   clear_screen: true
 
-# Theme customizations (advanced users)
+# Link checker settings (mdcli check links)
+check:
+  # External URLs matching these regular expressions are never requested
+  skip_urls: []
+
+# Lint rules (mdcli lint --list-rules shows them all). Set a rule to false
+# to turn it off, or to a map of its options.
+lint:
+  rules:
+    line-length:
+      max: 100
+      code_blocks: false
+    list-marker:
+      style: dash
+    # duplicate-heading: false
+
+# Formatter settings (mdcli fmt)
+fmt:
+  # Wrap paragraphs at this width; 0 keeps existing line breaks
+  width: 0
+
+# Slide decks (mdcli slides)
+slides:
+  # Split slides on "rule" (---), "heading", or "auto" (rules if any)
+  split: auto
+  # Deepest heading level that starts a slide
+  level: 2
+
+# Custom themes (advanced users). Themes can also live in
+# ~/.config/mdcli/themes/<name>.yaml, one theme per file.
 themes:
-  # You can override specific theme colors here
-  # custom:
-  #   primary: "#ff0000"
-  #   secondary: "#00ff00"
-
-# File patterns to ignore in batch mode
+  # Unset colors and the syntax style come from the inherited built-in theme.
+  # Redefining a built-in theme name overrides only the colors you set.
+  # midnight:
+  #   description: "Nord with warmer accents"
+  #   inherits: nord
+  #   syntax_style: monokai
+  #   colors:
+  #     primary: "#ff0000"
+  #     secondary: "#00ff00"
+
+# Files to ignore in batch, serve and watch, using .gitignore syntax
+# (negation with !, ** and anchored /paths). Patterns from .gitignore and
+# .mdcliignore files are applied after these.
 ignore_patterns:
   - "node_modules/**"
   - ".git/**"
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxEditSize limits the size of a saved document
+const maxEditSize = 10 << 20
+
+// editMu serializes saves so the conflict check and the write are atomic
+var editMu sync.Mutex
+
+// editFile is the JSON exchanged with the editor through /api/file
+type editFile struct {
+	// Path is relative to the served directory, empty in single-file mode
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	// ModTime, in Unix nanoseconds, is the version the editor's copy is based on
+	ModTime int64 `json:"modTime"`
+	// Force overwrites the file even when it changed since ModTime
+	Force bool   `json:"force,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+var (
+	errEditForbidden = errors.New("path is outside the served directory")
+	errEditNotFound  = errors.New("no such document")
+	errEditOrigin    = errors.New("request did not come from the editor")
+	errEditHost      = errors.New("request is not addressed to this server")
+)
+
+// registerEditRoutes adds the endpoints used by the --edit mode editor. Its
+// live preview uses /api/render, which serve always provides.
+func registerEditRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/file", handleEditFile)
+}
+
+// printEditStatus reports whether --edit is on, warning when the server is
+// reachable from other machines
+func printEditStatus() {
+	if !serveEdit {
+		return
+	}
+	fmt.Printf("✏️  Editing: enabled\n")
+	if serveBind != "localhost" && serveBind != "127.0.0.1" && serveBind != "::1" {
+		fmt.Fprintf(os.Stderr, "Warning: --edit lets anyone who can reach %s modify your files\n", serveBind)
+	}
+}
+
+// resolveEditPath maps an editor path to the file on disk and the
+// modification time of the rendered copy. In directory mode the path must
+// name a served Markdown file inside baseDir, after resolving symlinks.
+// Symlinks are resolved in the returned path, so saving writes through to
+// the target instead of replacing the link.
+func resolveEditPath(rel string) (string, time.Time, error) {
+	if !isDirectoryMode {
+		if rel != "" {
+			return "", time.Time{}, errEditNotFound
+		}
+		resolved, err := filepath.EvalSymlinks(currentFile)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return resolved, currentCached().ModTime, nil
+	}
+
+	if rel == "" || strings.ContainsRune(rel, 0) || strings.Contains(rel, `\`) ||
+		path.IsAbs(rel) || path.Clean(rel) != rel || strings.HasPrefix(rel, "../") || rel == ".." {
+		return "", time.Time{}, errEditForbidden
+	}
+
+	fileCacheMu.RLock()
+	cached, ok := fileCache[rel]
+	fileCacheMu.RUnlock()
+	if !ok {
+		return "", time.Time{}, errEditNotFound
+	}
+
+	abs := filepath.Join(baseDir, filepath.FromSlash(rel))
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", time.Time{}, errEditNotFound
+	}
+	root, err := filepath.EvalSymlinks(baseDir)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if inside, err := filepath.Rel(root, resolved); err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", time.Time{}, errEditForbidden
+	}
+
+	return resolved, cached.ModTime, nil
+}
+
+// handleEditFile serves the Markdown source of a document on GET and saves
+// it on POST. A save is rejected with 409 Conflict when the file changed
+// since the version the editor loaded, unless Force is set. Saves must come
+// from the editor page itself, see checkEditOrigin.
+func handleEditFile(w http.ResponseWriter, r *http.Request) {
+	if err := checkEditHost(r); err != nil {
+		writeEditError(w, err)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		rel := r.URL.Query().Get("path")
+		filename, modTime, err := resolveEditPath(rel)
+		if err != nil {
+			writeEditError(w, err)
+			return
+		}
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			writeEditError(w, err)
+			return
+		}
+		writeEditJSON(w, http.StatusOK, editFile{Path: rel, Content: string(content), ModTime: modTime.UnixNano()})
+
+	case http.MethodPost:
+		if err := checkEditOrigin(r); err != nil {
+			writeEditError(w, err)
+			return
+		}
+		var req editFile
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEditSize)).Decode(&req); err != nil {
+			writeEditJSON(w, http.StatusBadRequest, editFile{Error: "invalid request: " + err.Error()})
+			return
+		}
+		saveEditFile(w, req)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// checkEditHost rejects requests whose Host header names neither the bind
+// address nor a loopback address on the server's port. A DNS rebinding
+// page reaches the server under its own host name, which this refuses.
+func checkEditHost(r *http.Request) error {
+	port := fmt.Sprint(servePort)
+	for _, host := range []string{serveBind, "localhost", "127.0.0.1", "::1"} {
+		if strings.EqualFold(r.Host, net.JoinHostPort(host, port)) {
+			return nil
+		}
+	}
+	return errEditHost
+}
+
+// checkEditOrigin rejects saves that other sites could make through the
+// browser. Requiring a JSON body means a cross-site fetch needs a CORS
+// preflight, which the server never grants, and plain forms cannot send
+// one; the Origin and Fetch Metadata headers are checked as well for
+// browsers that send them.
+func checkEditOrigin(r *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return errEditOrigin
+	}
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" && site != "none" {
+		return errEditOrigin
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return errEditOrigin
+		}
+	}
+	return nil
+}
+
+func saveEditFile(w http.ResponseWriter, req editFile) {
+	editMu.Lock()
+	defer editMu.Unlock()
+
+	filename, modTime, err := resolveEditPath(req.Path)
+	if err != nil {
+		writeEditError(w, err)
+		return
+	}
+
+	// The file may have changed on disk before the watcher re-rendered it
+	stat, err := os.Stat(filename)
+	if err != nil {
+		writeEditError(w, err)
+		return
+	}
+	if !req.Force && (req.ModTime != modTime.UnixNano() || !stat.ModTime().Equal(modTime)) {
+		writeEditJSON(w, http.StatusConflict, editFile{
+			Path:    req.Path,
+			ModTime: stat.ModTime().UnixNano(),
+			Error:   "the file changed on disk since it was opened",
+		})
+		return
+	}
+
+	if err := writeFileAtomic(filename, []byte(req.Content)); err != nil {
+		writeEditError(w, err)
+		return
+	}
+
+	// Update the cache now rather than waiting for the watcher
+	if isDirectoryMode {
+		err = renderSingleCachedFile(req.Path)
+	} else {
+		err = renderCurrentFile()
+	}
+	if err != nil {
+		writeEditError(w, err)
+		return
+	}
+	_, modTime, err = resolveEditPath(req.Path)
+	if err != nil {
+		writeEditError(w, err)
+		return
+	}
+	liveEvents.publish(changeEvent{Path: req.Path, ModTime: modTime.Unix()})
+
+	writeEditJSON(w, http.StatusOK, editFile{Path: req.Path, ModTime: modTime.UnixNano()})
+}
+
+// writeFileAtomic replaces filename with data through a temporary file in
+// the same directory, keeping the file's permissions. filename must not be
+// a symlink, or the rename replaces the link itself.
+func writeFileAtomic(filename string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func writeEditError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errEditForbidden), errors.Is(err, errEditOrigin), errors.Is(err, errEditHost):
+		status = http.StatusForbidden
+	case errors.Is(err, errEditNotFound), os.IsNotExist(err):
+		status = http.StatusNotFound
+	}
+	writeEditJSON(w, status, editFile{Error: err.Error()})
+}
+
+func writeEditJSON(w http.ResponseWriter, status int, body editFile) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
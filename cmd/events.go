@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// changeEvent is sent to preview pages when a document changes
+type changeEvent struct {
+	// Path is the changed file relative to the served directory, empty in
+	// single-file mode
+	Path string `json:"path"`
+	// Tree is set when files were added or removed
+	Tree    bool  `json:"tree"`
+	Removed bool  `json:"removed"`
+	ModTime int64 `json:"modTime"`
+}
+
+// eventBroker fans change events out to the connected /events clients
+type eventBroker struct {
+	mu      sync.Mutex
+	clients map[chan changeEvent]bool
+}
+
+var liveEvents = &eventBroker{clients: make(map[chan changeEvent]bool)}
+
+func (b *eventBroker) subscribe() chan changeEvent {
+	ch := make(chan changeEvent, 16)
+	b.mu.Lock()
+	b.clients[ch] = true
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *eventBroker) unsubscribe(ch chan changeEvent) {
+	b.mu.Lock()
+	delete(b.clients, ch)
+	b.mu.Unlock()
+}
+
+// publish sends ev to every client. Slow clients miss events rather than
+// blocking the watcher.
+func (b *eventBroker) publish(ev changeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// ServeHTTP streams events to a client using Server-Sent Events
+func (b *eventBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+
+	// Ask clients to reconnect quickly after a server restart
+	fmt.Fprint(w, "retry: 1000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: change\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
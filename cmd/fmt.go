@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/format"
+	"github.com/tacheraSasi/mdcli/renderer"
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [files-or-directories...]",
+	Short: "Format Markdown files",
+	Long: `Rewrite Markdown documents in one consistent style: "-" bullets, "1."
+numbering, "*" emphasis and "**" strong text, fenced code blocks, aligned
+tables and one blank line between blocks. Heading text, code, math,
+diagrams, HTML and front matter are kept as written.
+
+Files are rewritten in place; directories are searched recursively,
+skipping ignored files. Without arguments the document on stdin is
+written to stdout. A file is left alone if formatting it would change its
+rendered HTML.
+
+With --width, paragraphs are wrapped at that many columns; by default
+their line breaks are kept. --check and --diff only report the files
+that need formatting and exit with status 1 when there are any.
+
+Examples:
+  mdcli fmt                           # Format stdin to stdout
+  mdcli fmt README.md docs/           # Format files in place
+  mdcli fmt docs/ --width 80          # Also wrap paragraphs
+  mdcli fmt docs/ --check             # List unformatted files (CI)
+  mdcli fmt docs/ --diff              # Show the changes as a diff`,
+	Run: runFmt,
+}
+
+var (
+	fmtWidth int
+	fmtCheck bool
+	fmtDiff  bool
+)
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+
+	fmtCmd.Flags().IntVarP(&fmtWidth, "width", "w", 0, "Wrap paragraphs at this width (0 keeps line breaks)")
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "List files that are not formatted instead of rewriting them")
+	fmtCmd.Flags().BoolVar(&fmtDiff, "diff", false, "Print a diff of the changes instead of rewriting files")
+}
+
+func runFmt(cmd *cobra.Command, args []string) {
+	opts := format.Options{Width: fmtWidth}
+	if !cmd.Flags().Changed("width") && viper.IsSet("fmt.width") {
+		opts.Width = viper.GetInt("fmt.width")
+	}
+
+	if len(args) == 0 {
+		formatStdin(opts)
+		return
+	}
+
+	files, err := expandMarkdownArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	changed := 0
+	failed := false
+	for _, file := range files {
+		content, err := renderer.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+			failed = true
+			continue
+		}
+		formatted, err := format.Source(content, opts)
+		if err != nil {
+			if errors.Is(err, format.ErrChangesOutput) {
+				err = fmt.Errorf("%w; file left unchanged", err)
+			}
+			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", file, err)
+			failed = true
+			continue
+		}
+		if formatted == content {
+			continue
+		}
+		changed++
+
+		switch {
+		case fmtDiff:
+			fmt.Print(format.Diff("a/"+file, "b/"+file, content, formatted))
+		case fmtCheck:
+			fmt.Println(file)
+		default:
+			if err := writeFileAtomic(file, []byte(formatted)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", file, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("✅ Formatted %s\n", file)
+		}
+	}
+
+	if fmtCheck || fmtDiff {
+		if changed > 0 {
+			fmt.Fprintf(os.Stderr, "\n❌ %d of %d %s not formatted\n", changed, len(files), plural(len(files), "file"))
+		} else if !failed {
+			fmt.Fprintf(os.Stderr, "✅ All %d %s formatted\n", len(files), plural(len(files), "file"))
+		}
+	}
+	if failed || ((fmtCheck || fmtDiff) && changed > 0) {
+		os.Exit(1)
+	}
+}
+
+// formatStdin formats the document on stdin to stdout
+func formatStdin(opts format.Options) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+		os.Exit(1)
+	}
+	formatted, err := format.Source(string(content), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case fmtDiff:
+		fmt.Print(format.Diff("<stdin>", "<stdin>", string(content), formatted))
+	case fmtCheck:
+		if formatted != string(content) {
+			fmt.Println("<stdin>")
+		}
+	default:
+		fmt.Print(formatted)
+		return
+	}
+	if formatted != string(content) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/ignore"
+)
+
+// defaultIgnorePatterns keep tooling and build directories out of the
+// preview and site commands. They are applied before the user's patterns,
+// so they can be re-included with a negated pattern such as "!build/".
+var defaultIgnorePatterns = []string{
+	".*/",
+	"node_modules/",
+	"vendor/",
+	"batch_output/",
+	"build/",
+	"bin/",
+	"__pycache__/",
+}
+
+// loadIgnoreMatcher combines the ignore_patterns config key and the
+// .gitignore / .mdcliignore files found below root, after the default
+// patterns when defaults is set. batch leaves the defaults out, so it still
+// converts every file that is not explicitly ignored.
+func loadIgnoreMatcher(root string, defaults bool) *ignore.Matcher {
+	var patterns []string
+	if defaults {
+		patterns = append(patterns, defaultIgnorePatterns...)
+	}
+	patterns = append(patterns, viper.GetStringSlice("ignore_patterns")...)
+
+	matcher, err := ignore.Load(root, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read ignore files: %v\n", err)
+	}
+	return matcher
+}
+
+// ignoreFileChanged reports whether path is one of the ignore files
+func ignoreFileChanged(path string) bool {
+	name := filepath.Base(path)
+	for _, f := range ignore.IgnoreFiles {
+		if name == f {
+			return true
+		}
+	}
+	return false
+}
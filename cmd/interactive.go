@@ -89,7 +89,7 @@ func runInteractive(cmd *cobra.Command, args []string) {
 		}
 
 		// Render the content
-		rendered, err := renderer.Render(renderer.RenderOptions{
+		doc, err := renderer.Render(renderer.RenderOptions{
 			Input:        content,
 			Autolink:     true,
 			Theme:        interactiveTheme,
@@ -105,7 +105,7 @@ func runInteractive(cmd *cobra.Command, args []string) {
 		fmt.Println("\n" + strings.Repeat("=", 40))
 		fmt.Println("📄 Rendered Output:")
 		fmt.Println(strings.Repeat("=", 40))
-		fmt.Println(rendered)
+		fmt.Println(doc.Output)
 		fmt.Println(strings.Repeat("-", 40))
 	}
 }
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/lint"
+	"github.com/tacheraSasi/mdcli/renderer"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [files-or-directories...]",
+	Short: "Check Markdown files against style rules",
+	Long: `Check Markdown documents against a set of style rules and report every
+violation as file:line:col. Directories are searched recursively, skipping
+ignored files. The command exits with status 1 when anything is reported.
+
+Rules are configured under lint.rules in the config file: set a rule to
+false to turn it off, or to a map of its options. Inside a document,
+comments turn rules off for a region or a single line:
+
+  <!-- mdcli-lint-disable line-length -->
+  <!-- mdcli-lint-enable line-length -->
+  <!-- mdcli-lint-disable-next-line duplicate-heading -->
+
+Examples:
+  mdcli lint                          # Lint the current directory
+  mdcli lint README.md docs/          # Lint files and directories
+  mdcli lint docs/ --width 100        # Allow lines of 100 characters
+  mdcli lint docs/ --format sarif     # SARIF for code scanning
+  mdcli lint --list-rules             # Show the available rules`,
+	Run: runLint,
+}
+
+var (
+	lintFormat    string
+	lintWidth     int
+	lintDisable   []string
+	lintListRules bool
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "text", "Output format (text, json, sarif)")
+	lintCmd.Flags().IntVarP(&lintWidth, "width", "w", 80, "Maximum line length")
+	lintCmd.Flags().StringSliceVar(&lintDisable, "disable", nil, "Rules to turn off (repeatable)")
+	lintCmd.Flags().BoolVar(&lintListRules, "list-rules", false, "List the available rules and exit")
+}
+
+func runLint(cmd *cobra.Command, args []string) {
+	if lintListRules {
+		for _, rule := range lint.Rules {
+			fmt.Printf("%-20s %s\n", rule.ID, rule.Description)
+		}
+		return
+	}
+	if lintFormat != "text" && lintFormat != "json" && lintFormat != "sarif" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s' (use text, json or sarif)\n", lintFormat)
+		os.Exit(1)
+	}
+
+	cfg := lint.Config{Width: lintWidth, Rules: viper.GetStringMap("lint.rules")}
+	if cfg.Rules == nil {
+		cfg.Rules = make(map[string]interface{})
+	}
+	for _, id := range lintDisable {
+		cfg.Rules[id] = false
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := expandMarkdownArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	diagnostics := []lint.Diagnostic{}
+	failed := false
+	for _, file := range files {
+		content, err := renderer.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+			failed = true
+			continue
+		}
+		found, err := lint.Lint(file, content, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error linting %s: %v\n", file, err)
+			failed = true
+			continue
+		}
+		diagnostics = append(diagnostics, found...)
+	}
+
+	switch lintFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(diagnostics)
+	case "sarif":
+		if err := lint.WriteSARIF(os.Stdout, diagnostics, version); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing SARIF: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		for _, d := range diagnostics {
+			fmt.Printf("%s:%d:%d: %s: %s\n", d.File, d.Line, d.Column, d.Rule, d.Message)
+		}
+		if len(diagnostics) > 0 {
+			fmt.Printf("\n❌ %d %s in %d files\n", len(diagnostics), plural(len(diagnostics), "problem"), len(files))
+		} else {
+			fmt.Printf("✅ No problems in %d files\n", len(files))
+		}
+	}
+
+	if failed || len(diagnostics) > 0 {
+		os.Exit(1)
+	}
+}
+
+// plural appends an s to word unless n is 1
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/tacheraSasi/mdcli/themes"
+	"github.com/yuin/goldmark/ast"
+)
+
+// manifestFile is written to the batch output directory
+const manifestFile = ".mdcli-manifest.json"
+
+// batchManifest records how every output of a batch run was produced, so
+// unchanged inputs can be skipped on the next run
+type batchManifest struct {
+	Version string `json:"version"`
+	// Outputs is keyed by output path relative to the output directory
+	Outputs map[string]manifestEntry `json:"outputs"`
+}
+
+// manifestEntry describes a single output file
+type manifestEntry struct {
+	Source string `json:"source"`
+	Hash   string `json:"hash"`
+	// Deps is the hash of the local files the output embeds, such as the
+	// images of a PDF
+	Deps    string       `json:"deps,omitempty"`
+	Options batchOptions `json:"options"`
+	Version string       `json:"version"`
+}
+
+// batchOptions are the render options that affect an output
+type batchOptions struct {
+	Format string `json:"format"`
+	Theme  string `json:"theme"`
+	// ThemeHash covers the theme's colors and syntax style, which a user
+	// theme can change without changing its name
+	ThemeHash string `json:"themeHash,omitempty"`
+	Width     int    `json:"width"`
+	Autolink  bool   `json:"autolink"`
+	MathML    bool   `json:"mathml,omitempty"`
+}
+
+// loadManifest reads the manifest of outputDir. A missing or unreadable
+// manifest yields an empty one, which rebuilds everything.
+func loadManifest(outputDir string) *batchManifest {
+	m := &batchManifest{Outputs: make(map[string]manifestEntry)}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, manifestFile))
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(data, m); err != nil || m.Outputs == nil {
+		return &batchManifest{Outputs: make(map[string]manifestEntry)}
+	}
+	return m
+}
+
+// save writes the manifest to outputDir
+func (m *batchManifest) save(outputDir string) error {
+	m.Version = version
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, manifestFile), append(data, '\n'), 0644)
+}
+
+// upToDate reports whether output was already built from the same input,
+// options and mdcli version
+func (m *batchManifest) upToDate(outputDir, output string, want manifestEntry) bool {
+	entry, ok := m.Outputs[output]
+	if !ok || entry != want {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(output)))
+	return err == nil
+}
+
+// prune removes the outputs not in keep from disk and from the manifest,
+// then removes directories left empty. It returns the pruned outputs.
+func (m *batchManifest) prune(outputDir string, keep map[string]bool) []string {
+	var pruned []string
+	for output := range m.Outputs {
+		if keep[output] {
+			continue
+		}
+		if removeOutput(outputDir, output) != nil {
+			continue
+		}
+		delete(m.Outputs, output)
+		pruned = append(pruned, output)
+	}
+	sort.Strings(pruned)
+	return pruned
+}
+
+// removeOutput deletes the slash separated output below outputDir, then
+// the directories it leaves empty. A missing output is not an error.
+func removeOutput(outputDir, output string) error {
+	path := filepath.Join(outputDir, filepath.FromSlash(output))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for dir := filepath.Dir(path); dir != filepath.Clean(outputDir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// hashContent returns the hex SHA-256 of an input file's content
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashTheme returns the hex SHA-256 of the named theme's definition, or ""
+// for an unknown theme
+func hashTheme(name string) string {
+	theme, err := themes.GetTheme(name)
+	if err != nil {
+		return ""
+	}
+	data, err := json.Marshal(theme)
+	if err != nil {
+		return ""
+	}
+	return hashContent(string(data))
+}
+
+// hashImages returns the hex SHA-256 of the local images content references,
+// resolved against baseDir, or "" when there are none. A missing image is
+// hashed too, so adding it later rebuilds the output.
+func hashImages(content, baseDir string) string {
+	doc, err := renderer.Parse(content)
+	if err != nil {
+		return ""
+	}
+
+	var files []string
+	ast.Walk(doc.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		img, ok := n.(*ast.Image)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		u, err := url.Parse(string(img.Destination))
+		// PDFs only embed images inside the document's directory
+		if err != nil || u.Scheme != "" || u.Host != "" || !filepath.IsLocal(filepath.FromSlash(u.Path)) {
+			return ast.WalkContinue, nil
+		}
+		files = append(files, filepath.Join(baseDir, filepath.FromSlash(u.Path)))
+		return ast.WalkContinue, nil
+	})
+	if len(files) == 0 {
+		return ""
+	}
+
+	sort.Strings(files)
+	h := sha256.New()
+	for _, file := range files {
+		fmt.Fprintf(h, "%s\x00", file)
+		if data, err := os.ReadFile(file); err == nil {
+			fmt.Fprintf(h, "%x\x00", sha256.Sum256(data))
+		} else {
+			h.Write([]byte("missing\x00"))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
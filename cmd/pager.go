@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/pager"
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/yuin/goldmark/ast"
+	"golang.org/x/term"
+)
+
+// usePager reports whether render should page its terminal output: one
+// file shown on an interactive terminal, unless turned off
+func usePager(cmd *cobra.Command, filenames []string) bool {
+	if noPager || (!cmd.Flags().Changed("no-pager") && viper.IsSet("render.pager") && !viper.GetBool("render.pager")) {
+		return false
+	}
+	switch outputFormat {
+	case "html", "pdf", "text", "plain":
+		return false
+	}
+	return outputFile == "" && len(filenames) == 1 && filenames[0] != "stdin" &&
+		term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// pageFile shows a file in the pager, or prints it when it fits on the
+// screen
+func pageFile(file string) {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		cols, rows = width, 24
+	}
+	page, err := loadPagerPage(file, cols)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", file, err)
+		os.Exit(1)
+	}
+	if len(page.Lines) < rows {
+		fmt.Println(strings.Join(page.Lines, "\n"))
+		return
+	}
+
+	if err := pager.Run(file, loadPagerPage); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadPagerPage renders a file for the terminal at the given width, with
+// its headings and links for navigation
+func loadPagerPage(file string, cols int) (*pager.Page, error) {
+	content, err := renderer.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := renderer.Render(renderer.RenderOptions{
+		Input:        content,
+		Autolink:     autolink,
+		Theme:        theme,
+		Width:        cols,
+		OutputFormat: "terminal",
+		BaseDir:      filepath.Dir(file),
+	})
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := renderer.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []pager.Link
+	ast.Walk(parsed.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*ast.Link)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		var text strings.Builder
+		ast.Walk(link, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+			if t, ok := c.(*ast.Text); ok && entering {
+				text.Write(t.Segment.Value(parsed.Source))
+				if t.SoftLineBreak() {
+					text.WriteByte(' ')
+				}
+			}
+			return ast.WalkContinue, nil
+		})
+		links = append(links, pager.Link{Text: text.String(), Target: string(link.Destination)})
+		return ast.WalkSkipChildren, nil
+	})
+
+	return pager.NewPage(doc.Title(""), doc.Output, doc.Outline, links), nil
+}
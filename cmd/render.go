@@ -19,7 +19,21 @@ var renderCmd = &cobra.Command{
 	Short: "Render Markdown files to various formats",
 	Long: `Render one or more Markdown files to the specified output format.
 Supports terminal output (default), HTML, PDF, and plain text formats.
-Can process multiple files and supports stdin input.`,
+Can process multiple files and supports stdin input.
+
+A single file rendered to an interactive terminal opens in a pager when it
+is longer than the screen: less and vim keys scroll, / searches, o shows
+the outline and Tab/Enter follow links to other Markdown files. Press h
+in the pager for all keys, or use --no-pager to print instead.
+
+With --standalone, HTML output is a complete page that looks like the
+serve preview, with its styles, scripts and local images embedded, so
+the single file can be shared and opened offline.
+
+Examples:
+  mdcli render README.md                             # Render in the terminal
+  mdcli render README.md -f html -o out.html         # HTML fragment
+  mdcli render README.md --standalone -o out.html    # Self-contained page`,
 	Args: cobra.ArbitraryArgs,
 	Run:  runRender,
 }
@@ -31,6 +45,9 @@ var (
 	width        int
 	autolink     bool
 	showProgress bool
+	standalone   bool
+	noPager      bool
+	mathML       bool
 )
 
 func init() {
@@ -42,6 +59,9 @@ func init() {
 	renderCmd.Flags().IntVarP(&width, "width", "w", 0, "Terminal width for formatting")
 	renderCmd.Flags().BoolVar(&autolink, "autolink", true, "Enable automatic link detection")
 	renderCmd.Flags().BoolVar(&showProgress, "progress", false, "Show progress bar")
+	renderCmd.Flags().BoolVar(&standalone, "standalone", false, "Write a self-contained HTML page with embedded styles, scripts and images")
+	renderCmd.Flags().BoolVar(&noPager, "no-pager", false, "Print terminal output instead of opening the pager")
+	renderCmd.Flags().BoolVar(&mathML, "mathml", false, "Convert math to MathML in HTML output instead of leaving it for MathJax")
 
 	// Bind flags to viper
 	viper.BindPFlag("output", renderCmd.Flags().Lookup("output"))
@@ -68,6 +88,16 @@ func runRender(cmd *cobra.Command, args []string) {
 	if !cmd.Flags().Changed("autolink") {
 		autolink = viper.GetBool("autolink")
 	}
+	if !cmd.Flags().Changed("mathml") {
+		mathML = viper.GetBool("render.mathml")
+	}
+	if standalone {
+		if cmd.Flags().Changed("format") && outputFormat != "html" {
+			fmt.Fprintf(os.Stderr, "Error: --standalone only applies to HTML output\n")
+			os.Exit(1)
+		}
+		outputFormat = "html"
+	}
 
 	var inputs []string
 	var filenames []string
@@ -102,6 +132,11 @@ func runRender(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if usePager(cmd, filenames) {
+		pageFile(filenames[0])
+		return
+	}
+
 	// Setup progress bar if requested
 	var bar *progressbar.ProgressBar
 	if showProgress && len(inputs) > 1 {
@@ -114,27 +149,61 @@ func runRender(cmd *cobra.Command, args []string) {
 	}
 
 	var renderedAll []string
-	for idx, input := range inputs {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Processing: %s\n", filenames[idx])
+	var title string
+	var outline []renderer.Heading
+	if outputFormat == "pdf" {
+		// PDFs can't be joined as text, so all inputs go into one document
+		sources := make([]renderer.PDFSource, len(inputs))
+		for idx, input := range inputs {
+			sources[idx] = renderer.PDFSource{Input: input, BaseDir: inputBaseDir(filenames[idx])}
 		}
 
-		rendered, err := renderer.Render(renderer.RenderOptions{
-			Input:        input,
-			Autolink:     autolink,
-			Theme:        theme,
-			Width:        width,
-			OutputFormat: outputFormat,
-		})
+		rendered, err := renderer.RenderPDF(renderer.RenderOptions{
+			Autolink: autolink,
+			Theme:    theme,
+			Width:    width,
+		}, sources...)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", filenames[idx], err)
+			fmt.Fprintf(os.Stderr, "Error rendering PDF: %v\n", err)
 			os.Exit(1)
 		}
-		renderedAll = append(renderedAll, rendered)
+		renderedAll = append(renderedAll, string(rendered))
+	} else {
+		for idx, input := range inputs {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Processing: %s\n", filenames[idx])
+			}
+
+			doc, err := renderer.Render(renderer.RenderOptions{
+				Input:        input,
+				Autolink:     autolink,
+				Theme:        theme,
+				Width:        width,
+				OutputFormat: outputFormat,
+				BaseDir:      inputBaseDir(filenames[idx]),
+				// The standalone page brings its own scripts and syntax CSS
+				NoScripts:        standalone,
+				HighlightClasses: standalone,
+				MathML:           mathML,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", filenames[idx], err)
+				os.Exit(1)
+			}
+			warnFrontMatter(filenames[idx], doc.FrontMatter)
+			if standalone {
+				if idx == 0 {
+					title = doc.Title(strings.TrimSuffix(filepath.Base(filenames[idx]), filepath.Ext(filenames[idx])))
+				}
+				outline = append(outline, doc.Outline...)
+				doc.Output = inlineImages(doc.Output, inputBaseDir(filenames[idx]))
+			}
+			renderedAll = append(renderedAll, doc.Output)
 
-		if bar != nil {
-			bar.Add(1)
-			time.Sleep(10 * time.Millisecond) // Small delay for better UX
+			if bar != nil {
+				bar.Add(1)
+				time.Sleep(10 * time.Millisecond) // Small delay for better UX
+			}
 		}
 	}
 
@@ -146,13 +215,20 @@ func runRender(cmd *cobra.Command, args []string) {
 		switch outputFormat {
 		case "html":
 			outputStr = strings.Join(renderedAll, "\n<hr>\n")
-		case "pdf":
-			outputStr = strings.Join(renderedAll, "\n\n---\n\n")
 		default:
 			outputStr = strings.Join(renderedAll, "\n\n---\n\n")
 		}
 	}
 
+	if standalone {
+		page, err := standalonePage(outputStr, title, theme, outline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building standalone page: %v\n", err)
+			os.Exit(1)
+		}
+		outputStr = page
+	}
+
 	// Output the result
 	if outputFile != "" {
 		// Ensure output directory exists
@@ -177,6 +253,21 @@ func runRender(cmd *cobra.Command, args []string) {
 	}
 }
 
+// inputBaseDir returns the directory relative links of an input resolve against
+func inputBaseDir(filename string) string {
+	if filename == "stdin" {
+		return "."
+	}
+	return filepath.Dir(filename)
+}
+
+// warnFrontMatter prints the front matter values of name that were ignored
+func warnFrontMatter(name string, fm renderer.FrontMatter) {
+	for _, warning := range fm.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", name, warning)
+	}
+}
+
 // runRenderWithFlags is a helper function that allows rendering with explicit flag values
 // This is used by the root command for backward compatibility
 func runRenderWithFlags(cmd *cobra.Command, args []string, output, format, thm string, w int, autoLnk, progress bool) {
@@ -78,7 +78,7 @@ func runRootCommand(cmd *cobra.Command, args []string) {
 
 // isSubcommand checks if a string matches any known subcommand
 func isSubcommand(arg string) bool {
-	subcommands := []string{"render", "serve", "watch", "batch", "interactive", "themes", "config", "help", "completion"}
+	subcommands := []string{"render", "serve", "watch", "batch", "site", "api", "check", "lint", "fmt", "slides", "interactive", "themes", "config", "help", "completion"}
 	for _, sub := range subcommands {
 		if arg == sub {
 			return true
@@ -149,6 +149,9 @@ func initConfig() {
 	viper.SetDefault("serve.auto_reload", true)
 	viper.SetDefault("render.show_progress", true)
 	viper.SetDefault("render.include_metadata", false)
+	viper.SetDefault("render.mathml", false)
 	viper.SetDefault("watch.debounce_delay", 100)
 	viper.SetDefault("watch.clear_screen", true)
+
+	loadUserThemes()
 }
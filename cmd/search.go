@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/tacheraSasi/mdcli/search"
+)
+
+// searchIndex is the full-text index of fileCache, guarded by fileCacheMu
+var searchIndex = search.New()
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// searchResponse is the JSON body returned by /search
+type searchResponse struct {
+	Query   string          `json:"query"`
+	Total   int             `json:"total"`
+	Results []search.Result `json:"results"`
+}
+
+// buildSearchIndex indexes every file of cache
+func buildSearchIndex(cache map[string]*CachedFile) *search.Index {
+	index := search.New()
+	for relPath, cached := range cache {
+		index.Add(relPath, cached.Title, search.SectionsFromHTML(cached.Content))
+	}
+	return index
+}
+
+// handleSearch answers /search?q=<query>[&limit=n] with ranked results.
+// Quoted words match as a phrase and the last word matches as a prefix.
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	limit := defaultSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxSearchLimit)
+	}
+
+	fileCacheMu.RLock()
+	index := searchIndex
+	fileCacheMu.RUnlock()
+
+	results, total := index.Search(query, limit)
+	if results == nil {
+		results = []search.Result{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(searchResponse{Query: query, Total: total, Results: results})
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -16,7 +17,11 @@ import (
 	"github.com/a-h/templ"
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/ignore"
 	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/tacheraSasi/mdcli/search"
+	"github.com/tacheraSasi/mdcli/themes"
 	views "github.com/tacheraSasi/mdcli/ui"
 )
 
@@ -34,7 +39,8 @@ Perfect for real-time document editing and review.
 Examples:
   mdcli serve README.md          # Serve a single file
   mdcli serve .                  # Serve all .md files in current directory
-  mdcli serve docs/              # Serve all .md files in docs/ recursively`,
+  mdcli serve docs/              # Serve all .md files in docs/ recursively
+  mdcli serve docs/ --edit       # Edit documents in the browser`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runServe,
 }
@@ -45,6 +51,8 @@ var (
 	serveWidth  int
 	serveBind   string
 	serveReload bool
+	serveEdit   bool
+	serveMathML bool
 )
 
 func init() {
@@ -55,15 +63,19 @@ func init() {
 	serveCmd.Flags().IntVarP(&serveWidth, "width", "w", 80, "Content width")
 	serveCmd.Flags().StringVarP(&serveBind, "bind", "b", "localhost", "Bind address")
 	serveCmd.Flags().BoolVar(&serveReload, "auto-reload", true, "Enable auto-reload on file changes")
+	serveCmd.Flags().BoolVar(&serveEdit, "edit", false, "Allow editing documents in the browser")
+	serveCmd.Flags().BoolVar(&serveMathML, "mathml", false, "Convert math to MathML instead of leaving it for MathJax")
 }
 
 type PreviewData = views.ServeData
 
 // --- Single-file mode state ---
 var (
-	currentFile   string
-	lastModTime   time.Time
-	cachedContent string
+	currentFile string
+	// currentCache is the rendered copy of currentFile, replaced whole by
+	// the watcher and by saves from the editor
+	currentCache   *CachedFile
+	currentCacheMu sync.RWMutex
 )
 
 // --- Directory mode state ---
@@ -74,24 +86,15 @@ var (
 	fileCacheMu     sync.RWMutex
 	fileTree        []views.FileEntry
 	globalModTime   time.Time
+	ignoreMatcher   *ignore.Matcher
 )
 
-// CachedFile stores the rendered HTML and modification time for a single file.
+// CachedFile stores the rendered HTML, title and modification time for a single file.
 type CachedFile struct {
 	Content string
+	Title   string
 	ModTime time.Time
-}
-
-// Directories to skip when scanning.
-var skipDirs = map[string]bool{
-	"node_modules": true,
-	"vendor":       true,
-	".git":         true,
-	"batch_output": true,
-	"build":        true,
-	"bin":          true,
-	".github":      true,
-	"__pycache__":  true,
+	Outline []renderer.Heading
 }
 
 func runServe(cmd *cobra.Command, args []string) {
@@ -99,6 +102,9 @@ func runServe(cmd *cobra.Command, args []string) {
 	if len(args) > 0 {
 		target = args[0]
 	}
+	if !cmd.Flags().Changed("mathml") {
+		serveMathML = viper.GetBool("render.mathml")
+	}
 
 	stat, err := os.Stat(target)
 	if os.IsNotExist(err) {
@@ -139,21 +145,27 @@ func runServeSingleFile(cmd *cobra.Command, file string) {
 
 	assetsSubFS, _ := fs.Sub(AssetsFS, "assets")
 	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(assetsSubFS))))
+	mux.HandleFunc("/assets/syntax/", handleSyntaxCSS)
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		cached := currentCached()
 		data := views.ServeData{
-			Title:      filepath.Base(currentFile),
-			Content:    cachedContent,
-			ThemeName:  serveTheme,
-			AutoReload: serveReload,
+			Title:        cached.Title,
+			Content:      cached.Content,
+			ThemeName:    serveTheme,
+			AutoReload:   serveReload,
+			Editable:     serveEdit,
+			LastModified: cached.ModTime.Format("15:04:05"),
+			Outline:      cached.Outline,
 		}
 		templ.Handler(views.ServePage(data)).ServeHTTP(w, r)
 	})
 
-	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, `{"lastModified": %d}`, lastModTime.Unix())
-	})
+	mux.Handle("/events", liveEvents)
+	mux.Handle("/api/render", newPreviewAPI())
+	if serveEdit {
+		registerEditRoutes(mux)
+	}
 
 	addr := fmt.Sprintf("%s:%d", serveBind, servePort)
 	fmt.Printf("🚀 Starting live preview server...\n")
@@ -163,6 +175,7 @@ func runServeSingleFile(cmd *cobra.Command, file string) {
 	if serveReload {
 		fmt.Printf("🔄 Auto-reload: enabled\n")
 	}
+	printEditStatus()
 	fmt.Printf("Press Ctrl+C to stop\n\n")
 
 	if err := http.ListenAndServe(addr, mux); err != nil {
@@ -171,35 +184,63 @@ func runServeSingleFile(cmd *cobra.Command, file string) {
 	}
 }
 
+// currentCached returns the rendered copy of the served file
+func currentCached() *CachedFile {
+	currentCacheMu.RLock()
+	defer currentCacheMu.RUnlock()
+	return currentCache
+}
+
 func renderCurrentFile() error {
 	stat, err := os.Stat(currentFile)
 	if err != nil {
 		return err
 	}
 
-	lastModTime = stat.ModTime()
-
 	content, err := renderer.ReadFile(currentFile)
 	if err != nil {
 		return err
 	}
 
-	rendered, err := renderer.Render(renderer.RenderOptions{
-		Input:        content,
-		Autolink:     true,
-		Theme:        serveTheme,
-		Width:        serveWidth,
-		OutputFormat: "html",
+	doc, err := renderer.Render(renderer.RenderOptions{
+		Input:            content,
+		Autolink:         true,
+		Theme:            serveTheme,
+		Width:            serveWidth,
+		OutputFormat:     "html",
+		NoScripts:        true,
+		HighlightClasses: true,
+		MathML:           serveMathML,
 	})
 	if err != nil {
 		return err
 	}
+	warnFrontMatter(currentFile, doc.FrontMatter)
 
-	cachedContent = rendered
+	cached := &CachedFile{
+		Content: doc.Output,
+		Title:   doc.Title(filepath.Base(currentFile)),
+		ModTime: stat.ModTime(),
+		Outline: doc.Outline,
+	}
+	currentCacheMu.Lock()
+	currentCache = cached
+	currentCacheMu.Unlock()
 	return nil
 }
 
 func startFileWatcher() {
+	watchFile(currentFile, func() (time.Time, error) {
+		if err := renderCurrentFile(); err != nil {
+			return time.Time{}, err
+		}
+		return currentCached().ModTime, nil
+	})
+}
+
+// watchFile calls reload whenever file changes and tells the open pages,
+// until the watcher fails. reload returns the new modification time.
+func watchFile(file string, reload func() (time.Time, error)) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating file watcher: %v\n", err)
@@ -207,8 +248,10 @@ func startFileWatcher() {
 	}
 	defer watcher.Close()
 
-	absPath, _ := filepath.Abs(currentFile)
-	err = watcher.Add(absPath)
+	// Watch the directory: editors that save atomically replace the file,
+	// which ends a watch on the file itself
+	absPath, _ := filepath.Abs(file)
+	err = watcher.Add(filepath.Dir(absPath))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error watching file: %v\n", err)
 		return
@@ -220,13 +263,19 @@ func startFileWatcher() {
 			if !ok {
 				return
 			}
+			if filepath.Clean(event.Name) != absPath {
+				continue
+			}
 
-			if event.Op&fsnotify.Write == fsnotify.Write {
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
 				time.Sleep(100 * time.Millisecond) // Debounce
-				if err := renderCurrentFile(); err != nil {
+				if modTime, err := reload(); err != nil {
 					fmt.Fprintf(os.Stderr, "Render error: %v\n", err)
-				} else if verbose {
-					fmt.Printf("📝 File updated: %s\n", time.Now().Format("15:04:05"))
+				} else {
+					liveEvents.publish(changeEvent{ModTime: modTime.Unix()})
+					if verbose {
+						fmt.Printf("📝 File updated: %s\n", time.Now().Format("15:04:05"))
+					}
 				}
 			}
 
@@ -251,6 +300,7 @@ func runServeDirectory(cmd *cobra.Command, dir string) {
 	}
 	baseDir = absDir
 	fileCache = make(map[string]*CachedFile)
+	ignoreMatcher = loadIgnoreMatcher(baseDir, true)
 
 	// Initial scan and render all markdown files
 	if err := scanAndRenderDirectory(); err != nil {
@@ -266,15 +316,14 @@ func runServeDirectory(cmd *cobra.Command, dir string) {
 
 	assetsSubFS, _ := fs.Sub(AssetsFS, "assets")
 	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(assetsSubFS))))
+	mux.HandleFunc("/assets/syntax/", handleSyntaxCSS)
 
-	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		fileCacheMu.RLock()
-		modTime := globalModTime
-		fileCacheMu.RUnlock()
-		fmt.Fprintf(w, `{"lastModified": %d}`, modTime.Unix())
-	})
-
+	mux.Handle("/events", liveEvents)
+	mux.HandleFunc("/search", handleSearch)
+	mux.Handle("/api/render", newPreviewAPI())
+	if serveEdit {
+		registerEditRoutes(mux)
+	}
 	mux.HandleFunc("/", handleDirectoryRequest)
 
 	addr := fmt.Sprintf("%s:%d", serveBind, servePort)
@@ -290,6 +339,7 @@ func runServeDirectory(cmd *cobra.Command, dir string) {
 	if serveReload {
 		fmt.Printf("🔄 Auto-reload: enabled\n")
 	}
+	printEditStatus()
 	fmt.Printf("Press Ctrl+C to stop\n\n")
 
 	if err := http.ListenAndServe(addr, mux); err != nil {
@@ -298,6 +348,25 @@ func runServeDirectory(cmd *cobra.Command, dir string) {
 	}
 }
 
+// newPreviewAPI returns the /api/render handler of serve, which renders
+// with the page's theme and width by default
+func newPreviewAPI() *renderAPI {
+	return newRenderAPI(serveTheme, serveWidth, defaultMaxRenderBody, defaultRenderTimeout, runtime.NumCPU(), true)
+}
+
+// handleSyntaxCSS serves the generated code highlighting stylesheet of a
+// theme, e.g. /assets/syntax/dracula.css
+func handleSyntaxCSS(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(filepath.Base(r.URL.Path), ".css")
+	css, err := themes.SyntaxCSS(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	fmt.Fprint(w, css)
+}
+
 // handleDirectoryRequest routes incoming requests to the correct markdown
 // file or shows a directory listing.
 func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
@@ -320,7 +389,7 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 	// 1. Exact match (e.g., /README.md)
 	if cached, ok := cache[urlPath]; ok {
 		content = cached.Content
-		title = filepath.Base(urlPath)
+		title = cached.Title
 		currentPath = urlPath
 		found = true
 	}
@@ -330,7 +399,7 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 		mdPath := urlPath + ".md"
 		if cached, ok := cache[mdPath]; ok {
 			content = cached.Content
-			title = filepath.Base(mdPath)
+			title = cached.Title
 			currentPath = mdPath
 			found = true
 		}
@@ -341,7 +410,7 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 		mdPath := urlPath + ".markdown"
 		if cached, ok := cache[mdPath]; ok {
 			content = cached.Content
-			title = filepath.Base(mdPath)
+			title = cached.Title
 			currentPath = mdPath
 			found = true
 		}
@@ -352,7 +421,7 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 		for _, name := range []string{"README.md", "readme.md", "Readme.md", "index.md", "INDEX.md"} {
 			if cached, ok := cache[name]; ok {
 				content = cached.Content
-				title = name
+				title = cached.Title
 				currentPath = name
 				found = true
 				break
@@ -366,7 +435,7 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 			dirPath := urlPath + "/" + name
 			if cached, ok := cache[dirPath]; ok {
 				content = cached.Content
-				title = name
+				title = cached.Title
 				currentPath = dirPath
 				found = true
 				break
@@ -396,6 +465,15 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 	// Rewrite internal .md links to clean URLs
 	content = rewriteMdLinks(content)
 
+	fileCacheMu.RLock()
+	modTime := globalModTime
+	fileCacheMu.RUnlock()
+	var outline []renderer.Heading
+	if cached, ok := cache[currentPath]; ok {
+		modTime = cached.ModTime
+		outline = cached.Outline
+	}
+
 	data := views.ServeData{
 		Title:           title,
 		Content:         content,
@@ -404,40 +482,69 @@ func handleDirectoryRequest(w http.ResponseWriter, r *http.Request) {
 		IsDirectoryMode: true,
 		CurrentPath:     currentPath,
 		Files:           tree,
+		Editable:        serveEdit && currentPath != "",
+		LastModified:    modTime.Format("15:04:05"),
+		Outline:         outline,
 	}
 	templ.Handler(views.ServePage(data)).ServeHTTP(w, r)
 }
 
 // scanAndRenderDirectory walks baseDir and renders every .md / .markdown file.
 func scanAndRenderDirectory() error {
-	newCache := make(map[string]*CachedFile)
+	newCache, latestMod, err := renderDirectory(baseDir, ignoreMatcher, serveTheme, serveWidth)
+	if err != nil {
+		return err
+	}
+
+	tree := buildFileTree(newCache)
+	index := buildSearchIndex(newCache)
+
+	fileCacheMu.Lock()
+	fileCache = newCache
+	fileTree = tree
+	searchIndex = index
+	globalModTime = latestMod
+	fileCacheMu.Unlock()
+
+	return nil
+}
+
+// renderDirectory renders every Markdown file below dir that matcher does not
+// ignore. It returns the files keyed by slash separated relative path and the
+// latest modification time.
+func renderDirectory(dir string, matcher *ignore.Matcher, theme string, width int) (map[string]*CachedFile, time.Time, error) {
+	cache := make(map[string]*CachedFile)
 	var latestMod time.Time
 
-	err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() {
-			if strings.HasPrefix(info.Name(), ".") && path != baseDir {
-				return filepath.SkipDir
-			}
-			if skipDirs[info.Name()] {
+		relPath := ignore.Clean(dir, path)
+		if matcher.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
+		if info.IsDir() {
+			return nil
+		}
 
 		lower := strings.ToLower(info.Name())
 		if !strings.HasSuffix(lower, ".md") && !strings.HasSuffix(lower, ".markdown") {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(baseDir, path)
-		if err != nil {
-			return err
+		if info.Mode()&os.ModeSymlink != 0 {
+			// Walk reports the link itself; edits and reloads compare
+			// against the target's modification time
+			if info, err = os.Stat(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not read %s: %v\n", relPath, err)
+				return nil
+			}
 		}
-		relPath = filepath.ToSlash(relPath)
 
 		content, err := renderer.ReadFile(path)
 		if err != nil {
@@ -445,21 +552,27 @@ func scanAndRenderDirectory() error {
 			return nil
 		}
 
-		rendered, err := renderer.Render(renderer.RenderOptions{
-			Input:        content,
-			Autolink:     true,
-			Theme:        serveTheme,
-			Width:        serveWidth,
-			OutputFormat: "html",
+		doc, err := renderer.Render(renderer.RenderOptions{
+			Input:            content,
+			Autolink:         true,
+			Theme:            theme,
+			Width:            width,
+			OutputFormat:     "html",
+			NoScripts:        true,
+			HighlightClasses: true,
+			MathML:           serveMathML,
 		})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not render %s: %v\n", relPath, err)
 			return nil
 		}
+		warnFrontMatter(relPath, doc.FrontMatter)
 
-		newCache[relPath] = &CachedFile{
-			Content: rendered,
+		cache[relPath] = &CachedFile{
+			Content: doc.Output,
+			Title:   doc.Title(info.Name()),
 			ModTime: info.ModTime(),
+			Outline: doc.Outline,
 		}
 		if info.ModTime().After(latestMod) {
 			latestMod = info.ModTime()
@@ -467,19 +580,8 @@ func scanAndRenderDirectory() error {
 
 		return nil
 	})
-	if err != nil {
-		return err
-	}
-
-	tree := buildFileTree(newCache)
 
-	fileCacheMu.Lock()
-	fileCache = newCache
-	fileTree = tree
-	globalModTime = latestMod
-	fileCacheMu.Unlock()
-
-	return nil
+	return cache, latestMod, err
 }
 
 // renderSingleCachedFile re-renders one file in the cache (for live-reload).
@@ -496,22 +598,32 @@ func renderSingleCachedFile(relPath string) error {
 		return err
 	}
 
-	rendered, err := renderer.Render(renderer.RenderOptions{
-		Input:        content,
-		Autolink:     true,
-		Theme:        serveTheme,
-		Width:        serveWidth,
-		OutputFormat: "html",
+	doc, err := renderer.Render(renderer.RenderOptions{
+		Input:            content,
+		Autolink:         true,
+		Theme:            serveTheme,
+		Width:            serveWidth,
+		OutputFormat:     "html",
+		NoScripts:        true,
+		HighlightClasses: true,
+		MathML:           serveMathML,
 	})
 	if err != nil {
 		return err
 	}
+	warnFrontMatter(relPath, doc.FrontMatter)
 
-	fileCacheMu.Lock()
-	fileCache[relPath] = &CachedFile{
-		Content: rendered,
+	cached := &CachedFile{
+		Content: doc.Output,
+		Title:   doc.Title(filepath.Base(absPath)),
 		ModTime: stat.ModTime(),
+		Outline: doc.Outline,
 	}
+	sections := search.SectionsFromHTML(cached.Content)
+
+	fileCacheMu.Lock()
+	fileCache[relPath] = cached
+	searchIndex.Add(relPath, cached.Title, sections)
 	if stat.ModTime().After(globalModTime) {
 		globalModTime = stat.ModTime()
 	}
@@ -710,15 +822,20 @@ var mdLinkRegex = regexp.MustCompile(`href="([^"]*?)(\.md|\.markdown)(#[^"]*)?"`
 
 // rewriteMdLinks converts internal .md links to clean URLs for the server.
 func rewriteMdLinks(content string) string {
+	return rewriteMdLinksTo(content, "")
+}
+
+// rewriteMdLinksTo replaces the extension of internal .md links with ext.
+func rewriteMdLinksTo(content, ext string) string {
 	return mdLinkRegex.ReplaceAllStringFunc(content, func(match string) string {
 		// Skip external URLs
 		if strings.Contains(match, "://") {
 			return match
 		}
-		match = strings.Replace(match, ".markdown#", "#", 1)
-		match = strings.Replace(match, `.markdown"`, `"`, 1)
-		match = strings.Replace(match, ".md#", "#", 1)
-		match = strings.Replace(match, `.md"`, `"`, 1)
+		match = strings.Replace(match, ".markdown#", ext+"#", 1)
+		match = strings.Replace(match, `.markdown"`, ext+`"`, 1)
+		match = strings.Replace(match, ".md#", ext+"#", 1)
+		match = strings.Replace(match, `.md"`, ext+`"`, 1)
 		return match
 	})
 }
@@ -741,11 +858,7 @@ func startDirectoryWatcher() {
 			return nil
 		}
 		if info.IsDir() {
-			name := info.Name()
-			if strings.HasPrefix(name, ".") && path != baseDir {
-				return filepath.SkipDir
-			}
-			if skipDirs[name] {
+			if ignoreMatcher.Match(ignore.Clean(baseDir, path), true) {
 				return filepath.SkipDir
 			}
 			watcher.Add(path)
@@ -760,14 +873,33 @@ func startDirectoryWatcher() {
 				return
 			}
 
+			relPath := ignore.Clean(baseDir, event.Name)
+
+			// Changed ignore rules can add or hide any file
+			if ignoreFileChanged(event.Name) {
+				time.Sleep(200 * time.Millisecond) // Debounce
+				ignoreMatcher = loadIgnoreMatcher(baseDir, true)
+				if scanErr := scanAndRenderDirectory(); scanErr != nil {
+					fmt.Fprintf(os.Stderr, "Rescan error: %v\n", scanErr)
+				}
+				liveEvents.publish(changeEvent{Tree: true, ModTime: time.Now().Unix()})
+				continue
+			}
+
 			// Watch newly created directories
 			if event.Op&fsnotify.Create == fsnotify.Create {
 				if info, statErr := os.Stat(event.Name); statErr == nil && info.IsDir() {
-					watcher.Add(event.Name)
+					if !ignoreMatcher.Match(relPath, true) {
+						watcher.Add(event.Name)
+					}
 					continue
 				}
 			}
 
+			if ignoreMatcher.Match(relPath, false) {
+				continue
+			}
+
 			// Only process .md / .markdown files
 			lower := strings.ToLower(event.Name)
 			if !strings.HasSuffix(lower, ".md") && !strings.HasSuffix(lower, ".markdown") {
@@ -776,37 +908,40 @@ func startDirectoryWatcher() {
 
 			time.Sleep(200 * time.Millisecond) // Debounce
 
-			relPath, relErr := filepath.Rel(baseDir, event.Name)
-			if relErr != nil {
-				continue
-			}
-			relPath = filepath.ToSlash(relPath)
-
 			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
 				// File removed
 				fileCacheMu.Lock()
 				delete(fileCache, relPath)
+				searchIndex.Remove(relPath)
 				fileTree = buildFileTree(fileCache)
 				globalModTime = time.Now()
 				fileCacheMu.Unlock()
 
+				liveEvents.publish(changeEvent{Path: relPath, Tree: true, Removed: true, ModTime: time.Now().Unix()})
+
 				if verbose {
 					fmt.Printf("🗑️  File removed: %s at %s\n", relPath, time.Now().Format("15:04:05"))
 				}
 			} else if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
 				// File modified or created
-				if renderErr := renderSingleCachedFile(relPath); renderErr != nil {
+				renderErr := renderSingleCachedFile(relPath)
+				if renderErr != nil {
 					fmt.Fprintf(os.Stderr, "Render error for %s: %v\n", relPath, renderErr)
 				} else if verbose {
 					fmt.Printf("📝 File updated: %s at %s\n", relPath, time.Now().Format("15:04:05"))
 				}
 
 				// Rebuild tree if a new file was created
-				if event.Op&fsnotify.Create != 0 {
+				created := event.Op&fsnotify.Create != 0
+				if created {
 					fileCacheMu.Lock()
 					fileTree = buildFileTree(fileCache)
 					fileCacheMu.Unlock()
 				}
+
+				if renderErr == nil {
+					liveEvents.publish(changeEvent{Path: relPath, Tree: created, ModTime: time.Now().Unix()})
+				}
 			}
 
 		case err, ok := <-watcher.Errors:
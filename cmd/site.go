@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tacheraSasi/mdcli/ignore"
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/tacheraSasi/mdcli/themes"
+	views "github.com/tacheraSasi/mdcli/ui"
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Static documentation site tools",
+	Long:  "Build navigable static websites from directories of Markdown files.",
+}
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build [directory]",
+	Short: "Build a static site from a Markdown directory",
+	Long: `Render every Markdown file in a directory into a static website that looks
+like the serve preview: file tree navigation, a generated index for every
+directory, rewritten .md links and the embedded CSS and icons.
+Non-Markdown files such as images are copied alongside the pages, except
+ones that would replace a generated page or the assets directory. Pages and
+files an earlier build wrote that no longer have a source are removed.
+
+Examples:
+  mdcli site build docs/                     # Writes the site to ./site
+  mdcli site build docs/ -o public           # Custom output directory
+  mdcli site build docs/ --base-path /mdcli  # Site hosted under a sub path`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSiteBuild,
+}
+
+var (
+	siteOutput   string
+	siteTheme    string
+	siteWidth    int
+	siteBasePath string
+)
+
+func init() {
+	rootCmd.AddCommand(siteCmd)
+	siteCmd.AddCommand(siteBuildCmd)
+
+	siteBuildCmd.Flags().StringVarP(&siteOutput, "output", "o", "site", "Output directory")
+	siteBuildCmd.Flags().StringVarP(&siteTheme, "theme", "t", "github", "Theme for HTML output")
+	siteBuildCmd.Flags().IntVarP(&siteWidth, "width", "w", 80, "Content width")
+	siteBuildCmd.Flags().StringVar(&siteBasePath, "base-path", "", "URL path the site is hosted under (e.g. /docs)")
+}
+
+// indexNames are the files used as a directory's index page, in order
+var indexNames = []string{"README.md", "readme.md", "Readme.md", "index.md", "INDEX.md"}
+
+func runSiteBuild(cmd *cobra.Command, args []string) {
+	srcDir := "."
+	if len(args) > 0 {
+		srcDir = args[0]
+	}
+
+	absDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving path: %v\n", err)
+		os.Exit(1)
+	}
+	absOut, err := filepath.Abs(siteOutput)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving output path: %v\n", err)
+		os.Exit(1)
+	}
+
+	matcher := loadIgnoreMatcher(absDir, true)
+	// Never read back a previous build written inside the source tree
+	if rel, err := filepath.Rel(absDir, absOut); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		matcher.Add("", "/"+filepath.ToSlash(rel)+"/")
+	}
+
+	cache, latestMod, err := renderDirectory(absDir, matcher, siteTheme, siteWidth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
+		os.Exit(1)
+	}
+	if len(cache) == 0 {
+		fmt.Println("No Markdown files found in the specified directory.")
+		return
+	}
+
+	basePath := strings.TrimSuffix(siteBasePath, "/")
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	tree := siteTree(buildFileTree(cache))
+
+	page := func(title, content, currentPath string, modTime time.Time, outline []renderer.Heading) views.ServeData {
+		return views.ServeData{
+			Title:           title,
+			Content:         siteLinks(content, basePath),
+			ThemeName:       siteTheme,
+			IsDirectoryMode: true,
+			CurrentPath:     currentPath,
+			Files:           tree,
+			BasePath:        basePath,
+			Static:          true,
+			LastModified:    modTime.Format("Jan 2, 2006"),
+			Outline:         outline,
+		}
+	}
+
+	fmt.Printf("🏗️  Building site from %s\n", absDir)
+
+	// written holds every generated page, relative to absOut
+	written := make(map[string]bool)
+	for relPath, cached := range cache {
+		target := siteHTMLPath(relPath)
+		if err := writeSitePage(absOut, target, page(cached.Title, cached.Content, target, cached.ModTime, cached.Outline)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", target, err)
+			os.Exit(1)
+		}
+		written[target] = true
+	}
+
+	// Every directory gets an index.html: its README/index page or a listing
+	for _, dir := range siteDirectories(cache) {
+		prefix := ""
+		if dir != "" {
+			prefix = dir + "/"
+		}
+		target := prefix + "index.html"
+		if _, exists := cache[prefix+"index.md"]; exists {
+			// Already written from index.md
+			continue
+		}
+
+		var data views.ServeData
+		found := false
+		for _, name := range indexNames {
+			if cached, ok := cache[prefix+name]; ok {
+				data = page(cached.Title, cached.Content, siteHTMLPath(prefix+name), cached.ModTime, cached.Outline)
+				found = true
+				break
+			}
+		}
+		if !found {
+			title := path.Base(dir)
+			if dir == "" {
+				title = filepath.Base(absDir)
+			}
+			data = page(title, buildDirectoryListing(dir, cache), "", latestMod, nil)
+		}
+
+		if err := writeSitePage(absOut, target, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", target, err)
+			os.Exit(1)
+		}
+		written[target] = true
+	}
+	pages := len(written)
+
+	if err := copyEmbeddedAssets(filepath.Join(absOut, "assets")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error copying assets: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writeSyntaxCSS(filepath.Join(absOut, "assets", "syntax"), siteTheme); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing syntax stylesheet: %v\n", err)
+		os.Exit(1)
+	}
+	copied, err := copySiteFiles(absDir, absOut, matcher, written)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error copying files: %v\n", err)
+		os.Exit(1)
+	}
+	for _, relPath := range copied {
+		written[relPath] = true
+	}
+
+	// Pages and files of earlier builds whose source is gone are removed
+	pruned := pruneSiteFiles(absOut, written)
+	if verbose {
+		for _, output := range pruned {
+			fmt.Printf("🗑️  Pruned %s\n", output)
+		}
+	}
+
+	fmt.Printf("✅ Wrote %d pages and %d files to %s", pages, len(copied), absOut)
+	if len(pruned) > 0 {
+		fmt.Printf(", removed %d stale files", len(pruned))
+	}
+	fmt.Println()
+}
+
+// siteManifestFile lists the pages and files of the last build in the
+// output directory, one slash separated path per line
+const siteManifestFile = ".mdcli-site"
+
+// pruneSiteFiles removes the files the last build wrote to outDir that are
+// not in written, records written for the next build and returns the
+// removed files
+func pruneSiteFiles(outDir string, written map[string]bool) []string {
+	manifest := filepath.Join(outDir, siteManifestFile)
+
+	var pruned []string
+	if data, err := os.ReadFile(manifest); err == nil {
+		for _, output := range strings.Split(string(data), "\n") {
+			if output == "" || written[output] || !filepath.IsLocal(filepath.FromSlash(output)) {
+				continue
+			}
+			if removeOutput(outDir, output) == nil {
+				pruned = append(pruned, output)
+			}
+		}
+	}
+
+	files := make([]string, 0, len(written))
+	for output := range written {
+		files = append(files, output)
+	}
+	sort.Strings(files)
+	if err := os.WriteFile(manifest, []byte(strings.Join(files, "\n")+"\n"), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write %s: %v\n", manifest, err)
+	}
+	sort.Strings(pruned)
+	return pruned
+}
+
+// siteHTMLPath maps a Markdown path to the page written for it
+func siteHTMLPath(relPath string) string {
+	return strings.TrimSuffix(relPath, path.Ext(relPath)) + ".html"
+}
+
+// siteTree points the file entries at the generated pages
+func siteTree(entries []views.FileEntry) []views.FileEntry {
+	result := make([]views.FileEntry, len(entries))
+	for i, entry := range entries {
+		if entry.IsDir {
+			entry.Children = siteTree(entry.Children)
+		} else {
+			entry.Path = siteHTMLPath(entry.Path)
+		}
+		result[i] = entry
+	}
+	return result
+}
+
+// siteDirectories returns every directory holding Markdown files, including
+// the root ("") and the parents of nested ones
+func siteDirectories(cache map[string]*CachedFile) []string {
+	seen := map[string]bool{"": true}
+	dirs := []string{""}
+	for relPath := range cache {
+		for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+var rootLinkRegex = regexp.MustCompile(`(href|src)="/([^/"][^"]*)?"`)
+
+// siteLinks points .md links at the generated pages and prefixes
+// root-relative links with basePath
+func siteLinks(content, basePath string) string {
+	content = rewriteMdLinksTo(content, ".html")
+	if basePath == "" {
+		return content
+	}
+	return rootLinkRegex.ReplaceAllStringFunc(content, func(match string) string {
+		attr, rest, _ := strings.Cut(match, `="`)
+		return attr + `="` + basePath + rest
+	})
+}
+
+// writeSitePage renders data into outDir/target
+func writeSitePage(outDir, target string, data views.ServeData) error {
+	outFile := filepath.Join(outDir, filepath.FromSlash(target))
+	if err := os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return views.ServePage(data).Render(context.Background(), f)
+}
+
+// copyEmbeddedAssets writes the embedded CSS, JS and icons to dest
+func copyEmbeddedAssets(dest string) error {
+	return fs.WalkDir(AssetsFS, "assets", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, "assets"), "/")
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		data, err := fs.ReadFile(AssetsFS, p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+// writeSyntaxCSS writes the code highlighting stylesheet of theme to dir
+func writeSyntaxCSS(dir, theme string) error {
+	css, err := themes.SyntaxCSS(theme)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, theme+".css"), []byte(css), 0644)
+}
+
+// copySiteFiles copies the non-Markdown files of srcDir, such as images,
+// into outDir and returns their paths relative to outDir. Files that would
+// replace a generated page or the bundled assets are skipped with a warning,
+// and so is outDir when it lies inside srcDir.
+func copySiteFiles(srcDir, outDir string, matcher *ignore.Matcher, generated map[string]bool) ([]string, error) {
+	var copied []string
+	err := filepath.Walk(srcDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && filepath.Clean(p) == filepath.Clean(outDir) {
+			return filepath.SkipDir
+		}
+		relPath := ignore.Clean(srcDir, p)
+		if matcher.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		lower := strings.ToLower(info.Name())
+		if strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown") {
+			return nil
+		}
+		if generated[relPath] || strings.HasPrefix(relPath, "assets/") {
+			fmt.Fprintf(os.Stderr, "Warning: not copying %s: the site generates a file at that path\n", relPath)
+			return nil
+		}
+
+		if err := copyFile(p, filepath.Join(outDir, filepath.FromSlash(relPath))); err != nil {
+			return err
+		}
+		copied = append(copied, relPath)
+		return nil
+	})
+	return copied, err
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/tacheraSasi/mdcli/slides"
+	"github.com/tacheraSasi/mdcli/themes"
+	views "github.com/tacheraSasi/mdcli/ui"
+)
+
+var slidesCmd = &cobra.Command{
+	Use:   "slides [file]",
+	Short: "Present a Markdown file as a slide deck",
+	Long: `Serve a Markdown document as a slide deck, or export it to a single HTML
+file or a PDF.
+
+Slides are separated by "---" lines with a blank line above them. Documents
+without such rules are split at headings instead (level 2 and above by
+default, see --level). A line starting with "Note:" turns the rest of the
+slide into speaker notes, shown only in the presenter view.
+
+Keys: →, Space, n next slide; ←, p previous; Home/End first/last; a number
+then Enter jumps to that slide; f fullscreen; s opens the presenter view,
+with the next slide, the notes and a timer (t pauses it, r resets it).
+Windows showing the deck follow each other, and reload when the file is
+saved.
+
+Examples:
+  mdcli slides talk.md                        # Present at http://localhost:8080
+  mdcli slides talk.md --split heading        # One slide per heading
+  mdcli slides talk.md --export html          # Write talk.html
+  mdcli slides talk.md --export pdf -o out.pdf`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSlides,
+}
+
+var (
+	slidesPort   int
+	slidesBind   string
+	slidesTheme  string
+	slidesReload bool
+	slidesSplit  string
+	slidesLevel  int
+	slidesExport string
+	slidesOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(slidesCmd)
+
+	slidesCmd.Flags().IntVarP(&slidesPort, "port", "p", 8080, "Port to serve on")
+	slidesCmd.Flags().StringVarP(&slidesBind, "bind", "b", "localhost", "Bind address")
+	slidesCmd.Flags().StringVarP(&slidesTheme, "theme", "t", "github", "Theme for the slides")
+	slidesCmd.Flags().BoolVar(&slidesReload, "auto-reload", true, "Reload the slides when the file changes")
+	slidesCmd.Flags().StringVar(&slidesSplit, "split", slides.SplitAuto, "Split slides on rules, headings or auto")
+	slidesCmd.Flags().IntVar(&slidesLevel, "level", 2, "Deepest heading level that starts a slide")
+	slidesCmd.Flags().StringVar(&slidesExport, "export", "", "Write the deck to a file instead of serving it (html, pdf)")
+	slidesCmd.Flags().StringVarP(&slidesOutput, "output", "o", "", "Export file path (default: the input name with .html or .pdf)")
+}
+
+// --- Slide server state ---
+var (
+	deckMu   sync.RWMutex
+	deckPage views.SlidesData
+)
+
+func runSlides(cmd *cobra.Command, args []string) {
+	file := args[0]
+	if !cmd.Flags().Changed("split") && viper.IsSet("slides.split") {
+		slidesSplit = viper.GetString("slides.split")
+	}
+	if !cmd.Flags().Changed("level") && viper.IsSet("slides.level") {
+		slidesLevel = viper.GetInt("slides.level")
+	}
+	if _, err := themes.GetTheme(slidesTheme); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch slidesExport {
+	case "":
+		serveSlides(file)
+	case "html", "pdf":
+		exportSlides(file)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown export format %q (use html or pdf)\n", slidesExport)
+		os.Exit(1)
+	}
+}
+
+// loadDeck reads and splits a Markdown file into slides
+func loadDeck(file string) (*slides.Deck, error) {
+	content, err := renderer.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	deck, err := slides.Parse(content, slides.Options{Split: slidesSplit, Level: slidesLevel})
+	if err != nil {
+		return nil, err
+	}
+	warnFrontMatter(file, deck.FrontMatter)
+	return deck, nil
+}
+
+// renderDeck renders the slides and notes of a deck to HTML for the slide
+// page. The title comes from the front matter, the first heading or the
+// file name.
+func renderDeck(file string, deck *slides.Deck) (views.SlidesData, error) {
+	theme, _ := themes.GetTheme(slidesTheme)
+	data := views.SlidesData{
+		Title:     deck.FrontMatter.Title,
+		ThemeName: slidesTheme,
+		ThemeCSS:  themes.CSS(theme.Colors, ".slide"),
+	}
+
+	render := func(input string) (*renderer.Document, error) {
+		return renderer.Render(renderer.RenderOptions{
+			Input:            input,
+			Autolink:         true,
+			Theme:            slidesTheme,
+			OutputFormat:     "html",
+			BaseDir:          filepath.Dir(file),
+			RawFrontMatter:   true,
+			NoScripts:        true,
+			HighlightClasses: true,
+		})
+	}
+	for _, s := range deck.Slides {
+		doc, err := render(s.Markdown)
+		if err != nil {
+			return data, fmt.Errorf("slide at line %d: %w", s.Line, err)
+		}
+		if data.Title == "" && len(doc.Outline) > 0 {
+			data.Title = doc.Outline[0].Text
+		}
+		slide := views.Slide{Content: doc.Output}
+		if s.Notes != "" {
+			notes, err := render(s.Notes)
+			if err != nil {
+				return data, fmt.Errorf("notes at line %d: %w", s.Line, err)
+			}
+			slide.Notes = notes.Output
+		}
+		data.Slides = append(data.Slides, slide)
+	}
+
+	if data.Title == "" {
+		data.Title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+	return data, nil
+}
+
+// reloadDeck renders file into the page served by the slide server
+func reloadDeck(file string) (time.Time, error) {
+	stat, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+	deck, err := loadDeck(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, err := renderDeck(file, deck)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data.AutoReload = slidesReload
+
+	deckMu.Lock()
+	deckPage = data
+	deckMu.Unlock()
+	return stat.ModTime(), nil
+}
+
+// deckFiles serves the files next to a deck, such as its images. Hidden
+// files and directories like .git or .env are never served, and
+// directories are not listed.
+func deckFiles(dir string) http.Handler {
+	root := http.Dir(dir)
+	files := http.FileServer(root)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, segment := range strings.Split(r.URL.Path, "/") {
+			if strings.HasPrefix(segment, ".") {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		f, err := root.Open(r.URL.Path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		info, err := f.Stat()
+		f.Close()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+func serveSlides(file string) {
+	if _, err := reloadDeck(file); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if slidesReload {
+		go watchFile(file, func() (time.Time, error) { return reloadDeck(file) })
+	}
+
+	mux := http.NewServeMux()
+
+	assetsSubFS, _ := fs.Sub(AssetsFS, "assets")
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(assetsSubFS))))
+	mux.HandleFunc("/assets/syntax/", handleSyntaxCSS)
+	mux.Handle("/events", liveEvents)
+
+	// Everything else is served from the deck's directory, for its images
+	files := deckFiles(filepath.Dir(file))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			files.ServeHTTP(w, r)
+			return
+		}
+		deckMu.RLock()
+		data := deckPage
+		deckMu.RUnlock()
+		templ.Handler(views.SlidesPage(data)).ServeHTTP(w, r)
+	})
+
+	deckMu.RLock()
+	count := len(deckPage.Slides)
+	deckMu.RUnlock()
+
+	addr := fmt.Sprintf("%s:%d", slidesBind, slidesPort)
+	fmt.Printf("🚀 Starting slide server...\n")
+	fmt.Printf("📄 File: %s (%d %s)\n", file, count, plural(count, "slide"))
+	fmt.Printf("🌐 URL: http://%s\n", addr)
+	fmt.Printf("🎤 Presenter view: http://%s/?presenter\n", addr)
+	fmt.Printf("🎨 Theme: %s\n", slidesTheme)
+	if slidesReload {
+		fmt.Printf("🔄 Auto-reload: enabled\n")
+	}
+	fmt.Printf("Press Ctrl+C to stop\n\n")
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// exportSlides writes the deck as one self-contained HTML page, or as a
+// PDF with a landscape page per slide
+func exportSlides(file string) {
+	deck, err := loadDeck(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	output := slidesOutput
+	if output == "" {
+		output = strings.TrimSuffix(file, filepath.Ext(file)) + "." + slidesExport
+	}
+
+	var out []byte
+	if slidesExport == "pdf" {
+		var sources []renderer.PDFSource
+		for _, s := range deck.Slides {
+			sources = append(sources, renderer.PDFSource{Input: s.Markdown, BaseDir: filepath.Dir(file)})
+		}
+		out, err = renderer.RenderPDF(renderer.RenderOptions{
+			Autolink:       true,
+			Theme:          slidesTheme,
+			RawFrontMatter: true,
+			Landscape:      true,
+		}, sources...)
+	} else {
+		out, err = standaloneSlides(file, deck)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", file, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(output, out, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Exported %d %s to %s\n", len(deck.Slides), plural(len(deck.Slides), "slide"), output)
+}
+
+// standaloneSlides renders the deck page with its assets and local images
+// inlined, so the presenter view also works offline
+func standaloneSlides(file string, deck *slides.Deck) ([]byte, error) {
+	data, err := renderDeck(file, deck)
+	if err != nil {
+		return nil, err
+	}
+	var content strings.Builder
+	for i := range data.Slides {
+		data.Slides[i].Content = inlineImages(data.Slides[i].Content, filepath.Dir(file))
+		data.Slides[i].Notes = inlineImages(data.Slides[i].Notes, filepath.Dir(file))
+		content.WriteString(data.Slides[i].Content)
+		content.WriteString(data.Slides[i].Notes)
+	}
+
+	var buf bytes.Buffer
+	if err := views.SlidesPage(data).Render(context.Background(), &buf); err != nil {
+		return nil, err
+	}
+	page, err := inlineAssets(buf.String(), content.String(), slidesTheme)
+	return []byte(page), err
+}
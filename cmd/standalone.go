@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/tacheraSasi/mdcli/themes"
+	views "github.com/tacheraSasi/mdcli/ui"
+)
+
+// References to the preview assets in a page, and to images in documents
+var (
+	stylesheetTag = regexp.MustCompile(`<link rel="stylesheet" href="/assets/([^"]+)"\s*/?>`)
+	iconTag       = regexp.MustCompile(`<link rel="(?:icon|apple-touch-icon)"[^>]*>`)
+	scriptTag     = regexp.MustCompile(`<script src="/assets/([^"]+)"></script>`)
+	cssURL        = regexp.MustCompile(`url\(\s*['"]?/assets/([^'")]+)['"]?\s*\)`)
+	imageTag      = regexp.MustCompile(`(<img\b[^>]*?\bsrc=)(?:"([^"]*)"|'([^']*)')`)
+)
+
+// standalonePage wraps rendered HTML in the preview page and inlines its
+// stylesheets and scripts, so the file can be opened anywhere
+func standalonePage(content, title, theme string, outline []renderer.Heading) (string, error) {
+	if theme == "" {
+		theme = "dracula"
+	}
+
+	var buf bytes.Buffer
+	err := views.ServePage(views.ServeData{
+		Title:     title,
+		Content:   content,
+		ThemeName: theme,
+		Static:    true,
+		Outline:   outline,
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		return "", err
+	}
+	return inlineAssets(buf.String(), content, theme)
+}
+
+// inlineAssets replaces the references to preview assets in page with
+// their contents. content is the rendered documents on the page, which
+// decide the scripts that are needed.
+func inlineAssets(page, content, theme string) (string, error) {
+	var firstErr error
+	fail := func(err error) string {
+		if firstErr == nil {
+			firstErr = err
+		}
+		return ""
+	}
+
+	page = stylesheetTag.ReplaceAllStringFunc(page, func(tag string) string {
+		name := stylesheetTag.FindStringSubmatch(tag)[1]
+		var css string
+		if strings.HasPrefix(name, "syntax/") {
+			generated, err := themes.SyntaxCSS(theme)
+			if err != nil {
+				return fail(err)
+			}
+			css = generated
+		} else {
+			data, err := fs.ReadFile(AssetsFS, "assets/"+name)
+			if err != nil {
+				return fail(err)
+			}
+			css = inlineCSSURLs(string(data))
+		}
+		return "<style>\n" + strings.ReplaceAll(css, "</style", `<\/style`) + "\n</style>"
+	})
+
+	// One favicon is enough; the other sizes are for home screens
+	icon := ""
+	if data, err := fs.ReadFile(AssetsFS, "assets/icons/favicon.ico"); err == nil {
+		icon = `<link rel="icon" type="image/x-icon" href="` + dataURI("favicon.ico", data) + `">`
+	}
+	page = iconTag.ReplaceAllStringFunc(page, func(string) string {
+		tag := icon
+		icon = ""
+		return tag
+	})
+
+	// The diagram and math renderers are large, so they are only included
+	// when the document uses them
+	needed := map[string]bool{
+		"js/mermaid.min.js": strings.Contains(content, `class="mermaid"`),
+		"js/math.js":        strings.Contains(content, `class="math `),
+	}
+	page = scriptTag.ReplaceAllStringFunc(page, func(tag string) string {
+		name := scriptTag.FindStringSubmatch(tag)[1]
+		if use, known := needed[name]; known && !use {
+			return ""
+		}
+		data, err := fs.ReadFile(AssetsFS, "assets/"+name)
+		if err != nil {
+			return fail(err)
+		}
+		return "<script>" + strings.ReplaceAll(string(data), "</script", `<\/script`) + "</script>"
+	})
+
+	return page, firstErr
+}
+
+// inlineCSSURLs replaces url() references to embedded assets, such as
+// fonts, with data URIs
+func inlineCSSURLs(css string) string {
+	return cssURL.ReplaceAllStringFunc(css, func(ref string) string {
+		name := cssURL.FindStringSubmatch(ref)[1]
+		data, err := fs.ReadFile(AssetsFS, "assets/"+name)
+		if err != nil {
+			return ref
+		}
+		return `url("` + dataURI(name, data) + `")`
+	})
+}
+
+// inlineImages replaces the sources of local images with data URIs. Paths
+// are resolved against baseDir; remote and missing images are left alone.
+func inlineImages(content, baseDir string) string {
+	return imageTag.ReplaceAllStringFunc(content, func(tag string) string {
+		m := imageTag.FindStringSubmatch(tag)
+		src := m[2] + m[3]
+		if src == "" || strings.HasPrefix(src, "data:") || strings.HasPrefix(src, "//") || strings.Contains(src, "://") {
+			return tag
+		}
+
+		file := html.UnescapeString(src)
+		if i := strings.IndexAny(file, "?#"); i >= 0 {
+			file = file[:i]
+		}
+		if unescaped, err := url.PathUnescape(file); err == nil {
+			file = unescaped
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(baseDir, filepath.FromSlash(file))
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: image %s not inlined: %v\n", src, err)
+			return tag
+		}
+		return m[1] + `"` + dataURI(file, data) + `"`
+	})
+}
+
+// dataURI encodes data as a base64 data URI, typed by the file extension
+// or, failing that, the content
+func dataURI(name string, data []byte) string {
+	mediaType := mime.TypeByExtension(path.Ext(name))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
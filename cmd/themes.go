@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tacheraSasi/mdcli/themes"
+	"gopkg.in/yaml.v3"
 )
 
 var themesCmd = &cobra.Command{
@@ -24,12 +28,22 @@ func runThemes(cmd *cobra.Command, args []string) {
 	fmt.Println(" Available Themes:")
 	fmt.Println(strings.Repeat("=", 40))
 
-	for name, theme := range themes.AvailableThemes {
-		fmt.Printf("\n%s\n", strings.ToUpper(name))
+	for _, name := range themes.ListThemes() {
+		theme, err := themes.GetTheme(name)
+		if err != nil {
+			continue
+		}
+
+		if theme.Custom {
+			fmt.Printf("\n%s (custom)\n", strings.ToUpper(name))
+		} else {
+			fmt.Printf("\n%s\n", strings.ToUpper(name))
+		}
 		fmt.Printf("   Name: %s\n", theme.Name)
 		fmt.Printf("   Description: %s\n", theme.Description)
 
 		if verbose {
+			fmt.Printf("   Syntax style: %s\n", themes.GetSyntaxHighlightingStyle(name))
 			fmt.Printf("   Colors:\n")
 			fmt.Printf("     Primary: %s\n", theme.Colors.Primary)
 			fmt.Printf("     Accent: %s\n", theme.Colors.Accent)
@@ -41,4 +55,47 @@ func runThemes(cmd *cobra.Command, args []string) {
 	fmt.Println(strings.Repeat("-", 40))
 	fmt.Println("Use --theme=<name> with render command to apply a theme")
 	fmt.Println("Use --verbose to see color details")
+	fmt.Printf("Custom themes are read from the 'themes' config key and %s\n", userThemesDir())
+}
+
+// userThemesDir returns the directory holding one YAML file per user theme
+func userThemesDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "mdcli", "themes")
+}
+
+// loadUserThemes registers the themes from the themes directory and the
+// config file. Invalid themes are reported and skipped.
+func loadUserThemes() {
+	var defs []themes.Definition
+
+	if dir := userThemesDir(); dir != "" {
+		fileDefs, errs := themes.LoadDir(dir)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		defs = append(defs, fileDefs...)
+	}
+
+	// Config file themes come last so they win over theme files
+	if raw := viper.Get("themes"); raw != nil {
+		data, err := yaml.Marshal(raw)
+		if err == nil {
+			var configDefs []themes.Definition
+			configDefs, err = themes.ParseDefinitions(data)
+			defs = append(defs, configDefs...)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	for _, def := range defs {
+		if err := themes.Register(def); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping custom theme: %v\n", err)
+		}
+	}
 }
@@ -123,7 +123,7 @@ func renderFiles(files []string) {
 
 	var renderedAll []string
 	for idx, input := range inputs {
-		rendered, err := renderer.Render(renderer.RenderOptions{
+		doc, err := renderer.Render(renderer.RenderOptions{
 			Input:        input,
 			Autolink:     true,
 			Theme:        watchTheme,
@@ -134,7 +134,7 @@ func renderFiles(files []string) {
 			fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", filenames[idx], err)
 			continue
 		}
-		renderedAll = append(renderedAll, rendered)
+		renderedAll = append(renderedAll, doc.Output)
 	}
 
 	outputStr := strings.Join(renderedAll, "\n\n---\n\n")
@@ -0,0 +1,203 @@
+package diagram
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Directions a line leaves a cell in
+const (
+	up uint8 = 1 << iota
+	down
+	left
+	right
+)
+
+// continuation marks the second cell of a wide rune
+const continuation = -1
+
+// canvas is a grid that boxes, lines and text are drawn on. Lines are
+// kept as the directions they leave each cell in, so crossing and
+// touching lines join up when the grid is turned into text.
+type canvas struct {
+	cells  [][]rune
+	lines  [][]uint8
+	dashed [][]bool
+}
+
+// grow makes room for the cell at x, y
+func (c *canvas) grow(x, y int) {
+	for len(c.cells) <= y {
+		c.cells = append(c.cells, nil)
+		c.lines = append(c.lines, nil)
+		c.dashed = append(c.dashed, nil)
+	}
+	for len(c.cells[y]) <= x {
+		c.cells[y] = append(c.cells[y], 0)
+		c.lines[y] = append(c.lines[y], 0)
+		c.dashed[y] = append(c.dashed[y], false)
+	}
+}
+
+// set puts r at x, y over anything drawn there before
+func (c *canvas) set(x, y int, r rune) {
+	if x < 0 || y < 0 {
+		return
+	}
+	c.grow(x, y)
+	c.cells[y][x] = r
+}
+
+// text writes s from x, y and returns the column after it
+func (c *canvas) text(x, y int, s string) int {
+	for _, r := range s {
+		c.set(x, y, r)
+		x++
+		if runewidth.RuneWidth(r) == 2 {
+			c.set(x, y, continuation)
+			x++
+		}
+	}
+	return x
+}
+
+// link adds the directions dirs to the line through x, y
+func (c *canvas) link(x, y int, dirs uint8, dashed bool) {
+	if x < 0 || y < 0 {
+		return
+	}
+	c.grow(x, y)
+	c.lines[y][x] |= dirs
+	c.dashed[y][x] = c.dashed[y][x] || dashed
+}
+
+// hline draws a horizontal line between x1 and x2 on row y
+func (c *canvas) hline(x1, x2, y int, dashed bool) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x <= x2; x++ {
+		var dirs uint8
+		if x > x1 {
+			dirs |= left
+		}
+		if x < x2 {
+			dirs |= right
+		}
+		c.link(x, y, dirs, dashed)
+	}
+}
+
+// vline draws a vertical line between y1 and y2 in column x
+func (c *canvas) vline(x, y1, y2 int, dashed bool) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y <= y2; y++ {
+		var dirs uint8
+		if y > y1 {
+			dirs |= up
+		}
+		if y < y2 {
+			dirs |= down
+		}
+		c.link(x, y, dirs, dashed)
+	}
+}
+
+// path draws straight lines through the points in turn
+func (c *canvas) path(dashed bool, points ...[2]int) {
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		if a[1] == b[1] {
+			c.hline(a[0], b[0], a[1], dashed)
+		} else {
+			c.vline(a[0], a[1], b[1], dashed)
+		}
+	}
+}
+
+// Border styles of boxes: top left, top right, bottom left, bottom right,
+// horizontal and vertical
+var (
+	squareBorder   = [6]rune{'┌', '┐', '└', '┘', '─', '│'}
+	roundBorder    = [6]rune{'╭', '╮', '╰', '╯', '─', '│'}
+	decisionBorder = [6]rune{'╱', '╲', '╲', '╱', '─', '│'}
+	noteBorder     = [6]rune{'┌', '┐', '└', '┘', '╌', '╎'}
+)
+
+// box draws a w by h box at x, y with label centered on its middle row
+func (c *canvas) box(x, y, w, h int, border [6]rune, label string) {
+	for i := x + 1; i < x+w-1; i++ {
+		c.set(i, y, border[4])
+		c.set(i, y+h-1, border[4])
+	}
+	for j := y + 1; j < y+h-1; j++ {
+		c.set(x, j, border[5])
+		c.set(x+w-1, j, border[5])
+		for i := x + 1; i < x+w-1; i++ {
+			c.set(i, j, ' ')
+		}
+	}
+	c.set(x, y, border[0])
+	c.set(x+w-1, y, border[1])
+	c.set(x, y+h-1, border[2])
+	c.set(x+w-1, y+h-1, border[3])
+	c.text(x+(w-runewidth.StringWidth(label))/2, y+h/2, label)
+}
+
+// width returns the number of columns in use
+func (c *canvas) width() int {
+	w := 0
+	for _, row := range c.cells {
+		w = max(w, len(row))
+	}
+	return w
+}
+
+// lineGlyphs maps the directions out of a cell to the line drawn in it
+var lineGlyphs = map[uint8]rune{
+	up: '│', down: '│', up | down: '│',
+	left: '─', right: '─', left | right: '─',
+	down | right: '╭', down | left: '╮', up | right: '╰', up | left: '╯',
+	up | down | right: '├', up | down | left: '┤',
+	down | left | right: '┬', up | left | right: '┴',
+	up | down | left | right: '┼',
+}
+
+// Lines returns the canvas as text, without trailing spaces
+func (c *canvas) Lines() []string {
+	out := make([]string, len(c.cells))
+	for y, row := range c.cells {
+		var b strings.Builder
+		for x, r := range row {
+			switch {
+			case r == continuation:
+				continue
+			case r != 0:
+				b.WriteRune(r)
+			case c.lines[y][x] != 0:
+				b.WriteRune(c.glyph(x, y))
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		out[y] = strings.TrimRight(b.String(), " ")
+	}
+	return out
+}
+
+// glyph returns the line drawing character for the cell at x, y
+func (c *canvas) glyph(x, y int) rune {
+	dirs := c.lines[y][x]
+	if c.dashed[y][x] {
+		switch dirs {
+		case left, right, left | right:
+			return '┄'
+		case up, down, up | down:
+			return '┆'
+		}
+	}
+	return lineGlyphs[dirs]
+}
@@ -0,0 +1,97 @@
+// Package diagram draws Mermaid flowcharts, sequence diagrams and state
+// diagrams as box-drawing text for the terminal.
+package diagram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ErrUnsupported is returned for diagram types that cannot be drawn
+var ErrUnsupported = errors.New("unsupported diagram type")
+
+// Render draws Mermaid source in at most width columns. Diagrams too wide
+// to draw are turned around, and failing that listed one edge or message
+// per line.
+func Render(source string, width int) ([]string, error) {
+	header, lines := statements(source)
+	kind := ""
+	if fields := strings.Fields(header); len(fields) > 0 {
+		kind = fields[0]
+	}
+
+	switch kind {
+	case "graph", "flowchart":
+		g, err := parseFlowchart(header, lines)
+		if err != nil {
+			return nil, err
+		}
+		return fitGraph(g, width), nil
+	case "stateDiagram", "stateDiagram-v2":
+		g, err := parseState(lines)
+		if err != nil {
+			return nil, err
+		}
+		return fitGraph(g, width), nil
+	case "sequenceDiagram":
+		s, err := parseSequence(lines)
+		if err != nil {
+			return nil, err
+		}
+		if out := s.draw().Lines(); fits(out, width) {
+			return out, nil
+		}
+		return s.list(), nil
+	case "":
+		return nil, errors.New("empty diagram")
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, kind)
+}
+
+// fitGraph draws g in its own direction, or across it when that is too
+// wide, or lists its edges when neither fits
+func fitGraph(g *graph, width int) []string {
+	if out := g.draw().Lines(); fits(out, width) {
+		return out
+	}
+	g.horizontal = !g.horizontal
+	if out := g.draw().Lines(); fits(out, width) {
+		return out
+	}
+	return g.list()
+}
+
+// fits reports whether no line is wider than width
+func fits(lines []string, width int) bool {
+	for _, line := range lines {
+		if runewidth.StringWidth(line) > width {
+			return false
+		}
+	}
+	return true
+}
+
+// statements splits source into its header line, such as "graph TD", and
+// the lines after it, leaving out comments, directives and front matter
+func statements(source string) (string, []string) {
+	var lines []string
+	inFrontMatter := false
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "---" && (i == 0 || inFrontMatter):
+			inFrontMatter = !inFrontMatter
+			continue
+		case inFrontMatter, line == "", strings.HasPrefix(line, "%%"):
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return lines[0], lines[1:]
+}
@@ -0,0 +1,70 @@
+package diagram
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestRender draws every testdata/<name>.mmd and compares the result with
+// testdata/<name>.golden. Run go test -update to accept new output.
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		width  int
+	}{
+		{"flowchart", "flowchart", 80},
+		{"branching", "branching", 80},
+		{"branching-narrow", "branching", 40},
+		{"branching-list", "branching", 12},
+		{"sequence", "sequence", 80},
+		{"sequence-list", "sequence", 20},
+		{"pie", "pie", 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := os.ReadFile(filepath.Join("testdata", tt.source+".mmd"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			lines, err := Render(string(source), tt.width)
+			if err != nil {
+				got = "error: " + err.Error() + "\n"
+			} else {
+				got = strings.Join(lines, "\n") + "\n"
+			}
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("Render(%s.mmd, %d) =\n%s\nwant\n%s", tt.source, tt.width, got, want)
+			}
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := Render("pie\n  \"A\" : 1\n", 80); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("pie chart error = %v, want ErrUnsupported", err)
+	}
+	if _, err := Render("%% only a comment\n", 80); err == nil || errors.Is(err, ErrUnsupported) {
+		t.Errorf("empty diagram error = %v, want a parse error", err)
+	}
+}
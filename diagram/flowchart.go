@@ -0,0 +1,165 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// nodeID matches the id at the start of a node reference
+	nodeID = regexp.MustCompile(`^[\p{L}\p{N}_]+`)
+	// textLink matches a link with its text inside, like "-- yes -->"
+	textLink = regexp.MustCompile(`^<?(--|==|-\.)\s*([^-=.|>\s][^|]*?)\s*(-{2,}|={2,}|\.+-)([>ox]?)`)
+	// plainLink matches a link with an optional |text| after it
+	plainLink = regexp.MustCompile(`^<?(-{2,}|={2,}|-\.+-)([>ox]?)(?:\s*\|([^|]*)\|)?`)
+)
+
+// Shape delimiters in the order they are tried, longest first
+var shapeDelimiters = []struct {
+	open, close string
+	shape       shape
+}{
+	{"(((", ")))", shapeRound},
+	{"((", "))", shapeRound},
+	{"([", "])", shapeRound},
+	{"[(", ")]", shapeBox},
+	{"[[", "]]", shapeBox},
+	{"{{", "}}", shapeDecision},
+	{"[", "]", shapeBox},
+	{"(", ")", shapeRound},
+	{"{", "}", shapeDecision},
+	{">", "]", shapeBox},
+}
+
+// Statements that do not add nodes or edges
+var flowchartSkip = []string{"subgraph ", "classDef ", "class ", "style ", "linkStyle", "click ", "direction "}
+
+// parseFlowchart reads a flowchart whose first line is header, such as
+// "graph TD"
+func parseFlowchart(header string, lines []string) (*graph, error) {
+	g := newGraph()
+	fields := strings.Fields(header)
+	if len(fields) > 1 {
+		switch strings.ToUpper(fields[1]) {
+		case "LR":
+			g.horizontal = true
+		case "RL":
+			g.horizontal, g.reverse = true, true
+		case "BT":
+			g.reverse = true
+		}
+	}
+
+	for _, line := range lines {
+		for _, statement := range strings.Split(line, ";") {
+			statement = strings.TrimSpace(statement)
+			if statement == "" || statement == "end" || hasAnyPrefix(statement, flowchartSkip) {
+				continue
+			}
+			if err := g.statement(statement); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+// statement reads a chain of nodes joined by links, like "A --> B & C"
+func (g *graph) statement(s string) error {
+	var prev []*node
+	var link *edge
+	for {
+		var nodes []*node
+		for {
+			n, rest, err := g.parseNode(s)
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, n)
+			s = strings.TrimSpace(rest)
+			if !strings.HasPrefix(s, "&") {
+				break
+			}
+			s = strings.TrimSpace(s[1:])
+		}
+		if link != nil {
+			for _, from := range prev {
+				for _, to := range nodes {
+					g.connect(from, to, link.label, link.dashed, link.arrow)
+				}
+			}
+		}
+		if s == "" {
+			return nil
+		}
+
+		prev = nodes
+		link = &edge{}
+		if m := textLink.FindStringSubmatch(s); m != nil {
+			link.label = cleanLabel(m[2])
+			link.dashed = strings.Contains(m[1], ".")
+			link.arrow = m[4] != ""
+			s = s[len(m[0]):]
+		} else if m := plainLink.FindStringSubmatch(s); m != nil {
+			link.label = cleanLabel(m[3])
+			link.dashed = strings.Contains(m[1], ".")
+			link.arrow = m[2] != ""
+			s = s[len(m[0]):]
+		} else {
+			return fmt.Errorf("expected a link at %q", s)
+		}
+		s = strings.TrimSpace(s)
+	}
+}
+
+// parseNode reads a node reference with an optional shape and label, and
+// returns the rest of s
+func (g *graph) parseNode(s string) (*node, string, error) {
+	id := nodeID.FindString(s)
+	if id == "" {
+		return nil, "", fmt.Errorf("expected a node at %q", s)
+	}
+	n := g.node(id)
+	s = s[len(id):]
+	for _, d := range shapeDelimiters {
+		if !strings.HasPrefix(s, d.open) {
+			continue
+		}
+		end := strings.Index(s[len(d.open):], d.close)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unclosed %q in %q", d.open, id+s)
+		}
+		n.label = cleanLabel(strings.Trim(s[len(d.open):len(d.open)+end], `/\`))
+		n.shape = d.shape
+		s = s[len(d.open)+end+len(d.close):]
+		break
+	}
+	// Class shorthand, like A:::warning
+	if strings.HasPrefix(s, ":::") {
+		s = strings.TrimLeft(s[3:], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-")
+	}
+	return n, s, nil
+}
+
+// lineBreak matches the <br> tags labels use for line breaks
+var lineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// cleanLabel trims quotes and line breaks from a label
+func cleanLabel(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return strings.Join(strings.Fields(lineBreak.ReplaceAllString(s, " ")), " ")
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,530 @@
+package diagram
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// shape is how a graph node is drawn
+type shape int
+
+const (
+	shapeBox shape = iota
+	shapeRound
+	shapeDecision
+	shapeStart
+	shapeEnd
+	// shapeDummy carries an edge through a layer it skips
+	shapeDummy
+)
+
+type node struct {
+	id    string
+	label string
+	shape shape
+	// loops are the labels of edges from the node to itself
+	loops []string
+
+	layer      int
+	x, y, w, h int
+}
+
+type edge struct {
+	from, to *node
+	label    string
+	dashed   bool
+	arrow    bool
+}
+
+// graph is a flowchart or state diagram, laid out in layers from top to
+// bottom, or from left to right when horizontal
+type graph struct {
+	horizontal bool
+	// reverse runs the layers from bottom to top or right to left
+	reverse bool
+	nodes   []*node
+	byID    map[string]*node
+	edges   []*edge
+}
+
+func newGraph() *graph {
+	return &graph{byID: make(map[string]*node)}
+}
+
+// node returns the node with the given id, adding it when it is new
+func (g *graph) node(id string) *node {
+	if n, ok := g.byID[id]; ok {
+		return n
+	}
+	n := &node{id: id, label: id}
+	g.nodes = append(g.nodes, n)
+	g.byID[id] = n
+	return n
+}
+
+func (g *graph) connect(from, to *node, label string, dashed, arrow bool) {
+	g.edges = append(g.edges, &edge{from: from, to: to, label: label, dashed: dashed, arrow: arrow})
+}
+
+// route is the path of an edge through the layers, from the upper end to
+// the lower one. Back routes point against the layer order.
+type route struct {
+	edge  *edge
+	nodes []*node
+	back  bool
+}
+
+// Gaps between nodes in a layer and between layers
+const (
+	nodeGap       = 3
+	horizontalGap = 1
+	layerGap      = 3
+	sideGap       = 4
+)
+
+// draw lays the graph out and draws it
+func (g *graph) draw() *canvas {
+	for _, n := range g.nodes {
+		n.loops = nil
+		n.w, n.h = runewidth.StringWidth(n.label)+4, 3
+		if n.shape == shapeStart || n.shape == shapeEnd {
+			n.w, n.h = 1, 1
+		}
+	}
+
+	routes := g.routes()
+	layers := g.layer(routes)
+	g.order(layers, routes)
+	g.position(layers, routes)
+
+	c := &canvas{}
+	for _, r := range routes {
+		g.drawRoute(c, r, layers)
+	}
+	for _, n := range g.nodes {
+		switch n.shape {
+		case shapeBox:
+			c.box(n.x, n.y, n.w, n.h, squareBorder, n.label)
+		case shapeRound:
+			c.box(n.x, n.y, n.w, n.h, roundBorder, n.label)
+		case shapeDecision:
+			c.box(n.x, n.y, n.w, n.h, decisionBorder, n.label)
+		case shapeStart:
+			c.set(n.x, n.y, '●')
+		case shapeEnd:
+			c.set(n.x, n.y, '◉')
+		}
+		for i, label := range n.loops {
+			c.text(n.x+n.w+1, n.y+n.h/2+i, strings.TrimSpace("↺ "+label))
+		}
+	}
+	return c
+}
+
+// routes turns the edges into routes between their ends, reversing the
+// edges that close a cycle. Edges from a node to itself are kept as loops.
+func (g *graph) routes() []*route {
+	var routes []*route
+	out := make(map[*node][]*route)
+	for _, e := range g.edges {
+		if e.from == e.to {
+			e.from.loops = append(e.from.loops, e.label)
+			continue
+		}
+		r := &route{edge: e, nodes: []*node{e.from, e.to}, back: g.reverse}
+		if g.reverse {
+			r.nodes = []*node{e.to, e.from}
+		}
+		routes = append(routes, r)
+		out[r.nodes[0]] = append(out[r.nodes[0]], r)
+	}
+
+	// Depth-first search; an edge to a node still on the stack closes a cycle
+	state := make(map[*node]int)
+	var visit func(n *node)
+	visit = func(n *node) {
+		state[n] = 1
+		for _, r := range out[n] {
+			to := r.nodes[1]
+			switch state[to] {
+			case 0:
+				visit(to)
+			case 1:
+				r.nodes[0], r.nodes[1] = r.nodes[1], r.nodes[0]
+				r.back = !r.back
+			}
+		}
+		state[n] = 2
+	}
+	for _, n := range g.nodes {
+		if state[n] == 0 {
+			visit(n)
+		}
+	}
+	return routes
+}
+
+// layer puts every node in the layer after the deepest node leading to
+// it, and adds dummy nodes where routes skip layers
+func (g *graph) layer(routes []*route) [][]*node {
+	in := make(map[*node]int)
+	out := make(map[*node][]*route)
+	for _, r := range routes {
+		in[r.nodes[1]]++
+		out[r.nodes[0]] = append(out[r.nodes[0]], r)
+	}
+	var queue []*node
+	for _, n := range g.nodes {
+		n.layer = 0
+		if in[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, r := range out[n] {
+			to := r.nodes[1]
+			to.layer = max(to.layer, n.layer+1)
+			if in[to]--; in[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	var layers [][]*node
+	add := func(n *node) {
+		for len(layers) <= n.layer {
+			layers = append(layers, nil)
+		}
+		layers[n.layer] = append(layers[n.layer], n)
+	}
+	for _, n := range g.nodes {
+		add(n)
+	}
+	for _, r := range routes {
+		from, to := r.nodes[0], r.nodes[1]
+		nodes := []*node{from}
+		for l := from.layer + 1; l < to.layer; l++ {
+			dummy := &node{shape: shapeDummy, layer: l, w: 1, h: 1}
+			add(dummy)
+			nodes = append(nodes, dummy)
+		}
+		r.nodes = append(nodes, to)
+	}
+	return layers
+}
+
+// order sorts the nodes of each layer by the mean position of their
+// neighbours in the layer before, then after, to cut down on crossings
+func (g *graph) order(layers [][]*node, routes []*route) {
+	above := make(map[*node][]*node)
+	below := make(map[*node][]*node)
+	for _, r := range routes {
+		for i := 1; i < len(r.nodes); i++ {
+			above[r.nodes[i]] = append(above[r.nodes[i]], r.nodes[i-1])
+			below[r.nodes[i-1]] = append(below[r.nodes[i-1]], r.nodes[i])
+		}
+	}
+
+	index := make(map[*node]int)
+	reindex := func(layer []*node) {
+		for i, n := range layer {
+			index[n] = i
+		}
+	}
+	for _, layer := range layers {
+		reindex(layer)
+	}
+	sortBy := func(layer []*node, neighbours map[*node][]*node) {
+		keys := make(map[*node]float64)
+		for _, n := range layer {
+			keys[n] = float64(index[n])
+			if ns := neighbours[n]; len(ns) > 0 {
+				sum := 0
+				for _, m := range ns {
+					sum += index[m]
+				}
+				keys[n] = float64(sum) / float64(len(ns))
+			}
+		}
+		sort.SliceStable(layer, func(i, j int) bool { return keys[layer[i]] < keys[layer[j]] })
+		reindex(layer)
+	}
+
+	for pass := 0; pass < 4; pass++ {
+		for l := 1; l < len(layers); l++ {
+			sortBy(layers[l], above)
+		}
+		for l := len(layers) - 2; l >= 0; l-- {
+			sortBy(layers[l], below)
+		}
+	}
+}
+
+// extent returns the size of n along its layer
+func (g *graph) extent(n *node) int {
+	if g.horizontal {
+		return n.h
+	}
+	return n.w
+}
+
+// position places the layers one after another and the nodes of each
+// layer as close to the middle of the nodes above them as they fit
+func (g *graph) position(layers [][]*node, routes []*route) {
+	above := make(map[*node][]*node)
+	for _, r := range routes {
+		for i := 1; i < len(r.nodes); i++ {
+			above[r.nodes[i]] = append(above[r.nodes[i]], r.nodes[i-1])
+		}
+	}
+	gap := nodeGap
+	if g.horizontal {
+		gap = horizontalGap
+	}
+
+	// Positions along the layer, as the left or top edge of each node
+	pos := make(map[*node]int)
+	for _, layer := range layers {
+		desired := make([]float64, len(layer))
+		for i, n := range layer {
+			ns := above[n]
+			if len(ns) == 0 {
+				if i == 0 {
+					desired[i] = float64(g.extent(n)) / 2
+				} else {
+					desired[i] = desired[i-1] + float64(g.extent(layer[i-1])+g.extent(n))/2 + float64(gap)
+				}
+				continue
+			}
+			sum := 0.0
+			for _, m := range ns {
+				sum += float64(pos[m]) + float64(g.extent(m))/2
+			}
+			desired[i] = sum / float64(len(ns))
+		}
+		for i, p := range g.pack(layer, desired, gap) {
+			pos[layer[i]] = p
+		}
+	}
+	least := math.MaxInt
+	for _, p := range pos {
+		least = min(least, p)
+	}
+
+	// Thickness of each layer, and the gap after it
+	offset := 0
+	for l, layer := range layers {
+		thick := 0
+		for _, n := range layer {
+			if g.horizontal {
+				thick = max(thick, n.w)
+			} else {
+				thick = max(thick, n.h)
+			}
+		}
+		for _, n := range layer {
+			p := pos[n] - least
+			if g.horizontal {
+				n.x, n.y = offset+(thick-n.w)/2, p
+			} else {
+				n.x, n.y = p, offset+(thick-n.h)/2
+			}
+		}
+		offset += thick + g.layerGap(l, routes)
+	}
+}
+
+// layerGap returns the room left after layer l for the routes leaving it
+func (g *graph) layerGap(l int, routes []*route) int {
+	label := 0
+	for _, r := range routes {
+		for i := 0; i < len(r.nodes)-1; i++ {
+			if r.nodes[i].layer == l && r.edge.label != "" && i == labelSegment(r) {
+				label = max(label, runewidth.StringWidth(r.edge.label))
+			}
+		}
+	}
+	switch {
+	case g.horizontal && label > 0:
+		return sideGap + label + 2
+	case g.horizontal:
+		return sideGap
+	case label > 0:
+		return layerGap + 1
+	}
+	return layerGap
+}
+
+// labelSegment returns the segment of a route its label is written
+// beside, the one with the arrow
+func labelSegment(r *route) int {
+	if r.back {
+		return 0
+	}
+	return len(r.nodes) - 2
+}
+
+// pack returns the start positions of nodes in a layer, each as close to
+// its desired center as the nodes before it allow. Nodes that crowd each
+// other are moved as one block to the mean of their desired positions.
+func (g *graph) pack(layer []*node, desired []float64, gap int) []int {
+	type block struct {
+		first, last int
+		start       float64
+	}
+	// offsets within the block starting at each node
+	offset := func(first, i int) int {
+		off := 0
+		for j := first; j < i; j++ {
+			off += g.extent(layer[j]) + gap
+		}
+		return off
+	}
+	startOf := func(b block) float64 {
+		sum := 0.0
+		for i := b.first; i <= b.last; i++ {
+			sum += desired[i] - float64(g.extent(layer[i]))/2 - float64(offset(b.first, i))
+		}
+		return sum / float64(b.last-b.first+1)
+	}
+
+	var blocks []block
+	for i := range layer {
+		b := block{first: i, last: i}
+		b.start = startOf(b)
+		for len(blocks) > 0 {
+			prev := blocks[len(blocks)-1]
+			end := prev.start + float64(offset(prev.first, prev.last)+g.extent(layer[prev.last])+gap)
+			if end <= b.start {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			b = block{first: prev.first, last: i}
+			b.start = startOf(b)
+		}
+		blocks = append(blocks, b)
+	}
+
+	positions := make([]int, len(layer))
+	for _, b := range blocks {
+		start := int(math.Round(b.start))
+		for i := b.first; i <= b.last; i++ {
+			positions[i] = start + offset(b.first, i)
+		}
+	}
+	// Rounding may leave neighbouring blocks touching
+	for i := 1; i < len(positions); i++ {
+		positions[i] = max(positions[i], positions[i-1]+g.extent(layer[i-1])+gap)
+	}
+	return positions
+}
+
+// drawRoute draws the lines of a route, bending halfway between layers,
+// with its arrow and label
+func (g *graph) drawRoute(c *canvas, r *route, layers [][]*node) {
+	e := r.edge
+	last := len(r.nodes) - 2
+	for i := 0; i <= last; i++ {
+		u, v := r.nodes[i], r.nodes[i+1]
+		labelled := e.label != "" && i == labelSegment(r)
+
+		if g.horizontal {
+			sx, sy := u.x+u.w-1, u.y+u.h/2
+			tx, ty := v.x, v.y+v.h/2
+			xm := layerEnd(layers[u.layer], true) + 2
+			c.path(e.dashed, [2]int{sx, sy}, [2]int{xm, sy}, [2]int{xm, ty}, [2]int{tx, ty})
+			switch {
+			case labelled && r.back:
+				c.text(xm+2, sy, e.label)
+			case labelled:
+				c.text(xm+1, ty, " "+e.label+" ")
+			}
+			if e.arrow && !r.back && i == last {
+				c.set(tx-1, ty, '▶')
+			}
+			if e.arrow && r.back && i == 0 {
+				c.set(sx+1, sy, '◀')
+			}
+			continue
+		}
+
+		sx, sy := u.x+u.w/2, u.y+u.h-1
+		tx, ty := v.x+v.w/2, v.y
+		ym := layerEnd(layers[u.layer], false) + 2
+		c.path(e.dashed, [2]int{sx, sy}, [2]int{sx, ym}, [2]int{tx, ym}, [2]int{tx, ty})
+		switch {
+		case labelled && r.back:
+			c.text(sx+2, sy+1, e.label)
+		case labelled:
+			c.text(tx+2, ym+1, e.label)
+		}
+		if e.arrow && !r.back && i == last {
+			c.set(tx, ty-1, '▼')
+		}
+		if e.arrow && r.back && i == 0 {
+			c.set(sx, sy+1, '▲')
+		}
+	}
+}
+
+// layerEnd returns the last row, or column when horizontal, of a layer
+func layerEnd(layer []*node, horizontal bool) int {
+	end := 0
+	for _, n := range layer {
+		if horizontal {
+			end = max(end, n.x+n.w-1)
+		} else {
+			end = max(end, n.y+n.h-1)
+		}
+	}
+	return end
+}
+
+// list writes the graph as one line per edge, for diagrams too wide to draw
+func (g *graph) list() []string {
+	name := func(n *node) string {
+		switch n.shape {
+		case shapeStart:
+			return "●"
+		case shapeEnd:
+			return "◉"
+		}
+		return "[" + n.label + "]"
+	}
+	var lines []string
+	for _, e := range g.edges {
+		line := "──"
+		if e.dashed {
+			line = "┄┄"
+		}
+		arrow := line
+		if e.label != "" {
+			arrow += " " + e.label + " " + line
+		}
+		if e.arrow {
+			arrow += "▶"
+		}
+		lines = append(lines, name(e.from)+" "+arrow+" "+name(e.to))
+	}
+	for _, n := range g.nodes {
+		if !g.connected(n) {
+			lines = append(lines, name(n))
+		}
+	}
+	return lines
+}
+
+// connected reports whether n is the end of any edge
+func (g *graph) connected(n *node) bool {
+	for _, e := range g.edges {
+		if e.from == n || e.to == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	// participantLine matches "participant A" or "actor A as Alice"
+	participantLine = regexp.MustCompile(`^(?:participant|actor)\s+(.+?)(?:\s+as\s+(.+))?$`)
+	// messageLine matches "A->>B: text" and the other arrow forms
+	messageLine = regexp.MustCompile(`^([^-+>:]+?)\s*(--?)(>>|>|x|\))\s*[+-]?\s*([^:]+?)\s*(?::\s?(.*))?$`)
+	// noteLine matches "Note right of A: text" and "Note over A,B: text"
+	noteLine = regexp.MustCompile(`^(?i:note)\s+(left of|right of|over)\s+([^:]+?)\s*:\s?(.*)$`)
+	// blockLine matches the start of a loop, alt or similar block
+	blockLine = regexp.MustCompile(`^(loop|alt|opt|par|critical|break|rect)\b\s*(.*)$`)
+	// dividerLine matches the start of another branch of a block
+	dividerLine = regexp.MustCompile(`^(else|and|option)\b\s*(.*)$`)
+)
+
+// Statements of a sequence diagram that change nothing drawn
+var sequenceSkip = []string{"activate ", "deactivate ", "title ", "title:", "links ", "link ", "box ", "create ", "destroy "}
+
+type eventKind int
+
+const (
+	eventMessage eventKind = iota
+	eventNote
+	eventBlock
+	eventDivider
+	eventEnd
+)
+
+// event is one row of a sequence diagram, such as a message or a note
+type event struct {
+	kind     eventKind
+	from, to int
+	text     string
+	dashed   bool
+	head     string
+	// side is where a note goes: "left of", "right of" or "over"
+	side string
+}
+
+type participant struct {
+	label  string
+	w      int
+	center int
+}
+
+type sequence struct {
+	participants []*participant
+	byID         map[string]int
+	events       []event
+}
+
+// parseSequence reads a sequence diagram
+func parseSequence(lines []string) (*sequence, error) {
+	s := &sequence{byID: make(map[string]int)}
+	number := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || hasAnyPrefix(line, sequenceSkip):
+			continue
+		case line == "autonumber":
+			number = 1
+			continue
+		case line == "end":
+			s.events = append(s.events, event{kind: eventEnd})
+			continue
+		}
+
+		if m := participantLine.FindStringSubmatch(line); m != nil {
+			i := s.participant(m[1])
+			if m[2] != "" {
+				s.participants[i].label = cleanLabel(m[2])
+			}
+			continue
+		}
+		if m := noteLine.FindStringSubmatch(line); m != nil {
+			ids := strings.Split(m[2], ",")
+			e := event{kind: eventNote, side: strings.ToLower(m[1]), text: cleanLabel(m[3])}
+			e.from = s.participant(ids[0])
+			e.to = s.participant(ids[len(ids)-1])
+			if e.from > e.to {
+				e.from, e.to = e.to, e.from
+			}
+			s.events = append(s.events, e)
+			continue
+		}
+		if m := blockLine.FindStringSubmatch(line); m != nil {
+			s.events = append(s.events, event{kind: eventBlock, text: strings.TrimSpace(m[1] + " " + cleanLabel(m[2]))})
+			continue
+		}
+		if m := dividerLine.FindStringSubmatch(line); m != nil {
+			s.events = append(s.events, event{kind: eventDivider, text: strings.TrimSpace(m[1] + " " + cleanLabel(m[2]))})
+			continue
+		}
+		if m := messageLine.FindStringSubmatch(line); m != nil {
+			e := event{
+				kind:   eventMessage,
+				from:   s.participant(m[1]),
+				to:     s.participant(m[4]),
+				text:   cleanLabel(m[5]),
+				dashed: m[2] == "--",
+				head:   m[3],
+			}
+			if number > 0 {
+				e.text = strings.TrimSpace(fmt.Sprintf("%d. %s", number, e.text))
+				number++
+			}
+			s.events = append(s.events, e)
+			continue
+		}
+		return nil, fmt.Errorf("cannot read %q", line)
+	}
+	return s, nil
+}
+
+// participant returns the index of the participant with the given id,
+// adding it when it is new
+func (s *sequence) participant(id string) int {
+	id = strings.TrimSpace(id)
+	if i, ok := s.byID[id]; ok {
+		return i
+	}
+	s.participants = append(s.participants, &participant{label: id})
+	s.byID[id] = len(s.participants) - 1
+	return len(s.participants) - 1
+}
+
+// span is a minimum distance between the lifelines of two participants
+type span struct {
+	from, to, distance int
+}
+
+// layout spaces the lifelines so every message, note and box fits
+func (s *sequence) layout() {
+	var spans []span
+	leftmost := 0
+	for i, p := range s.participants {
+		p.w = runewidth.StringWidth(p.label) + 4
+		leftmost = max(leftmost, p.w/2)
+		if i > 0 {
+			prev := s.participants[i-1]
+			spans = append(spans, span{i - 1, i, prev.w - prev.w/2 + p.w/2 + 2})
+		}
+	}
+	last := len(s.participants) - 1
+	for _, e := range s.events {
+		width := runewidth.StringWidth(e.text)
+		switch {
+		case e.kind == eventMessage && e.from != e.to:
+			spans = append(spans, span{min(e.from, e.to), max(e.from, e.to), width + 4})
+		case e.kind == eventMessage && e.from < last:
+			spans = append(spans, span{e.from, e.from + 1, width + 4})
+		case e.kind == eventNote && e.side == "over" && e.from != e.to:
+			spans = append(spans, span{e.from, e.to, width})
+		case e.kind == eventNote && e.side == "over":
+			leftmost = max(leftmost, (width+4)/2)
+		case e.kind == eventNote && e.side == "right of" && e.from < last:
+			spans = append(spans, span{e.from, e.from + 1, width + 7})
+		case e.kind == eventNote && e.side == "left of" && e.from > 0:
+			spans = append(spans, span{e.from - 1, e.from, width + 7})
+		case e.kind == eventNote && e.side == "left of":
+			leftmost = max(leftmost, width+6)
+		}
+	}
+
+	// Widen the last gap of each span that is too narrow, shortest first
+	gaps := make([]int, len(s.participants))
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].to-spans[i].from < spans[j].to-spans[j].from })
+	for _, sp := range spans {
+		distance := 0
+		for i := sp.from; i < sp.to; i++ {
+			distance += gaps[i]
+		}
+		if distance < sp.distance {
+			gaps[sp.to-1] += sp.distance - distance
+		}
+	}
+
+	center := leftmost
+	for i, p := range s.participants {
+		p.center = center
+		center += gaps[i]
+	}
+}
+
+// draw lays the diagram out and draws it, with the participants above and
+// below their lifelines
+func (s *sequence) draw() *canvas {
+	s.layout()
+	c := &canvas{}
+	if len(s.participants) == 0 {
+		return c
+	}
+	first, last := s.participants[0], s.participants[len(s.participants)-1]
+	left, right := first.center-first.w/2, last.center+last.w/2
+
+	y := 3
+	for _, e := range s.events {
+		switch e.kind {
+		case eventMessage:
+			y = s.drawMessage(c, e, y)
+		case eventNote:
+			s.drawNote(c, e, y)
+			y += 3
+		case eventBlock, eventDivider, eventEnd:
+			c.hline(left, right, y, true)
+			if e.text != "" {
+				c.text(left+1, y, "["+e.text+"]")
+			}
+			y++
+		}
+	}
+
+	for _, p := range s.participants {
+		c.vline(p.center, 3, y, false)
+		c.box(p.center-p.w/2, 0, p.w, 3, squareBorder, p.label)
+		c.box(p.center-p.w/2, y, p.w, 3, squareBorder, p.label)
+	}
+	return c
+}
+
+// drawMessage draws a message from row y and returns the row after it
+func (s *sequence) drawMessage(c *canvas, e event, y int) int {
+	from, to := s.participants[e.from].center, s.participants[e.to].center
+	heads := map[string][2]rune{">>": {'▶', '◀'}, "x": {'×', '×'}, ")": {'▷', '◁'}}
+	head, hasHead := heads[e.head]
+
+	if from == to {
+		c.text(from+2, y, e.text)
+		c.path(e.dashed, [2]int{from, y + 1}, [2]int{from + 3, y + 1}, [2]int{from + 3, y + 2}, [2]int{from, y + 2})
+		if hasHead {
+			c.set(from+1, y+2, head[1])
+		}
+		return y + 3
+	}
+
+	width := runewidth.StringWidth(e.text)
+	c.text((from+to)/2-width/2, y, e.text)
+	c.hline(from, to, y+1, e.dashed)
+	switch {
+	case hasHead && to > from:
+		c.set(to-1, y+1, head[0])
+	case hasHead:
+		c.set(to+1, y+1, head[1])
+	}
+	return y + 2
+}
+
+// drawNote draws a note box from row y
+func (s *sequence) drawNote(c *canvas, e event, y int) {
+	w := runewidth.StringWidth(e.text) + 4
+	from, to := s.participants[e.from].center, s.participants[e.to].center
+	switch e.side {
+	case "right of":
+		c.box(to+2, y, w, 3, noteBorder, e.text)
+	case "left of":
+		c.box(from-2-w, y, w, 3, noteBorder, e.text)
+	default:
+		w = max(w, to-from+5)
+		c.box((from+to)/2-w/2, y, w, 3, noteBorder, e.text)
+	}
+}
+
+// list writes the diagram as one line per message, for diagrams too wide
+// to draw
+func (s *sequence) list() []string {
+	var lines []string
+	indent := ""
+	for _, e := range s.events {
+		switch e.kind {
+		case eventMessage:
+			arrow := "──"
+			if e.dashed {
+				arrow = "┄┄"
+			}
+			if e.head != ">" {
+				arrow += "▶"
+			}
+			line := s.participants[e.from].label + " " + arrow + " " + s.participants[e.to].label
+			if e.text != "" {
+				line += ": " + e.text
+			}
+			lines = append(lines, indent+line)
+		case eventNote:
+			names := s.participants[e.from].label
+			if e.to != e.from {
+				names += ", " + s.participants[e.to].label
+			}
+			lines = append(lines, indent+"Note "+e.side+" "+names+": "+e.text)
+		case eventBlock:
+			lines = append(lines, indent+"["+e.text+"]")
+			indent += "  "
+		case eventDivider:
+			lines = append(lines, strings.TrimPrefix(indent, "  ")+"["+e.text+"]")
+		case eventEnd:
+			indent = strings.TrimPrefix(indent, "  ")
+			lines = append(lines, indent+"[end]")
+		}
+	}
+	return lines
+}
@@ -0,0 +1,96 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// transition matches "A --> B" with an optional ": label"
+	transition = regexp.MustCompile(`^(\S+)\s*-->\s*(\S+)\s*(?::\s*(.*))?$`)
+	// stateAlias matches `state "Description" as Id`
+	stateAlias = regexp.MustCompile(`^state\s+"([^"]*)"\s+as\s+(\S+)`)
+	// stateDeclaration matches "state Id" with an optional <<choice>> or {
+	stateDeclaration = regexp.MustCompile(`^state\s+(\S+)\s*(<<(\w+)>>)?`)
+	// stateDescription matches "Id : description"
+	stateDescription = regexp.MustCompile(`^(\S+)\s*:\s*(.*)$`)
+)
+
+// parseState reads a state diagram. Composite states are drawn flat, with
+// their inner states alongside the outer ones.
+func parseState(lines []string) (*graph, error) {
+	g := newGraph()
+	state := func(id string, target bool) *node {
+		if id == "[*]" {
+			if target {
+				n := g.node("[*]end")
+				n.shape = shapeEnd
+				return n
+			}
+			n := g.node("[*]start")
+			n.shape = shapeStart
+			return n
+		}
+		n := g.node(id)
+		if n.shape == shapeBox {
+			n.shape = shapeRound
+		}
+		return n
+	}
+
+	inNote := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case inNote:
+			inNote = !strings.HasPrefix(line, "end note")
+			continue
+		case strings.HasPrefix(line, "note "):
+			// Single line notes have their text after a colon
+			inNote = !strings.Contains(line, ":")
+			continue
+		case line == "" || line == "}" || line == "--" || hasAnyPrefix(line, []string{"classDef ", "class ", "style "}):
+			continue
+		case strings.HasPrefix(line, "direction "):
+			switch strings.ToUpper(strings.TrimSpace(line[len("direction "):])) {
+			case "LR":
+				g.horizontal, g.reverse = true, false
+			case "RL":
+				g.horizontal, g.reverse = true, true
+			case "BT":
+				g.horizontal, g.reverse = false, true
+			default:
+				g.horizontal, g.reverse = false, false
+			}
+			continue
+		}
+
+		if m := transition.FindStringSubmatch(line); m != nil {
+			g.connect(state(m[1], false), state(m[2], true), cleanLabel(m[3]), false, true)
+			continue
+		}
+		if m := stateAlias.FindStringSubmatch(line); m != nil {
+			state(m[2], false).label = cleanLabel(m[1])
+			continue
+		}
+		if m := stateDeclaration.FindStringSubmatch(line); m != nil {
+			n := state(strings.TrimSuffix(m[1], "{"), false)
+			if m[3] == "choice" {
+				n.shape = shapeDecision
+				n.label = ""
+			}
+			continue
+		}
+		if m := stateDescription.FindStringSubmatch(line); m != nil {
+			state(m[1], false).label = cleanLabel(m[2])
+			continue
+		}
+		if !strings.ContainsAny(line, " \t:") {
+			state(line, false)
+			continue
+		}
+		return nil, fmt.Errorf("cannot read %q", line)
+	}
+	return g, nil
+}
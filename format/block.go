@@ -0,0 +1,373 @@
+package format
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	mathjax "github.com/litao91/goldmark-mathjax"
+	"github.com/mattn/go-runewidth"
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
+	"go.abhg.dev/goldmark/mermaid"
+)
+
+// minWidth is the narrowest wrapping width given to nested blocks
+const minWidth = 20
+
+// printer writes the blocks of a parsed document back as Markdown
+type printer struct {
+	source []byte
+	width  int
+	// refs are the link reference definitions by normalized label
+	refs map[string]parser.Reference
+	// used lists the definitions referenced so far, in order
+	used []string
+	// inTable escapes pipes in code, which would split table cells
+	inTable bool
+	// bullet is the marker of the innermost bullet list
+	bullet string
+	// keepEmphasis writes emphasis with the delimiters of the input
+	keepEmphasis bool
+}
+
+// narrower returns the wrapping width left after indenting by n columns
+func narrower(width, n int) int {
+	if width <= 0 {
+		return 0
+	}
+	if width-n < minWidth {
+		return minWidth
+	}
+	return width - n
+}
+
+// children returns the lines of the blocks below n. Blocks are separated
+// by blank lines, except in tight lists.
+func (p *printer) children(n ast.Node, width int, tight bool) []string {
+	var lines []string
+	alternate := false
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		prev := c.PreviousSibling()
+		var block []string
+		switch c := c.(type) {
+		case *ast.List:
+			// Adjacent lists only stay apart with different markers
+			previous, ok := prev.(*ast.List)
+			alternate = ok && previous.IsOrdered() == c.IsOrdered() && !alternate
+			block = p.list(c, width, alternate)
+		case *ast.ThematicBreak:
+			// "---" right below a line of text would make it a heading, at
+			// the start of the document front matter, and after a "-" bullet
+			// one thematic break
+			block = []string{"---"}
+			if (tight && prev != nil && prev.Kind() == ast.KindTextBlock) ||
+				(len(lines) == 0 && n.Kind() == ast.KindDocument) ||
+				(n.Kind() == ast.KindListItem && p.bullet == "-") {
+				block = []string{"***"}
+			}
+		default:
+			block = p.block(c, width)
+		}
+		if _, ok := c.(*ast.List); !ok {
+			alternate = false
+		}
+		// Paragraphs of link reference definitions are left empty
+		if len(block) == 0 {
+			continue
+		}
+		if len(lines) > 0 && !tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+// block returns the lines of a block node
+func (p *printer) block(n ast.Node, width int) []string {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		if !n.HasChildren() {
+			return nil
+		}
+		return wrap(p.inlines(n), width)
+	case *ast.Heading:
+		return p.heading(n)
+	case *ast.Blockquote:
+		lines := []string{}
+		for _, line := range p.children(n, narrower(width, 2), false) {
+			if line == "" {
+				lines = append(lines, ">")
+				continue
+			}
+			lines = append(lines, "> "+line)
+		}
+		if len(lines) == 0 {
+			return []string{">"}
+		}
+		return lines
+	case *ast.List:
+		return p.list(n, width, false)
+	case *ast.FencedCodeBlock:
+		info := ""
+		if n.Info != nil {
+			info = strings.TrimSpace(string(n.Info.Segment.Value(p.source)))
+		}
+		return fence(info, p.lines(n))
+	case *mermaid.Block:
+		return fence("mermaid", p.lines(n))
+	case *mathjax.MathBlock:
+		lines := append([]string{"$$"}, p.lines(n)...)
+		return append(lines, "$$")
+	case *ast.CodeBlock:
+		var lines []string
+		for _, line := range p.lines(n) {
+			if strings.TrimSpace(line) == "" {
+				lines = append(lines, "")
+				continue
+			}
+			lines = append(lines, "    "+line)
+		}
+		return lines
+	case *ast.HTMLBlock:
+		lines := p.lines(n)
+		if n.HasClosure() {
+			lines = append(lines, strings.TrimSuffix(string(n.ClosureLine.Value(p.source)), "\n"))
+		}
+		return lines
+	case *extast.Table:
+		return p.table(n)
+	case *renderer.TOC:
+		var lines []string
+		for _, line := range p.lines(n) {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+		return lines
+	}
+	// Blocks of unknown extensions are kept as written
+	return p.lines(n)
+}
+
+// lines returns the source lines of a block, without line endings
+func (p *printer) lines(n ast.Node) []string {
+	var lines []string
+	for i := 0; i < n.Lines().Len(); i++ {
+		segment := n.Lines().At(i)
+		line := strings.Repeat(" ", segment.Padding) + string(segment.Value(p.source))
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	return lines
+}
+
+// fence returns a fenced code block long enough not to be closed early
+func fence(info string, lines []string) []string {
+	marker := "```"
+	if strings.Contains(info, "`") {
+		marker = "~~~"
+	}
+	for _, line := range lines {
+		for strings.HasPrefix(strings.TrimLeft(line, " "), marker) {
+			marker += marker[:1]
+		}
+	}
+
+	out := []string{marker + info}
+	out = append(out, lines...)
+	return append(out, marker)
+}
+
+// heading writes ATX headings, keeping the text as written because the
+// heading ID is derived from it. Headings spanning several lines stay in
+// the underlined form, as the ID only uses their last line.
+func (p *printer) heading(n *ast.Heading) []string {
+	var text []string
+	for _, line := range p.lines(n) {
+		text = append(text, strings.TrimSpace(line))
+	}
+	if len(text) > 1 {
+		underline := "="
+		if n.Level == 2 {
+			underline = "-"
+		}
+		longest := 3
+		for _, line := range text {
+			if w := runewidth.StringWidth(line); w > longest {
+				longest = w
+			}
+		}
+		return append(text, strings.Repeat(underline, longest))
+	}
+
+	marker := strings.Repeat("#", n.Level)
+	if len(text) == 0 || text[0] == "" {
+		return []string{marker}
+	}
+	return []string{marker + " " + text[0]}
+}
+
+// list writes "-" bullets or "1." numbers. The alternate markers "*" and
+// "1)" keep a list apart from the list before it.
+func (p *printer) list(n *ast.List, width int, alternate bool) []string {
+	bullet, delimiter := "-", "."
+	if alternate {
+		bullet, delimiter = "*", ")"
+	}
+	if !n.IsOrdered() {
+		defer func(outer string) { p.bullet = outer }(p.bullet)
+		p.bullet = bullet
+	}
+
+	var lines []string
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		if item != n.FirstChild() && !n.IsTight {
+			lines = append(lines, "")
+		}
+		marker := bullet
+		if n.IsOrdered() {
+			marker = strconv.Itoa(number) + delimiter
+			number++
+		}
+		indent := len(marker) + 1
+
+		content := p.children(item, narrower(width, indent), n.IsTight)
+		if len(content) == 0 {
+			lines = append(lines, marker)
+			continue
+		}
+		for i, line := range content {
+			switch {
+			case i == 0 && line == "":
+				line = marker
+			case i == 0:
+				line = marker + " " + line
+			case line != "":
+				line = strings.Repeat(" ", indent) + line
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// table writes a GFM table with its columns padded to the same width
+func (p *printer) table(n *extast.Table) []string {
+	p.inTable = true
+	defer func() { p.inTable = false }()
+
+	var rows [][]string
+	var widths []int
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			text := strings.NewReplacer("\n", " ", space, " ", softBreak, " ").Replace(p.inlines(cell))
+			cells = append(cells, text)
+
+			i := len(cells) - 1
+			if i >= len(widths) {
+				widths = append(widths, 3)
+			}
+			if w := runewidth.StringWidth(text); w > widths[i] {
+				widths[i] = w
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	alignment := func(i int) extast.Alignment {
+		if i < len(n.Alignments) {
+			return n.Alignments[i]
+		}
+		return extast.AlignNone
+	}
+
+	var lines []string
+	for r, cells := range rows {
+		var b strings.Builder
+		b.WriteString("|")
+		for i, cell := range cells {
+			padding := widths[i] - runewidth.StringWidth(cell)
+			left := 0
+			switch alignment(i) {
+			case extast.AlignRight:
+				left = padding
+			case extast.AlignCenter:
+				left = padding / 2
+			}
+			b.WriteString(" " + strings.Repeat(" ", left) + cell + strings.Repeat(" ", padding-left) + " |")
+		}
+		lines = append(lines, b.String())
+
+		if r == 0 {
+			var d strings.Builder
+			d.WriteString("|")
+			for i := range cells {
+				dashes := strings.Repeat("-", widths[i])
+				switch alignment(i) {
+				case extast.AlignLeft:
+					dashes = ":" + dashes[1:]
+				case extast.AlignRight:
+					dashes = dashes[1:] + ":"
+				case extast.AlignCenter:
+					dashes = ":" + dashes[2:] + ":"
+				}
+				d.WriteString(" " + dashes + " |")
+			}
+			lines = append(lines, d.String())
+		}
+	}
+	return lines
+}
+
+// definitions returns the link reference definitions, the ones in use
+// first in the order they are used
+func (p *printer) definitions() []string {
+	labels := append([]string(nil), p.used...)
+	used := make(map[string]bool)
+	for _, label := range p.used {
+		used[label] = true
+	}
+	var unused []string
+	for label := range p.refs {
+		if !used[label] {
+			unused = append(unused, label)
+		}
+	}
+	sort.Strings(unused)
+	labels = append(labels, unused...)
+
+	var lines []string
+	for _, label := range labels {
+		ref := p.refs[label]
+		line := "[" + strings.Join(strings.Fields(string(ref.Label())), " ") + "]: "
+		dest := string(ref.Destination())
+		if dest == "" || needsAngles(dest) {
+			dest = "<" + dest + ">"
+		}
+		line += dest
+		if len(ref.Title()) > 0 {
+			line += " " + quoteTitle(string(ref.Title()))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// useReference reports whether label names a definition, recording it
+func (p *printer) useReference(label string) bool {
+	key := util.ToLinkReference([]byte(strings.ReplaceAll(label, space, " ")))
+	if _, ok := p.refs[key]; !ok {
+		return false
+	}
+	for _, used := range p.used {
+		if used == key {
+			return true
+		}
+	}
+	p.used = append(p.used, key)
+	return true
+}
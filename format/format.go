@@ -0,0 +1,112 @@
+// Package format rewrites Markdown documents in one consistent style, the
+// way gofmt does for Go source.
+package format
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
+)
+
+// Options tune the formatted output
+type Options struct {
+	// Width wraps paragraphs at this many columns. Zero keeps the line
+	// breaks of the input.
+	Width int
+}
+
+// ErrChangesOutput is returned when the formatted document would not
+// render to the same HTML as the input
+var ErrChangesOutput = errors.New("formatting would change the rendered HTML")
+
+// Source formats a Markdown document. Front matter is kept as written.
+//
+// The output uses "-" bullets, "1." numbering, "*" emphasis, "**" strong
+// text, fenced code blocks and aligned tables. Heading text is kept as
+// written, since it determines the heading IDs.
+func Source(input string, opts Options) (string, error) {
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	doc, err := renderer.Parse(input)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := write(input, doc, opts, false)
+	if !sameHTML(input, formatted) {
+		// Emphasis next to words or other stars may need the delimiters
+		// it was written with
+		formatted = write(input, doc, opts, true)
+		if !sameHTML(input, formatted) {
+			return "", ErrChangesOutput
+		}
+	}
+	return formatted, nil
+}
+
+// write prints a parsed document back as Markdown
+func write(input string, doc *renderer.Parsed, opts Options, keepEmphasis bool) string {
+	p := &printer{
+		source:       doc.Source,
+		width:        opts.Width,
+		refs:         make(map[string]parser.Reference),
+		keepEmphasis: keepEmphasis,
+	}
+	for _, ref := range doc.References {
+		p.refs[util.ToLinkReference(ref.Label())] = ref
+	}
+
+	lines := p.children(doc.Root, opts.Width, false)
+	if defs := p.definitions(); len(defs) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, defs...)
+	}
+
+	var out strings.Builder
+	if frontMatter := input[:len(input)-len(doc.Source)]; frontMatter != "" {
+		out.WriteString(strings.TrimRight(frontMatter, "\n"))
+		out.WriteString("\n")
+		if len(lines) > 0 {
+			out.WriteString("\n")
+		}
+	}
+	for _, line := range lines {
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+// Rewrapping changes whitespace, so runs of it are collapsed before
+// comparing, and dropped before line breaks and the end of blocks
+var (
+	whitespace = regexp.MustCompile(`\s+`)
+	blockEnd   = regexp.MustCompile(`\s+(<br>|</(?:h[1-6]|p|li|td|th)>)`)
+)
+
+// sameHTML reports whether two documents render to the same HTML, apart
+// from whitespace
+func sameHTML(a, b string) bool {
+	render := func(input string) (string, bool) {
+		doc, err := renderer.Render(renderer.RenderOptions{
+			Input:        input,
+			OutputFormat: "html",
+			Autolink:     true,
+			NoScripts:    true,
+		})
+		if err != nil {
+			return "", false
+		}
+		output := blockEnd.ReplaceAllString(doc.Output, "$1")
+		return whitespace.ReplaceAllString(output, " "), true
+	}
+	htmlA, okA := render(a)
+	htmlB, okB := render(b)
+	return okA && okB && htmlA == htmlB
+}
@@ -0,0 +1,89 @@
+package format
+
+import "testing"
+
+var sourceTests = []struct {
+	name  string
+	input string
+	width int
+	want  string
+}{
+	{"bullets", "* a\n* b\n", 0, "- a\n- b\n"},
+	{"loose list", "+ a\n\n+ b\n", 0, "- a\n\n- b\n"},
+	{"numbering", "1) one\n2) two\n", 0, "1. one\n2. two\n"},
+	{"nested list", "- a\n  - b\n    - c\n", 0, "- a\n  - b\n    - c\n"},
+	{"emphasis", "_em_ and __strong__\n", 0, "*em* and **strong**\n"},
+	{"intraword underscores", "snake_case_word and 2*3*4\n", 0, "snake_case_word and 2*3*4\n"},
+	{"setext headings", "Title\n=====\n\nSub\n---\n", 0, "# Title\n\n## Sub\n"},
+	{"indented code", "    code\n    block\n", 0, "    code\n    block\n"},
+	{"fenced code", "```go\nfunc main() {}\n```\n", 0, "```go\nfunc main() {}\n```\n"},
+	{"table", "|a|b|\n|-|:-:|\n|1|22|\n", 0, "| a   |  b  |\n| --- | :-: |\n| 1   | 22  |\n"},
+	{"front matter", "---\ntitle: X\n---\n\n* a\n", 0, "---\ntitle: X\n---\n\n- a\n"},
+	{"leading thematic break", "---\nJust text.\n---\n\n* a\n", 0, "***\n\n## Just text.\n\n- a\n"},
+	{"thematic break", "***\n\ntext\n", 0, "***\n\ntext\n"},
+	{"reference definition", "[link][ref]\n\n[ref]: http://x.com  \"T\"\n", 0, "[link][ref]\n\n[ref]: http://x.com \"T\"\n"},
+	{"lazy blockquote", "> quote\ncontinued\n", 0, "> quote\n> continued\n"},
+	{"hard break", "line one  \nline two\n", 0, "line one\\\nline two\n"},
+	{"html block", "<div>\nhtml\n</div>\n", 0, "<div>\nhtml\n</div>\n"},
+	{
+		"wrapped paragraph",
+		"A long paragraph with many words that should be wrapped at a narrow width of twenty columns.\n",
+		20,
+		"A long paragraph\nwith many words that\nshould be wrapped at\na narrow width of\ntwenty columns.\n",
+	},
+	{"wrapped blockquote", "> quote\ncontinued\n", 20, "> quote continued\n"},
+	{"crlf", "* a\r\n* b\r\n", 0, "- a\n- b\n"},
+}
+
+func TestSource(t *testing.T) {
+	for _, tt := range sourceTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Source(tt.input, Options{Width: tt.width})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Source(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// Formatting a formatted document must not change it again, or fmt --check
+// would never pass
+func TestSourceIdempotent(t *testing.T) {
+	for _, tt := range sourceTests {
+		for _, width := range []int{0, 20, 80} {
+			once, err := Source(tt.input, Options{Width: width})
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			twice, err := Source(once, Options{Width: width})
+			if err != nil {
+				t.Fatalf("%s: formatting again: %v", tt.name, err)
+			}
+			if once != twice {
+				t.Errorf("%s at width %d is not stable:\n%s", tt.name, width, Diff("once", "twice", once, twice))
+			}
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\n", "a\n", ""},
+		{"changed line", "a\nb\nc\n", "a\nB\nc\n", "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"},
+		{"added line", "a\n", "a\nb\n", "--- old\n+++ new\n@@ -1 +1,2 @@\n a\n+b\n"},
+		{"no final newline", "a", "a\n", "--- old\n+++ new\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n"},
+	}
+
+	for _, tt := range tests {
+		if got := Diff("old", "new", tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: Diff = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package format
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	mathjax "github.com/litao91/goldmark-mathjax"
+	"github.com/mattn/go-runewidth"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+)
+
+// space marks the spaces where a paragraph may wrap, and softBreak the
+// line breaks of the input
+const (
+	space     = "\x00"
+	softBreak = "\x01"
+)
+
+// inlines returns the inline content of a block, with hard line breaks as
+// newlines
+func (p *printer) inlines(n ast.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		p.inline(&b, c)
+	}
+	return b.String()
+}
+
+func (p *printer) inline(b *strings.Builder, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		value := string(n.Segment.Value(p.source))
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			value = strings.TrimRight(value, " \t")
+		}
+		if p.width > 0 {
+			value = strings.ReplaceAll(value, " ", space)
+		}
+		b.WriteString(value)
+		switch {
+		case n.HardLineBreak():
+			b.WriteString("\\\n")
+		case n.SoftLineBreak():
+			b.WriteString(softBreak)
+		}
+	case *ast.String:
+		b.Write(n.Value)
+	case *ast.CodeSpan:
+		b.WriteString(p.delimit(n, '`'))
+	case *mathjax.InlineMath:
+		b.WriteString(p.delimit(n, '$'))
+	case *ast.Emphasis:
+		content := p.inlines(n)
+		// Stars next to the content, such as nested emphasis, would run
+		// into the delimiters
+		char := "*"
+		if p.keepEmphasis {
+			char = string(p.emphasisChar(n))
+		} else if strings.HasPrefix(content, "*") || strings.HasSuffix(content, "*") {
+			char = "_"
+		}
+		delimiter := strings.Repeat(char, n.Level)
+		b.WriteString(delimiter + content + delimiter)
+	case *extast.Strikethrough:
+		b.WriteString("~~")
+		b.WriteString(p.inlines(n))
+		b.WriteString("~~")
+	case *ast.Link:
+		text := p.inlines(n)
+		b.WriteString("[" + text + "]")
+		b.WriteString(p.linkTarget(n, text, n.Destination, n.Title))
+	case *ast.Image:
+		text := p.inlines(n)
+		b.WriteString("![" + text + "]")
+		b.WriteString(p.linkTarget(n, text, n.Destination, n.Title))
+	case *ast.AutoLink:
+		label := n.Label(p.source)
+		offset := offsetOf(p.source, label)
+		if offset > 0 && p.source[offset-1] == '<' {
+			b.WriteString("<" + string(label) + ">")
+		} else {
+			b.Write(label)
+		}
+	case *ast.RawHTML:
+		for i := 0; i < n.Segments.Len(); i++ {
+			segment := n.Segments.At(i)
+			b.Write(segment.Value(p.source))
+		}
+	case *extast.TaskCheckBox:
+		if n.IsChecked {
+			b.WriteString("[x] ")
+		} else {
+			b.WriteString("[ ] ")
+		}
+	default:
+		b.WriteString(p.inlines(n))
+	}
+}
+
+// emphasisChar returns the delimiter an emphasis was written with
+func (p *printer) emphasisChar(n *ast.Emphasis) byte {
+	// The delimiters of n and the emphasis around it precede its content
+	start := -1
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			start = t.Segment.Start
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	run := start
+	for run > 0 && (p.source[run-1] == '*' || p.source[run-1] == '_') {
+		run--
+	}
+	outer := 0
+	for c := ast.Node(n); c.Parent() != nil && c.Parent().FirstChild() == c; c = c.Parent() {
+		if e, ok := c.Parent().(*ast.Emphasis); ok {
+			outer += e.Level
+		} else {
+			break
+		}
+	}
+	if start < 0 || run+outer >= start {
+		return '*'
+	}
+	return p.source[run+outer]
+}
+
+// offsetOf returns the offset in source of sub, a slice of source
+func offsetOf(source, sub []byte) int {
+	return cap(source) - cap(sub)
+}
+
+// rawContent returns the text of a code span or inline math, with line
+// breaks turned into spaces as they render
+func (p *printer) rawContent(n ast.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			value := t.Segment.Value(p.source)
+			if bytes.HasSuffix(value, []byte("\n")) {
+				value = append(value[:len(value)-1:len(value)-1], ' ')
+			}
+			b.Write(value)
+		}
+	}
+	if p.inTable {
+		return strings.ReplaceAll(b.String(), "|", "\\|")
+	}
+	return b.String()
+}
+
+// delimit writes a code span or inline math with as many delimiters as
+// the input used, padding the content with spaces where the parser would
+// trim them
+func (p *printer) delimit(n ast.Node, delimiter byte) string {
+	content := p.rawContent(n)
+	count := 1
+	if first, ok := n.FirstChild().(*ast.Text); ok {
+		i := first.Segment.Start
+		if i > 1 && (p.source[i-1] == ' ' || p.source[i-1] == '\n') && p.source[i-2] == delimiter {
+			i--
+		}
+		count = 0
+		for i > 0 && p.source[i-1] == delimiter {
+			count++
+			i--
+		}
+	}
+	fence := strings.Repeat(string(delimiter), max(count, 1))
+
+	padded := strings.HasPrefix(content, fence[:1]) || strings.HasSuffix(content, fence[:1]) ||
+		(strings.HasPrefix(content, " ") && strings.HasSuffix(content, " ") && strings.TrimSpace(content) != "")
+	if padded {
+		return fence + " " + content + " " + fence
+	}
+	return fence + content + fence
+}
+
+// linkTarget returns what follows the text of a link or image: the
+// reference it was written with, if the definition exists, or else the
+// destination and title in parentheses
+func (p *printer) linkTarget(n ast.Node, text string, destination, title []byte) string {
+	if closing := p.closingBracket(n); closing >= 0 {
+		rest := p.source[closing+1:]
+		switch {
+		case len(rest) > 0 && rest[0] == '(':
+		case len(rest) > 1 && rest[0] == '[' && rest[1] == ']':
+			if p.useReference(text) {
+				return "[]"
+			}
+		case len(rest) > 0 && rest[0] == '[':
+			if end := bytes.IndexByte(rest, ']'); end > 0 && p.useReference(string(rest[1:end])) {
+				return string(rest[:end+1])
+			}
+		default:
+			if p.useReference(text) {
+				return ""
+			}
+		}
+	}
+
+	dest := string(destination)
+	if needsAngles(dest) || (dest == "" && len(title) > 0) {
+		dest = "<" + dest + ">"
+	}
+	if len(title) > 0 {
+		return "(" + dest + " " + quoteTitle(string(title)) + ")"
+	}
+	return "(" + dest + ")"
+}
+
+// closingBracket returns the offset of the "]" ending the text of a link
+// or image, or -1 when it cannot be found
+func (p *printer) closingBracket(n ast.Node) int {
+	i := p.contentEnd(n)
+	if i < 0 {
+		return -1
+	}
+	// Closing delimiters of the last inline, or a container prefix
+	for i < len(p.source) && strings.IndexByte("*_~`$> \t\n", p.source[i]) >= 0 {
+		i++
+	}
+	if i < len(p.source) && p.source[i] == ']' {
+		return i
+	}
+	return -1
+}
+
+// contentEnd returns the offset just past the source of the inline
+// content of n, or -1 when it has none
+func (p *printer) contentEnd(n ast.Node) int {
+	end := -1
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		e := -1
+		switch c := c.(type) {
+		case *ast.Text:
+			e = c.Segment.Stop
+		case *ast.RawHTML:
+			if c.Segments.Len() > 0 {
+				e = c.Segments.At(c.Segments.Len() - 1).Stop
+			}
+		case *ast.AutoLink:
+			label := c.Label(p.source)
+			e = offsetOf(p.source, label) + len(label)
+		case *ast.Link, *ast.Image:
+			e = p.targetEnd(c)
+		default:
+			e = p.contentEnd(c)
+		}
+		if e > end {
+			end = e
+		}
+	}
+	return end
+}
+
+// targetEnd returns the offset just past a whole link or image, or -1
+func (p *printer) targetEnd(n ast.Node) int {
+	closing := p.closingBracket(n)
+	if closing < 0 {
+		return -1
+	}
+	i := closing + 1
+	if i >= len(p.source) {
+		return i
+	}
+	switch p.source[i] {
+	case '(':
+		depth := 0
+		for ; i < len(p.source); i++ {
+			switch p.source[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return -1
+	case '[':
+		if end := bytes.IndexByte(p.source[i:], ']'); end >= 0 {
+			return i + end + 1
+		}
+		return -1
+	}
+	return i
+}
+
+// needsAngles reports whether a link destination must be written in
+// angle brackets
+func needsAngles(dest string) bool {
+	if strings.ContainsAny(dest, " \t\n") {
+		return true
+	}
+	depth := 0
+	for i := 0; i < len(dest); i++ {
+		switch dest[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return true
+			}
+		}
+	}
+	return depth != 0
+}
+
+// quoteTitle quotes a link title with double quotes where possible
+func quoteTitle(title string) string {
+	switch {
+	case !containsUnescaped(title, '"'):
+		return `"` + title + `"`
+	case !containsUnescaped(title, '\''):
+		return "'" + title + "'"
+	}
+	return "(" + title + ")"
+}
+
+func containsUnescaped(s string, c byte) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// blockStart matches words that would start a block at the beginning of a
+// line, where a paragraph must not wrap
+var blockStart = regexp.MustCompile("^(#{1,6}|[-+*]|\\d{1,9}[.)]|[-=:|]+|[*_]{3,})$|^(>|<|```|~~~|\\$\\$|\\|)")
+
+// wrap splits text into lines of at most width columns, breaking at the
+// marked spaces. Words longer than the width get a line of their own.
+// Without a width, the line breaks of the input are kept.
+func wrap(text string, width int) []string {
+	var lines []string
+	paragraphs := strings.Split(text, "\n")
+	for i, paragraph := range paragraphs {
+		// All but the last end in a hard line break
+		if i < len(paragraphs)-1 && strings.HasSuffix(paragraph, "\\") {
+			paragraph = strings.TrimRight(paragraph[:len(paragraph)-1], " "+space) + "\\"
+		}
+		if width <= 0 {
+			line := ""
+			for j, part := range strings.Split(strings.ReplaceAll(paragraph, space, " "), softBreak) {
+				if j > 0 && !canBreak(line, part) {
+					line += " " + part
+					continue
+				}
+				if j > 0 {
+					lines = append(lines, line)
+				}
+				line = part
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		line := ""
+		for _, word := range strings.Split(strings.ReplaceAll(paragraph, softBreak, space), space) {
+			switch {
+			case word == "":
+			case line == "":
+				line = word
+			case runewidth.StringWidth(line)+1+runewidth.StringWidth(word) <= width || !canBreak(line, word):
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// canBreak reports whether a paragraph may continue on a new line starting
+// with next, after line
+func canBreak(line, next string) bool {
+	// A trailing backslash would become a hard line break
+	if strings.HasSuffix(line, "\\") {
+		return false
+	}
+	word, _, _ := strings.Cut(next, " ")
+	return !blockStart.MatchString(word)
+}
@@ -0,0 +1,280 @@
+// Package ignore implements gitignore-style path matching.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFiles are the pattern files read from every directory, in order
+var IgnoreFiles = []string{".gitignore", ".mdcliignore"}
+
+// Matcher decides whether paths below a root directory are ignored
+type Matcher struct {
+	rules []rule
+}
+
+// rule is a single compiled pattern
+type rule struct {
+	// base is the slash separated directory the pattern is relative to
+	base    string
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+	// contents matches the directory of a "dir/**" pattern, which can be
+	// skipped entirely when no later rule re-includes anything
+	contents *regexp.Regexp
+	// final is set when no negated rule follows this one
+	final bool
+}
+
+// New compiles patterns relative to the root directory
+func New(patterns ...string) *Matcher {
+	m := &Matcher{}
+	m.Add("", patterns...)
+	return m
+}
+
+// Load builds a matcher from patterns followed by every ignore file found
+// below root. Files in ignored directories are not read.
+func Load(root string, patterns ...string) (*Matcher, error) {
+	m := New(patterns...)
+
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = ""
+		} else if m.Match(rel, true) {
+			return filepath.SkipDir
+		}
+
+		for _, name := range IgnoreFiles {
+			if err := m.AddFile(filepath.Join(p, name), rel); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return m, err
+}
+
+// AddFile appends the patterns of an ignore file whose directory is base,
+// relative to the root. A missing file is not an error.
+func (m *Matcher) AddFile(filename, base string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	m.Add(base, patterns...)
+	return nil
+}
+
+// Add appends patterns relative to the slash separated directory base
+func (m *Matcher) Add(base string, patterns ...string) {
+	base = strings.Trim(filepath.ToSlash(base), "/")
+
+	for _, pattern := range patterns {
+		r, ok := compile(pattern)
+		if !ok {
+			continue
+		}
+		r.base = base
+		m.rules = append(m.rules, r)
+	}
+
+	final := true
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		m.rules[i].final = final
+		if m.rules[i].negate {
+			final = false
+		}
+	}
+}
+
+// Match reports whether the slash separated path rel, relative to the
+// root, is ignored. A path is also ignored when one of its parent
+// directories is, since git cannot re-include files of an excluded
+// directory.
+func (m *Matcher) Match(rel string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	rel = strings.Trim(filepath.ToSlash(rel), "/")
+	if rel == "" || rel == "." {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.match(rel, isDir)
+}
+
+// match applies the rules to a single path, ignoring its parents
+func (m *Matcher) match(rel string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		target := rel
+		if r.base != "" {
+			if !strings.HasPrefix(rel, r.base+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(rel, r.base+"/")
+		}
+
+		if r.re.MatchString(target) && (isDir || !r.dirOnly) {
+			ignored = !r.negate
+		} else if isDir && r.final && r.contents != nil && r.contents.MatchString(target) {
+			ignored = true
+		}
+	}
+	return ignored
+}
+
+// compile parses one line of an ignore file
+func compile(pattern string) (rule, bool) {
+	var r rule
+
+	pattern = trimTrailingSpace(strings.TrimSuffix(pattern, "\r"))
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return r, false
+	}
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return r, false
+	}
+
+	// A slash anywhere but at the end anchors the pattern to its base
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	prefix := "^"
+	if !anchored {
+		prefix = "^(?:.*/)?"
+	}
+	re, err := regexp.Compile(prefix + globToRegexp(pattern) + "$")
+	if err != nil {
+		// Malformed patterns (e.g. a bad character range) never match
+		return r, false
+	}
+	r.re = re
+
+	if !r.negate && strings.HasSuffix(pattern, "/**") {
+		dir := strings.TrimSuffix(pattern, "/**")
+		r.contents, _ = regexp.Compile("^" + globToRegexp(dir) + "$")
+	}
+
+	return r, true
+}
+
+// trimTrailingSpace removes unescaped trailing spaces
+func trimTrailingSpace(s string) string {
+	for strings.HasSuffix(s, " ") && !strings.HasSuffix(s, `\ `) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// globToRegexp translates gitignore glob syntax into a regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			atStart := i == 0 || glob[i-1] == '/'
+			atEnd := i+2 == len(glob) || glob[i+2] == '/'
+			if !atStart || !atEnd {
+				// Not a standalone "**": behaves like "*"
+				b.WriteString("[^/]*")
+				i++
+				continue
+			}
+			switch {
+			case i+2 == len(glob):
+				// Trailing "**" matches everything inside
+				b.WriteString(".*")
+			default:
+				// Leading or inner "**/" matches zero or more directories
+				b.WriteString("(?:.*/)?")
+				i++
+			}
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
+
+// Clean converts an OS path below root to the slash separated form Match
+// expects
+func Clean(root, p string) string {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return path.Clean(filepath.ToSlash(rel))
+}
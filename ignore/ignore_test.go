@@ -0,0 +1,122 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		dir      bool
+		want     bool
+	}{
+		{"plain name at root", []string{"notes.md"}, "notes.md", false, true},
+		{"plain name in subdirectory", []string{"notes.md"}, "docs/notes.md", false, true},
+		{"glob", []string{"*.tmp.md"}, "docs/a.tmp.md", false, true},
+		{"glob stays in one segment", []string{"docs/*.md"}, "docs/sub/a.md", false, false},
+		{"comment", []string{"# notes.md"}, "notes.md", false, false},
+		{"escaped hash", []string{`\#notes.md`}, "#notes.md", false, true},
+
+		{"anchored at root", []string{"/drafts"}, "drafts", true, true},
+		{"anchored not below root", []string{"/drafts"}, "docs/drafts", true, false},
+		{"inner slash anchors", []string{"docs/drafts"}, "docs/drafts", true, true},
+		{"inner slash not deeper", []string{"docs/drafts"}, "site/docs/drafts", true, false},
+
+		{"directory only matches directory", []string{"build/"}, "build", true, true},
+		{"directory only skips file", []string{"build/"}, "build", false, false},
+		{"file inside ignored directory", []string{"build/"}, "build/guide.md", false, true},
+
+		{"negation re-includes", []string{"*.md", "!keep.md"}, "keep.md", false, false},
+		{"negation leaves others", []string{"*.md", "!keep.md"}, "drop.md", false, true},
+		{"later rule wins", []string{"!keep.md", "*.md"}, "keep.md", false, true},
+		{"negated directory", []string{"build/", "!build/"}, "build/guide.md", false, false},
+		{"negation cannot re-include inside excluded directory", []string{"docs/", "!docs/keep.md"}, "docs/keep.md", false, true},
+		{"escaped bang", []string{`\!important.md`}, "!important.md", false, true},
+
+		{"leading double star", []string{"**/drafts"}, "a/b/drafts", true, true},
+		{"inner double star", []string{"docs/**/old.md"}, "docs/old.md", false, true},
+		{"inner double star deeper", []string{"docs/**/old.md"}, "docs/a/b/old.md", false, true},
+		{"trailing double star", []string{"docs/**"}, "docs/a/b.md", false, true},
+		{"trailing double star skips directory", []string{"docs/**"}, "docs", true, true},
+		{"trailing double star with negation", []string{"docs/**", "!docs/keep.md"}, "docs/keep.md", false, false},
+
+		{"character class", []string{"draft[0-9].md"}, "draft3.md", false, true},
+		{"negated character class", []string{"draft[!0-9].md"}, "draft3.md", false, false},
+		{"question mark", []string{"draft?.md"}, "drafta.md", false, true},
+		{"trailing spaces trimmed", []string{"notes.md  "}, "notes.md", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.patterns...).Match(tt.path, tt.dir); got != tt.want {
+				t.Errorf("New(%q).Match(%q, %v) = %v, want %v", tt.patterns, tt.path, tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchBase(t *testing.T) {
+	m := New()
+	m.Add("docs", "/drafts", "*.tmp")
+
+	tests := []struct {
+		path string
+		dir  bool
+		want bool
+	}{
+		{"docs/drafts", true, true},
+		{"drafts", true, false},
+		{"docs/sub/drafts", true, false},
+		{"docs/sub/a.tmp", false, true},
+		{"a.tmp", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.dir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".gitignore", "*.draft.md\n")
+	write("docs/.mdcliignore", "/private/\n!keep.draft.md\n")
+	write("skipped/.gitignore", "!*.md\n")
+
+	m, err := Load(root, "skipped/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		dir  bool
+		want bool
+	}{
+		{"a.draft.md", false, true},
+		{"docs/b.draft.md", false, true},
+		{"docs/keep.draft.md", false, false},
+		{"docs/private", true, true},
+		{"private", true, false},
+		{"skipped/a.md", false, true},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.dir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
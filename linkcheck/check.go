@@ -0,0 +1,343 @@
+// Package linkcheck finds broken links, anchors and images in Markdown
+// documents.
+package linkcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Problem is a broken link
+type Problem struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// Kind is "link", "image", or "file" when the document itself could
+	// not be read
+	Kind   string `json:"kind"`
+	Target string `json:"target,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Report is the outcome of checking a set of documents
+type Report struct {
+	Files int `json:"files"`
+	Links int `json:"links"`
+	// External is the number of distinct external URLs requested
+	External int       `json:"external"`
+	Problems []Problem `json:"problems"`
+}
+
+// URLChecker verifies external links
+type URLChecker interface {
+	CheckURL(ctx context.Context, url string) error
+}
+
+// Checker verifies the links of Markdown documents
+type Checker struct {
+	// Root is the directory root-relative links such as "/docs/a.md"
+	// resolve against
+	Root string
+	// External checks http and https links; nil skips them
+	External URLChecker
+	// Concurrency limits the number of external checks running at once
+	Concurrency int
+
+	// anchors caches the anchors of every document read, by absolute path
+	anchors map[string]map[string]bool
+}
+
+// link is a link or image found in a document
+type link struct {
+	kind   string
+	target string
+	line   int
+	column int
+}
+
+// HTML links and anchors in raw HTML
+var (
+	htmlLinkRegex   = regexp.MustCompile(`(?i)<(a|img)\b[^>]*?\s(?:href|src)\s*=\s*["']([^"']*)["']`)
+	htmlAnchorRegex = regexp.MustCompile(`(?i)<[a-z][^>]*?\s(?:id|name)\s*=\s*["']([^"']+)["']`)
+)
+
+// Check verifies every link of files
+func (c *Checker) Check(ctx context.Context, files []string) *Report {
+	if c.anchors == nil {
+		c.anchors = make(map[string]map[string]bool)
+	}
+	report := &Report{Files: len(files), Problems: []Problem{}}
+
+	// External links are collected to request every URL once
+	external := make(map[string][]Problem)
+	for _, file := range files {
+		doc, err := c.parse(file)
+		if err != nil {
+			report.Problems = append(report.Problems, Problem{File: file, Line: 1, Column: 1, Kind: "file", Reason: err.Error()})
+			continue
+		}
+
+		for _, l := range extractLinks(doc) {
+			report.Links++
+			p := Problem{File: file, Line: l.line, Column: l.column, Kind: l.kind, Target: l.target}
+			u, isExternal, reason := c.checkLocal(file, l)
+			switch {
+			case reason != "":
+				p.Reason = reason
+				report.Problems = append(report.Problems, p)
+			case isExternal && c.External != nil:
+				external[u] = append(external[u], p)
+			}
+		}
+	}
+
+	report.External = len(external)
+	for u, reason := range c.checkExternal(ctx, external) {
+		for _, p := range external[u] {
+			p.Reason = reason
+			report.Problems = append(report.Problems, p)
+		}
+	}
+
+	sort.Slice(report.Problems, func(i, j int) bool {
+		a, b := report.Problems[i], report.Problems[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return report
+}
+
+// parse reads a document and caches its anchors
+func (c *Checker) parse(file string) (*renderer.Parsed, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := renderer.Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		c.anchors[abs] = documentAnchors(doc)
+	}
+	return doc, nil
+}
+
+// checkLocal verifies a link that does not need the network. It returns
+// the URL to request for external links, or why the link is broken.
+func (c *Checker) checkLocal(file string, l link) (external string, isExternal bool, reason string) {
+	target := strings.TrimSpace(l.target)
+	if target == "" {
+		return "", false, "empty link target"
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", false, "invalid URL"
+	}
+
+	switch {
+	case u.Scheme == "http" || u.Scheme == "https":
+		u.Fragment = ""
+		return u.String(), true, ""
+	case u.Scheme != "" || u.Host != "":
+		// mailto:, tel: and other schemes cannot be checked
+		return "", false, ""
+	}
+
+	if u.Path == "" {
+		// A fragment of the same document
+		if u.Fragment != "" && !c.hasAnchor(file, u.Fragment) {
+			return "", false, fmt.Sprintf("anchor #%s not found", u.Fragment)
+		}
+		return "", false, ""
+	}
+
+	var path string
+	if strings.HasPrefix(u.Path, "/") {
+		path = filepath.Join(c.Root, filepath.FromSlash(u.Path))
+	} else {
+		path = filepath.Join(filepath.Dir(file), filepath.FromSlash(u.Path))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if l.kind == "image" {
+			return "", false, "image not found"
+		}
+		return "", false, "file not found"
+	}
+
+	if u.Fragment != "" && !info.IsDir() && isMarkdown(path) && !c.hasAnchor(path, u.Fragment) {
+		return "", false, fmt.Sprintf("anchor #%s not found in %s", u.Fragment, u.Path)
+	}
+	return "", false, ""
+}
+
+// hasAnchor reports whether the Markdown document at path has an anchor.
+// Documents outside the checked set are read on first use.
+func (c *Checker) hasAnchor(path, anchor string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	anchors, ok := c.anchors[abs]
+	if !ok {
+		if _, err := c.parse(path); err != nil {
+			c.anchors[abs] = nil
+		}
+		anchors = c.anchors[abs]
+	}
+	return anchors[anchor]
+}
+
+// checkExternal requests every URL and returns why the broken ones failed
+func (c *Checker) checkExternal(ctx context.Context, urls map[string][]Problem) map[string]string {
+	broken := make(map[string]string)
+	if c.External == nil {
+		return broken
+	}
+
+	workers := c.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	slots := make(chan struct{}, workers)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for u := range urls {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(u string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := c.External.CheckURL(ctx, u); err != nil {
+				mu.Lock()
+				broken[u] = err.Error()
+				mu.Unlock()
+			}
+		}(u)
+	}
+	wg.Wait()
+	return broken
+}
+
+// documentAnchors returns the heading IDs and the id and name attributes
+// of raw HTML in a document
+func documentAnchors(doc *renderer.Parsed) map[string]bool {
+	anchors := make(map[string]bool)
+	for _, h := range doc.Outline {
+		if h.ID != "" {
+			anchors[h.ID] = true
+		}
+	}
+	forEachHTML(doc, func(html []byte, offset int) {
+		for _, m := range htmlAnchorRegex.FindAllSubmatch(html, -1) {
+			anchors[string(m[1])] = true
+		}
+	})
+	return anchors
+}
+
+// extractLinks returns the links and images of a document in order
+func extractLinks(doc *renderer.Parsed) []link {
+	var links []link
+	add := func(kind, target string, offset int) {
+		line, column := doc.Position(offset)
+		links = append(links, link{kind: kind, target: target, line: line, column: column})
+	}
+
+	ast.Walk(doc.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := n.(type) {
+		case *ast.Link:
+			add("link", string(t.Destination), markerOffset(doc, t, "["))
+		case *ast.Image:
+			add("image", string(t.Destination), markerOffset(doc, t, "!["))
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			offset := doc.Offset(t)
+			if i := bytes.Index(doc.Source[offset:], t.Label(doc.Source)); i >= 0 {
+				offset += i
+			}
+			add("link", string(t.URL(doc.Source)), offset)
+		case *renderer.TOC:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	forEachHTML(doc, func(html []byte, offset int) {
+		for _, m := range htmlLinkRegex.FindAllSubmatchIndex(html, -1) {
+			kind := "link"
+			if strings.EqualFold(string(html[m[2]:m[3]]), "img") {
+				kind = "image"
+			}
+			add(kind, string(html[m[4]:m[5]]), offset+m[0])
+		}
+	})
+
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].line != links[j].line {
+			return links[i].line < links[j].line
+		}
+		return links[i].column < links[j].column
+	})
+	return links
+}
+
+// markerOffset returns the offset of the opening marker of a link or image,
+// which precedes its text
+func markerOffset(doc *renderer.Parsed, n ast.Node, marker string) int {
+	offset := doc.Offset(n)
+	if offset >= len(marker) && string(doc.Source[offset-len(marker):offset]) == marker {
+		return offset - len(marker)
+	}
+	return offset
+}
+
+// forEachHTML calls fn with every line of raw HTML and its offset
+func forEachHTML(doc *renderer.Parsed, fn func(html []byte, offset int)) {
+	ast.Walk(doc.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := n.(type) {
+		case *ast.RawHTML:
+			for i := 0; i < t.Segments.Len(); i++ {
+				s := t.Segments.At(i)
+				fn(s.Value(doc.Source), s.Start)
+			}
+		case *ast.HTMLBlock:
+			for i := 0; i < t.Lines().Len(); i++ {
+				s := t.Lines().At(i)
+				fn(s.Value(doc.Source), s.Start)
+			}
+			if t.HasClosure() {
+				fn(t.ClosureLine.Value(doc.Source), t.ClosureLine.Start)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func isMarkdown(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown")
+}
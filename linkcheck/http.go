@@ -0,0 +1,77 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// HTTPChecker requests external links. Responses of 400 and above are
+// broken, except 429 (rate limited). Servers that refuse HEAD requests are
+// retried with GET.
+type HTTPChecker struct {
+	Client    *http.Client
+	UserAgent string
+	// Skip lists URLs that are never requested
+	Skip []*regexp.Regexp
+}
+
+// NewHTTPChecker returns a checker giving up on a URL after timeout
+func NewHTTPChecker(timeout time.Duration, skip []*regexp.Regexp) *HTTPChecker {
+	return &HTTPChecker{
+		Client:    &http.Client{Timeout: timeout},
+		UserAgent: "mdcli-linkcheck",
+		Skip:      skip,
+	}
+}
+
+// CheckURL implements URLChecker
+func (h *HTTPChecker) CheckURL(ctx context.Context, rawURL string) error {
+	for _, re := range h.Skip {
+		if re.MatchString(rawURL) {
+			return nil
+		}
+	}
+
+	status, err := h.request(ctx, http.MethodHead, rawURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusForbidden || status == http.StatusNotImplemented) {
+		status, err = h.request(ctx, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			if urlErr.Timeout() {
+				return errors.New("request timed out")
+			}
+			err = urlErr.Err
+		}
+		return err
+	}
+	if status >= 400 && status != http.StatusTooManyRequests {
+		return fmt.Errorf("HTTP %d %s", status, http.StatusText(status))
+	}
+	return nil
+}
+
+func (h *HTTPChecker) request(ctx context.Context, method, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if h.UserAgent != "" {
+		req.Header.Set("User-Agent", h.UserAgent)
+	}
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// Only the status matters; drain a little so the connection is reused
+	io.CopyN(io.Discard, resp.Body, 4096)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
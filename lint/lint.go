@@ -0,0 +1,301 @@
+// Package lint checks Markdown documents against a configurable set of
+// style rules.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tacheraSasi/mdcli/renderer"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+)
+
+// Diagnostic is a rule violation
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Config selects and tunes the rules
+type Config struct {
+	// Width is the default limit of the line-length rule
+	Width int
+	// Rules maps rule IDs to false to disable them, or to a map of options.
+	// A map may set "enabled" to false as well. Rules not listed run with
+	// their defaults.
+	Rules map[string]interface{}
+}
+
+// Validate reports rule IDs that do not exist
+func (c Config) Validate() error {
+	var unknown []string
+	for id := range c.Rules {
+		if findRule(id) == nil {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown lint rules: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// options returns the settings of a rule and whether it is enabled
+func (c Config) options(id string) (options, bool) {
+	switch v := c.Rules[id].(type) {
+	case bool:
+		return options{}, v
+	case map[string]interface{}:
+		opts := options(v)
+		return opts, opts.bool("enabled", true)
+	}
+	return options{}, true
+}
+
+// Lint checks the Markdown document content, reporting diagnostics for file
+func Lint(file, content string, cfg Config) ([]Diagnostic, error) {
+	doc, err := renderer.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newDocument(file, content, doc)
+	for _, rule := range Rules {
+		opts, enabled := cfg.options(rule.ID)
+		if !enabled {
+			continue
+		}
+		d.rule, d.opts, d.width = rule.ID, opts, cfg.Width
+		rule.check(d)
+	}
+
+	sort.SliceStable(d.diagnostics, func(i, j int) bool {
+		a, b := d.diagnostics[i], d.diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return d.diagnostics, nil
+}
+
+// document is the state shared by the rules while linting one file
+type document struct {
+	*renderer.Parsed
+	file string
+	// content is the whole input, including front matter
+	content string
+	// lines are the lines of Source, without line endings
+	lines []sourceLine
+	// code and table mark the lines inside code blocks and tables
+	code  map[int]bool
+	table map[int]bool
+	// disabled holds the rules turned off by comments, per Source line
+	disabled []map[string]bool
+
+	rule        string
+	opts        options
+	width       int
+	diagnostics []Diagnostic
+}
+
+// sourceLine is a line of Source and the offset it starts at
+type sourceLine struct {
+	start int
+	text  string
+}
+
+func newDocument(file, content string, doc *renderer.Parsed) *document {
+	d := &document{
+		Parsed:  doc,
+		file:    file,
+		content: content,
+		code:    make(map[int]bool),
+		table:   make(map[int]bool),
+	}
+
+	start := 0
+	for start <= len(doc.Source) {
+		end := bytes.IndexByte(doc.Source[start:], '\n')
+		if end < 0 {
+			if start < len(doc.Source) {
+				d.lines = append(d.lines, sourceLine{start, string(doc.Source[start:])})
+			}
+			break
+		}
+		text := strings.TrimSuffix(string(doc.Source[start:start+end]), "\r")
+		d.lines = append(d.lines, sourceLine{start, text})
+		start += end + 1
+	}
+
+	ast.Walk(doc.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if n.Type() == ast.TypeBlock && n.IsRaw() && n.Kind() != ast.KindHTMLBlock {
+			for i := 0; i < n.Lines().Len(); i++ {
+				d.code[d.lineIndex(n.Lines().At(i).Start)] = true
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*ast.Text); ok && inTable(t) {
+			d.table[d.lineIndex(t.Segment.Start)] = true
+		}
+		return ast.WalkContinue, nil
+	})
+
+	d.parseDirectives()
+	return d
+}
+
+// inTable reports whether n is inside a GFM table
+func inTable(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == extast.KindTableCell {
+			return true
+		}
+	}
+	return false
+}
+
+// lineIndex returns the index of the line containing offset
+func (d *document) lineIndex(offset int) int {
+	i := sort.Search(len(d.lines), func(i int) bool { return d.lines[i].start > offset })
+	if i > 0 {
+		i--
+	}
+	return i
+}
+
+// directiveRegex matches the comments that turn rules off and on:
+//
+//	<!-- mdcli-lint-disable [rule...] -->
+//	<!-- mdcli-lint-enable [rule...] -->
+//	<!-- mdcli-lint-disable-next-line [rule...] -->
+//	<!-- mdcli-lint-disable-line [rule...] -->
+//
+// Without rule IDs they apply to every rule.
+var directiveRegex = regexp.MustCompile(`<!--\s*mdcli-lint-(disable-next-line|disable-line|disable|enable)((?:\s+[\w-]+)*)\s*-->`)
+
+// allRules stands for every rule in disabled sets
+const allRules = "*"
+
+func (d *document) parseDirectives() {
+	d.disabled = make([]map[string]bool, len(d.lines)+1)
+	current := map[string]bool{}
+	extra := make(map[int]map[string]bool)
+
+	for i, line := range d.lines {
+		for _, m := range directiveRegex.FindAllStringSubmatch(line.text, -1) {
+			rules := strings.Fields(m[2])
+			if len(rules) == 0 {
+				rules = []string{allRules}
+			}
+			switch m[1] {
+			case "disable":
+				current = copySet(current)
+				for _, r := range rules {
+					current[r] = true
+				}
+			case "enable":
+				if rules[0] == allRules {
+					current = map[string]bool{}
+					break
+				}
+				current = copySet(current)
+				for _, r := range rules {
+					delete(current, r)
+				}
+			case "disable-line", "disable-next-line":
+				target := i
+				if m[1] == "disable-next-line" {
+					target++
+				}
+				if extra[target] == nil {
+					extra[target] = map[string]bool{}
+				}
+				for _, r := range rules {
+					extra[target][r] = true
+				}
+			}
+		}
+		d.disabled[i] = current
+	}
+
+	for i, rules := range extra {
+		if i < len(d.disabled) {
+			merged := copySet(d.disabled[i])
+			for r := range rules {
+				merged[r] = true
+			}
+			d.disabled[i] = merged
+		}
+	}
+}
+
+func copySet(s map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(s))
+	for k := range s {
+		c[k] = true
+	}
+	return c
+}
+
+// report records a diagnostic of the current rule at a Source offset
+func (d *document) report(offset int, format string, args ...interface{}) {
+	if i := d.lineIndex(offset); i < len(d.disabled) && d.disabled[i] != nil {
+		if d.disabled[i][allRules] || d.disabled[i][d.rule] {
+			return
+		}
+	}
+	line, column := d.Position(offset)
+	d.diagnostics = append(d.diagnostics, Diagnostic{
+		File:    d.file,
+		Line:    line,
+		Column:  column,
+		Rule:    d.rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// reportLine records a diagnostic at a byte index of a Source line
+func (d *document) reportLine(i, index int, format string, args ...interface{}) {
+	d.report(d.lines[i].start+index, format, args...)
+}
+
+// options holds the settings of a rule from the config file
+type options map[string]interface{}
+
+func (o options) int(key string, fallback int) int {
+	switch v := o[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return fallback
+}
+
+func (o options) bool(key string, fallback bool) bool {
+	if v, ok := o[key].(bool); ok {
+		return v
+	}
+	return fallback
+}
+
+func (o options) string(key, fallback string) string {
+	if v, ok := o[key].(string); ok {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,299 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Rule is a check run on every document
+type Rule struct {
+	ID          string
+	Description string
+	check       func(d *document)
+}
+
+// Rules lists every rule in the order they run
+var Rules = []Rule{
+	{"heading-increment", "Heading levels increase one at a time", checkHeadingIncrement},
+	{"duplicate-heading", "Headings have unique text (option: siblings_only)", checkDuplicateHeading},
+	{"single-title", "Only one top-level heading (option: level)", checkSingleTitle},
+	{"heading-punctuation", "Headings do not end with punctuation (option: punctuation)", checkHeadingPunctuation},
+	{"trailing-spaces", "Lines have no trailing whitespace, except hard breaks (option: br_spaces)", checkTrailingSpaces},
+	{"hard-tabs", "Lines are indented with spaces (option: code_blocks)", checkHardTabs},
+	{"multiple-blanks", "No runs of blank lines (option: maximum)", checkMultipleBlanks},
+	{"final-newline", "Files end with a newline", checkFinalNewline},
+	{"line-length", "Lines fit the width (options: max, code_blocks, tables)", checkLineLength},
+	{"list-marker", "Bullet lists use one marker (option: style = consistent, dash, asterisk, plus)", checkListMarker},
+	{"code-language", "Fenced code blocks name their language", checkCodeLanguage},
+	{"empty-link", "Links have a destination", checkEmptyLink},
+}
+
+// findRule returns the rule with the given ID, or nil
+func findRule(id string) *Rule {
+	for i := range Rules {
+		if Rules[i].ID == id {
+			return &Rules[i]
+		}
+	}
+	return nil
+}
+
+// headings calls fn for every heading with its text
+func (d *document) headings(fn func(h *ast.Heading, text string)) {
+	i := 0
+	ast.Walk(d.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		// The outline lists the headings in the same order
+		text := ""
+		if i < len(d.Outline) {
+			text = d.Outline[i].Text
+		}
+		i++
+		fn(h, text)
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// lineStart returns the offset of the line holding a block node
+func (d *document) lineStart(n ast.Node) int {
+	return d.lines[d.lineIndex(d.Offset(n))].start
+}
+
+func checkHeadingIncrement(d *document) {
+	previous := 0
+	d.headings(func(h *ast.Heading, text string) {
+		if previous > 0 && h.Level > previous+1 {
+			d.report(d.lineStart(h), "heading level jumps from h%d to h%d", previous, h.Level)
+		}
+		previous = h.Level
+	})
+}
+
+func checkDuplicateHeading(d *document) {
+	siblingsOnly := d.opts.bool("siblings_only", false)
+
+	type seen struct {
+		level int
+		key   string
+	}
+	// parents are the enclosing headings of the current one
+	var parents []seen
+	first := make(map[string]int)
+	d.headings(func(h *ast.Heading, text string) {
+		for len(parents) > 0 && parents[len(parents)-1].level >= h.Level {
+			parents = parents[:len(parents)-1]
+		}
+		key := strings.ToLower(text)
+		if siblingsOnly && len(parents) > 0 {
+			key = parents[len(parents)-1].key + "\x00" + key
+		}
+		parents = append(parents, seen{h.Level, key})
+
+		offset := d.lineStart(h)
+		if line, ok := first[key]; ok {
+			d.report(offset, "duplicate heading %q (first on line %d)", text, line)
+			return
+		}
+		first[key], _ = d.Position(offset)
+	})
+}
+
+func checkSingleTitle(d *document) {
+	level := d.opts.int("level", 1)
+	count := 0
+	d.headings(func(h *ast.Heading, text string) {
+		if h.Level != level {
+			return
+		}
+		count++
+		if count > 1 {
+			d.report(d.lineStart(h), "multiple h%d headings; a document should have one title", level)
+		}
+	})
+}
+
+func checkHeadingPunctuation(d *document) {
+	punctuation := d.opts.string("punctuation", ".,;:!")
+	d.headings(func(h *ast.Heading, text string) {
+		last, _ := utf8.DecodeLastRuneInString(text)
+		if text != "" && strings.ContainsRune(punctuation, last) {
+			d.report(d.lineStart(h), "heading ends with punctuation %q", string(last))
+		}
+	})
+}
+
+func checkTrailingSpaces(d *document) {
+	brSpaces := d.opts.int("br_spaces", 2)
+	for i, line := range d.lines {
+		if d.code[i] {
+			continue
+		}
+		trimmed := strings.TrimRight(line.text, " \t")
+		trailing := line.text[len(trimmed):]
+		if trailing == "" {
+			continue
+		}
+		// Two spaces before a following line are a hard line break
+		hardBreak := trimmed != "" && trailing == strings.Repeat(" ", brSpaces) &&
+			i+1 < len(d.lines) && strings.TrimSpace(d.lines[i+1].text) != ""
+		if !hardBreak {
+			d.reportLine(i, len(trimmed), "trailing whitespace")
+		}
+	}
+}
+
+func checkHardTabs(d *document) {
+	codeBlocks := d.opts.bool("code_blocks", false)
+	for i, line := range d.lines {
+		if d.code[i] && !codeBlocks {
+			continue
+		}
+		if j := strings.IndexByte(line.text, '\t'); j >= 0 {
+			d.reportLine(i, j, "hard tab")
+		}
+	}
+}
+
+func checkMultipleBlanks(d *document) {
+	maximum := d.opts.int("maximum", 1)
+	blanks := 0
+	for i, line := range d.lines {
+		if d.code[i] || strings.TrimSpace(line.text) != "" {
+			blanks = 0
+			continue
+		}
+		blanks++
+		if blanks == maximum+1 {
+			d.reportLine(i, 0, "more than %d consecutive blank lines", maximum)
+		}
+	}
+}
+
+func checkFinalNewline(d *document) {
+	if d.content != "" && !strings.HasSuffix(d.content, "\n") {
+		d.report(len(d.Source), "file does not end with a newline")
+	}
+}
+
+func checkLineLength(d *document) {
+	limit := d.width
+	if limit <= 0 {
+		limit = 80
+	}
+	limit = d.opts.int("max", limit)
+	codeBlocks := d.opts.bool("code_blocks", false)
+	tables := d.opts.bool("tables", false)
+
+	for i, line := range d.lines {
+		if (d.code[i] && !codeBlocks) || (d.table[i] && !tables) {
+			continue
+		}
+		length := utf8.RuneCountInString(line.text)
+		if length <= limit {
+			continue
+		}
+		// Byte index of the first character past the limit
+		index := 0
+		for n := 0; n < limit; n++ {
+			_, size := utf8.DecodeRuneInString(line.text[index:])
+			index += size
+		}
+		// A single word running past the limit, such as a URL, cannot wrap
+		if !strings.ContainsAny(line.text[index:], " \t") {
+			continue
+		}
+		d.reportLine(i, index, "line is %d characters long, the limit is %d", length, limit)
+	}
+}
+
+// listMarkers are the bullet list markers by style name
+var listMarkers = map[string]byte{"dash": '-', "asterisk": '*', "plus": '+'}
+
+func checkListMarker(d *document) {
+	style := d.opts.string("style", "consistent")
+	var expected byte
+	if style != "consistent" {
+		marker, ok := listMarkers[style]
+		if !ok {
+			d.report(0, "unknown list-marker style %q", style)
+			return
+		}
+		expected = marker
+	}
+
+	ast.Walk(d.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		list, ok := n.(*ast.List)
+		if !ok || !entering || list.IsOrdered() {
+			return ast.WalkContinue, nil
+		}
+		if expected == 0 {
+			expected = list.Marker
+			return ast.WalkContinue, nil
+		}
+		if list.Marker != expected {
+			offset := d.Offset(list)
+			start := d.lines[d.lineIndex(offset)].start
+			if i := bytes.LastIndexByte(d.Source[start:offset], list.Marker); i >= 0 {
+				offset = start + i
+			}
+			d.report(offset, "list marker %q should be %q", string(list.Marker), string(expected))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func checkCodeLanguage(d *document) {
+	ast.Walk(d.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		code, ok := n.(*ast.FencedCodeBlock)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		if len(code.Language(d.Source)) == 0 {
+			// The opening fence is the line before the first code line
+			offset := d.Offset(code)
+			if code.Lines().Len() > 0 {
+				if i := d.lineIndex(offset); i > 0 {
+					offset = d.lines[i-1].start
+				}
+			}
+			d.report(offset, "fenced code block has no language")
+		}
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+func checkEmptyLink(d *document) {
+	ast.Walk(d.Root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*ast.Link)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		dest := strings.TrimSpace(string(link.Destination))
+		if dest == "" || dest == "#" {
+			offset := d.Offset(link)
+			if offset > 0 && d.Source[offset-1] == '[' {
+				offset--
+			}
+			d.report(offset, "link %q has no destination", d.linkText(link))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// linkText returns the text of a link
+func (d *document) linkText(n ast.Node) string {
+	var b strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			b.Write(t.Segment.Value(d.Source))
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
@@ -0,0 +1,179 @@
+package pager
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// escapeLen returns the length of the ANSI escape sequence at the start of
+// s, or 0 when s does not start with one
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		// CSI: parameters up to a final byte in @-~
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		// OSC, ended by BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
+
+// stripANSI removes escape sequences, leaving the visible text
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// cut returns the columns [from, from+width) of a styled line. Escape
+// sequences are kept, so colors carry over from the hidden part.
+func cut(s string, from, width int) string {
+	var b strings.Builder
+	col := 0
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == '\t' {
+			r, size = ' ', 1
+		}
+		w := runewidth.RuneWidth(r)
+		if col >= from && col+w <= from+width {
+			if s[i] == '\t' {
+				b.WriteByte(' ')
+			} else {
+				b.WriteString(s[i : i+size])
+			}
+		}
+		col += w
+		i += size
+		if col >= from+width {
+			break
+		}
+	}
+	return b.String()
+}
+
+// matcher finds a query in visible text. Queries without capitals match
+// either case, like smartcase in vim.
+type matcher struct {
+	query []rune
+	fold  bool
+}
+
+func newMatcher(query string) *matcher {
+	m := &matcher{query: []rune(query), fold: true}
+	for _, r := range m.query {
+		if unicode.IsUpper(r) {
+			m.fold = false
+		}
+	}
+	if m.fold {
+		for i, r := range m.query {
+			m.query[i] = unicode.ToLower(r)
+		}
+	}
+	return m
+}
+
+// find returns the rune offsets in text where the query starts
+func (m *matcher) find(text []rune) []int {
+	if len(m.query) == 0 {
+		return nil
+	}
+	var found []int
+	for i := 0; i+len(m.query) <= len(text); i++ {
+		match := true
+		for j, q := range m.query {
+			r := text[i+j]
+			if m.fold {
+				r = unicode.ToLower(r)
+			}
+			if r != q {
+				match = false
+				break
+			}
+		}
+		if match {
+			found = append(found, i)
+			i += len(m.query) - 1
+		}
+	}
+	return found
+}
+
+// contains reports whether the visible text of a line has a match
+func (m *matcher) contains(line string) bool {
+	return len(m.find([]rune(stripANSI(line)))) > 0
+}
+
+// highlight wraps the matches in a styled line with the on and off
+// sequences, reapplying on after any escape sequence inside a match
+func (m *matcher) highlight(line, on, off string) string {
+	starts := m.find([]rune(stripANSI(line)))
+	if len(starts) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	pos, next, end := 0, 0, -1
+	for i := 0; i < len(line); {
+		if n := escapeLen(line[i:]); n > 0 {
+			b.WriteString(line[i : i+n])
+			if end > pos {
+				b.WriteString(on)
+			}
+			i += n
+			continue
+		}
+		if pos == end {
+			b.WriteString(off)
+		}
+		if next < len(starts) && pos == starts[next] {
+			b.WriteString(on)
+			end = pos + len(m.query)
+			next++
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		pos++
+	}
+	if pos == end {
+		b.WriteString(off)
+	}
+	return b.String()
+}
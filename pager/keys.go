@@ -0,0 +1,73 @@
+package pager
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// csiKeys names the escape sequences sent by special keys
+var csiKeys = map[string]string{
+	"A": "up", "B": "down", "C": "right", "D": "left",
+	"H": "home", "F": "end", "Z": "shift+tab",
+	"1~": "home", "7~": "home", "4~": "end", "8~": "end",
+	"5~": "pgup", "6~": "pgdown", "3~": "delete",
+}
+
+// readKeys sends the keys typed on r until it fails. Printable keys are
+// sent as themselves, others by name, such as "ctrl+d" or "pgdown".
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+// parseKeys splits one read from the terminal into keys
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		c := b[0]
+		switch {
+		case c == '\x1b':
+			if len(b) > 2 && (b[1] == '[' || b[1] == 'O') {
+				i := 2
+				for i < len(b) && (b[i] < 0x40 || b[i] > 0x7e) {
+					i++
+				}
+				if i < len(b) {
+					if name, ok := csiKeys[string(b[2:i+1])]; ok {
+						keys = append(keys, name)
+					}
+					b = b[i+1:]
+					continue
+				}
+			}
+			keys = append(keys, "esc")
+			b = b[1:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+			b = b[1:]
+		case c == '\t':
+			keys = append(keys, "tab")
+			b = b[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, "backspace")
+			b = b[1:]
+		case c < 0x20:
+			keys = append(keys, "ctrl+"+string(rune('a'+c-1)))
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys = append(keys, string(r))
+			b = b[size:]
+		}
+	}
+	return keys
+}
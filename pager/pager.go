@@ -0,0 +1,716 @@
+// Package pager shows rendered documents in an interactive terminal pager
+// with less and vim keys, search, a heading outline and link following.
+package pager
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/tacheraSasi/mdcli/renderer"
+	"golang.org/x/term"
+)
+
+// Page is a document rendered for the pager
+type Page struct {
+	Title    string
+	Lines    []string
+	Headings []Heading
+	Links    []Link
+}
+
+// Heading is a document heading and the line it is shown on
+type Heading struct {
+	renderer.Heading
+	Line int
+}
+
+// Link is a link in a document and the line its text is shown on
+type Link struct {
+	Text   string
+	Target string
+	Line   int
+	// key is the part of the text found on the line
+	key string
+}
+
+// Loader renders the document at path to fit width columns
+type Loader func(path string, width int) (*Page, error)
+
+// NewPage splits rendered output into lines and finds the lines showing
+// the headings and links, by looking for their text in document order.
+// Links whose text is not found cannot be selected and are left out.
+func NewPage(title, output string, headings []renderer.Heading, links []Link) *Page {
+	page := &Page{Title: title, Lines: strings.Split(strings.TrimRight(output, "\n"), "\n")}
+	plain := make([]string, len(page.Lines))
+	for i, line := range page.Lines {
+		plain[i] = strings.ToLower(strings.Join(strings.Fields(stripANSI(line)), " "))
+	}
+	find := func(text string, from int) int {
+		for i := from; i < len(plain); i++ {
+			if strings.Contains(plain[i], text) {
+				return i
+			}
+		}
+		return -1
+	}
+	normalize := func(text string) string {
+		return strings.ToLower(strings.Join(strings.Fields(text), " "))
+	}
+
+	line := 0
+	for _, h := range headings {
+		if i := find(normalize(h.Text), line); i >= 0 {
+			line = i + 1
+		}
+		page.Headings = append(page.Headings, Heading{Heading: h, Line: max(0, line-1)})
+	}
+
+	line = 0
+	for _, link := range links {
+		// Long link text may wrap, so its first word is tried as well
+		for _, key := range []string{normalize(link.Text), strings.SplitN(normalize(link.Text), " ", 2)[0]} {
+			if key == "" {
+				continue
+			}
+			if i := find(key, line); i >= 0 {
+				link.Line, link.key, line = i, key, i
+				page.Links = append(page.Links, link)
+				break
+			}
+		}
+	}
+	return page
+}
+
+// view is a document on the back stack
+type view struct {
+	path string
+	page *Page
+	// width is the width the page was rendered at
+	width int
+	top   int
+	left  int
+	// link is the selected link, -1 for none
+	link int
+}
+
+type pager struct {
+	load    Loader
+	out     *bufio.Writer
+	width   int
+	height  int
+	history []*view
+
+	outline   bool
+	outlineAt int
+
+	// search is the last search, repeated by n and N
+	search    *matcher
+	query     string
+	forward   bool
+	matchLine int
+
+	// prompt is "/" or "?" while a search is typed
+	prompt  string
+	input   []rune
+	message string
+	// count is the number typed before a command
+	count int
+}
+
+// Run pages the document at path until the user quits. Documents are
+// rendered again when the terminal is resized.
+func Run(path string, load Loader) error {
+	in := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(in, state)
+
+	p := &pager{load: load, out: bufio.NewWriter(os.Stdout), matchLine: -1}
+	p.resize()
+	first := &view{path: path, link: -1}
+	if err := p.render(first); err != nil {
+		return err
+	}
+	p.history = []*view{first}
+	p.message = "Press h for help, q to quit"
+
+	// Alternate screen, hidden cursor
+	p.out.WriteString("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		p.out.WriteString("\x1b[?25h\x1b[?1049l")
+		p.out.Flush()
+	}()
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	resized := make(chan struct{}, 1)
+	stop := notifyResize(resized)
+	defer stop()
+
+	for {
+		p.draw()
+		select {
+		case key, ok := <-keys:
+			if !ok || p.handle(key) {
+				return nil
+			}
+		case <-resized:
+			p.resize()
+		}
+	}
+}
+
+func (p *pager) resize() {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 || h <= 1 {
+		w, h = 80, 24
+	}
+	p.width, p.height = w, h
+}
+
+func (p *pager) current() *view {
+	return p.history[len(p.history)-1]
+}
+
+// rows is the number of document lines on screen
+func (p *pager) rows() int {
+	return max(1, p.height-1)
+}
+
+// paneWidth is the width of the outline pane, without its border
+func (p *pager) paneWidth() int {
+	return min(40, p.width/3)
+}
+
+// contentWidth is the width documents are rendered at
+func (p *pager) contentWidth() int {
+	if p.outline {
+		return max(10, p.width-p.paneWidth()-1)
+	}
+	return p.width
+}
+
+// render loads the document of v at the current width, keeping the
+// reading position in proportion
+func (p *pager) render(v *view) error {
+	width := p.contentWidth()
+	page, err := p.load(v.path, width)
+	if err != nil {
+		return err
+	}
+	if v.page != nil && len(v.page.Lines) > 0 {
+		v.top = reposition(v.page, page, v.top)
+	}
+	if v.link >= len(page.Links) {
+		v.link = -1
+	}
+	v.page, v.width = page, width
+	return nil
+}
+
+// reposition returns the line of page showing what line of old showed:
+// the same distance into the section it is in, scaled to the new length
+func reposition(old, page *Page, line int) int {
+	scale := func(n int) int { return n * len(page.Lines) / len(old.Lines) }
+	if len(old.Headings) != len(page.Headings) {
+		return scale(line)
+	}
+	for i := len(old.Headings) - 1; i >= 0; i-- {
+		if h := old.Headings[i]; h.Line <= line {
+			return page.Headings[i].Line + scale(line-h.Line)
+		}
+	}
+	return scale(line)
+}
+
+// clamp keeps the view within the document
+func (p *pager) clamp(v *view) {
+	v.top = max(0, min(v.top, len(v.page.Lines)-p.rows()))
+	v.left = max(0, v.left)
+}
+
+// ====================================================================
+// DRAWING
+// ====================================================================
+
+const (
+	searchOn  = "\x1b[7m"
+	searchOff = "\x1b[27m"
+	linkOn    = "\x1b[4m\x1b[7m"
+	linkOff   = "\x1b[27m\x1b[24m"
+)
+
+func (p *pager) draw() {
+	v := p.current()
+	if v.path != "" && v.width != p.contentWidth() {
+		if err := p.render(v); err != nil {
+			p.message = "Error: " + err.Error()
+		}
+	}
+	p.clamp(v)
+
+	rows := p.rows()
+	var pane []string
+	if p.outline {
+		pane = p.outlineLines(rows)
+	}
+	var link *matcher
+	if v.link >= 0 {
+		link = newMatcher(v.page.Links[v.link].key)
+	}
+
+	p.out.WriteString("\x1b[H")
+	for row := 0; row < rows; row++ {
+		if p.outline {
+			p.out.WriteString(pane[row])
+			p.out.WriteString("\x1b[0m\x1b[2m│\x1b[22m")
+		}
+		if i := v.top + row; i < len(v.page.Lines) {
+			line := v.page.Lines[i]
+			if p.search != nil {
+				line = p.search.highlight(line, searchOn, searchOff)
+			}
+			if link != nil && v.page.Links[v.link].Line == i {
+				line = link.highlight(line, linkOn, linkOff)
+			}
+			p.out.WriteString(cut(line, v.left, p.contentWidth()))
+		} else {
+			p.out.WriteString("\x1b[2m~\x1b[22m")
+		}
+		p.out.WriteString("\x1b[0m\x1b[K\r\n")
+	}
+	p.drawStatus(v)
+	p.out.Flush()
+}
+
+// outlineLines returns the rows of the outline pane, scrolled to show the
+// selected heading
+func (p *pager) outlineLines(rows int) []string {
+	headings := p.current().page.Headings
+	width := p.paneWidth()
+	minLevel := 6
+	for _, h := range headings {
+		minLevel = min(minLevel, h.Level)
+	}
+	start := max(0, p.outlineAt-rows+1)
+
+	lines := make([]string, rows)
+	for row := range lines {
+		text := ""
+		if i := start + row; i < len(headings) {
+			text = " " + strings.Repeat("  ", headings[i].Level-minLevel) + headings[i].Text
+		} else if row == 0 {
+			text = " (no headings)"
+		}
+		text = runewidth.FillRight(runewidth.Truncate(text, width, "…"), width)
+		if start+row == p.outlineAt && len(headings) > 0 {
+			text = "\x1b[7m" + text + "\x1b[27m"
+		}
+		lines[row] = text
+	}
+	return lines
+}
+
+// drawStatus writes the bottom line: the search prompt, a message, or the
+// document and position
+func (p *pager) drawStatus(v *view) {
+	if p.prompt != "" {
+		p.out.WriteString(p.prompt + string(p.input) + "\x1b[7m \x1b[27m\x1b[K")
+		return
+	}
+
+	left := v.path
+	if v.path == "" {
+		left = v.page.Title
+	} else if v.page.Title != "" {
+		left = v.page.Title + " (" + v.path + ")"
+	}
+	if p.message != "" {
+		left = p.message
+	}
+
+	total := len(v.page.Lines)
+	last := min(total, v.top+p.rows())
+	right := fmt.Sprintf(" %d-%d/%d ", v.top+1, last, total)
+	if total > 0 {
+		right += fmt.Sprintf("%d%% ", last*100/total)
+	}
+
+	space := p.width - runewidth.StringWidth(right)
+	left = runewidth.FillRight(runewidth.Truncate(" "+left, max(0, space), "…"), max(0, space))
+	p.out.WriteString("\x1b[7m" + left + right + "\x1b[0m\x1b[K")
+}
+
+// ====================================================================
+// KEYS
+// ====================================================================
+
+// handle applies a key and reports whether the pager should quit
+func (p *pager) handle(key string) bool {
+	p.message = ""
+	if p.prompt != "" {
+		p.handlePrompt(key)
+		return false
+	}
+	if p.outline && p.handleOutline(key) {
+		return false
+	}
+
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		p.count = p.count*10 + int(key[0]-'0')
+		return false
+	}
+	count, explicit := max(1, p.count), p.count > 0
+	p.count = 0
+
+	v := p.current()
+	rows := p.rows()
+	switch key {
+	case "q", "Q", "ctrl+c":
+		if v.path == "" {
+			p.back()
+			return false
+		}
+		return true
+	case "j", "down", "ctrl+e", "ctrl+n":
+		v.top += count
+	case "enter":
+		if v.link >= 0 {
+			p.follow()
+		} else {
+			v.top += count
+		}
+	case "k", "up", "y", "ctrl+y", "ctrl+p":
+		v.top -= count
+	case " ", "f", "z", "ctrl+f", "ctrl+v", "pgdown":
+		v.top += count * rows
+	case "b", "w", "ctrl+b", "pgup":
+		v.top -= count * rows
+	case "d", "ctrl+d":
+		v.top += count * rows / 2
+	case "u", "ctrl+u":
+		v.top -= count * rows / 2
+	case "g", "<", "home":
+		v.top = 0
+		if explicit {
+			v.top = count - 1
+		}
+	case "G", ">", "end":
+		v.top = len(v.page.Lines)
+		if explicit {
+			v.top = count - 1
+		}
+	case "right", "l":
+		v.left += count * max(1, p.contentWidth()/2)
+	case "left":
+		v.left -= count * max(1, p.contentWidth()/2)
+	case "/", "?":
+		p.prompt, p.input = key, nil
+	case "n":
+		p.findNext(p.forward)
+	case "N":
+		p.findNext(!p.forward)
+	case "]", "}":
+		p.jumpHeading(1)
+	case "[", "{":
+		p.jumpHeading(-1)
+	case "o", "=":
+		if len(v.page.Headings) == 0 {
+			p.message = "No headings"
+			break
+		}
+		p.outline = true
+		p.outlineAt = p.headingAt(v.top)
+	case "tab":
+		p.selectLink(1)
+	case "shift+tab":
+		p.selectLink(-1)
+	case "backspace", "B":
+		p.back()
+	case "h", "H":
+		if v.path == "" {
+			p.back()
+			break
+		}
+		p.history = append(p.history, &view{page: helpPage(), link: -1})
+	case "r", "R", "ctrl+l":
+		if v.path != "" {
+			if err := p.render(v); err != nil {
+				p.message = "Error: " + err.Error()
+			}
+		}
+	case "esc":
+		v.link = -1
+		p.search = nil
+	}
+	p.clamp(v)
+	return false
+}
+
+// handlePrompt edits the search being typed
+func (p *pager) handlePrompt(key string) {
+	switch key {
+	case "enter":
+		query, forward := string(p.input), p.prompt == "/"
+		p.prompt = ""
+		if query == "" {
+			query = p.query
+		}
+		if query == "" {
+			return
+		}
+		p.query, p.forward = query, forward
+		p.search = newMatcher(query)
+		p.matchLine = -1
+		p.findNext(forward)
+	case "esc", "ctrl+c":
+		p.prompt = ""
+	case "backspace":
+		if len(p.input) == 0 {
+			p.prompt = ""
+		} else {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case "ctrl+u":
+		p.input = nil
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			p.input = append(p.input, []rune(key)...)
+		}
+	}
+}
+
+// handleOutline moves through the outline pane, scrolling the document
+// along. It reports whether the key was used.
+func (p *pager) handleOutline(key string) bool {
+	headings := p.current().page.Headings
+	switch key {
+	case "j", "down", "ctrl+n", "tab":
+		p.outlineAt++
+	case "k", "up", "ctrl+p", "shift+tab":
+		p.outlineAt--
+	case "g", "home":
+		p.outlineAt = 0
+	case "G", "end":
+		p.outlineAt = len(headings) - 1
+	case "enter", "o", "=", "esc", "q":
+		p.outline = false
+		return true
+	default:
+		return false
+	}
+	p.outlineAt = max(0, min(p.outlineAt, len(headings)-1))
+	if len(headings) > 0 {
+		p.current().top = headings[p.outlineAt].Line
+	}
+	return true
+}
+
+// headingAt returns the index of the heading of the section at line
+func (p *pager) headingAt(line int) int {
+	at := 0
+	for i, h := range p.current().page.Headings {
+		if h.Line <= line {
+			at = i
+		}
+	}
+	return at
+}
+
+// jumpHeading scrolls to the next heading below the top line, or the
+// previous one above it
+func (p *pager) jumpHeading(step int) {
+	v := p.current()
+	headings := v.page.Headings
+	if step > 0 {
+		for _, h := range headings {
+			if h.Line > v.top {
+				v.top = h.Line
+				return
+			}
+		}
+	} else {
+		for i := len(headings) - 1; i >= 0; i-- {
+			if headings[i].Line < v.top {
+				v.top = headings[i].Line
+				return
+			}
+		}
+	}
+	p.message = "No more headings"
+}
+
+// findNext moves to the next line matching the search, wrapping around
+// the document
+func (p *pager) findNext(forward bool) {
+	if p.search == nil {
+		p.message = "No previous search"
+		return
+	}
+	v := p.current()
+	lines := v.page.Lines
+	if len(lines) == 0 {
+		return
+	}
+
+	// Continue from the last match while it is on screen
+	start := p.matchLine
+	if start < v.top || start >= v.top+p.rows() {
+		start = v.top - 1
+		if !forward {
+			start = v.top + p.rows()
+		}
+	}
+	n := len(lines)
+	for i := 1; i <= n; i++ {
+		line := start + i
+		if !forward {
+			line = start - i
+		}
+		line = (line%n + n) % n
+		if !p.search.contains(lines[line]) {
+			continue
+		}
+		if forward && line <= start {
+			p.message = "Search hit BOTTOM, continuing at TOP"
+		} else if !forward && line >= start {
+			p.message = "Search hit TOP, continuing at BOTTOM"
+		}
+		p.matchLine = line
+		v.top = line
+		return
+	}
+	p.message = "Pattern not found: " + p.query
+}
+
+// selectLink selects the next or previous link, starting from the screen
+// when the selected link is out of sight
+func (p *pager) selectLink(step int) {
+	v := p.current()
+	links := v.page.Links
+	if len(links) == 0 {
+		p.message = "No links"
+		return
+	}
+	rows := p.rows()
+	visible := func(line int) bool { return line >= v.top && line < v.top+rows }
+
+	switch {
+	case v.link >= 0 && visible(links[v.link].Line):
+		v.link = (v.link + step + len(links)) % len(links)
+	case step > 0:
+		v.link = 0
+		for i, l := range links {
+			if l.Line >= v.top {
+				v.link = i
+				break
+			}
+		}
+	default:
+		v.link = len(links) - 1
+		for i := len(links) - 1; i >= 0; i-- {
+			if links[i].Line < v.top+rows {
+				v.link = i
+				break
+			}
+		}
+	}
+	if line := links[v.link].Line; !visible(line) {
+		v.top = line - rows/2
+	}
+	p.message = links[v.link].Target
+}
+
+// follow opens the selected link: Markdown files and anchors are pushed
+// on the back stack, other targets are only shown
+func (p *pager) follow() {
+	v := p.current()
+	target := v.page.Links[v.link].Target
+	if strings.Contains(target, ":") && !filepath.IsAbs(target) {
+		p.message = "External link: " + target
+		return
+	}
+
+	file, fragment, _ := strings.Cut(target, "#")
+	if unescaped, err := url.PathUnescape(file); err == nil {
+		file = unescaped
+	}
+	next := &view{path: v.path, page: v.page, width: v.width, link: -1}
+	if file != "" {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(v.path), filepath.FromSlash(file))
+		}
+		if ext := strings.ToLower(filepath.Ext(file)); ext != ".md" && ext != ".markdown" {
+			p.message = "Not a Markdown file: " + target
+			return
+		}
+		next = &view{path: file, link: -1}
+		if err := p.render(next); err != nil {
+			p.message = "Error: " + err.Error()
+			return
+		}
+	}
+	if fragment != "" {
+		found := false
+		for _, h := range next.page.Headings {
+			if h.ID == fragment {
+				next.top, found = h.Line, true
+				break
+			}
+		}
+		if !found {
+			p.message = "No heading #" + fragment
+		}
+	}
+	p.history = append(p.history, next)
+}
+
+// back returns to the previous document
+func (p *pager) back() {
+	if len(p.history) == 1 {
+		p.message = "No previous document"
+		return
+	}
+	p.history = p.history[:len(p.history)-1]
+}
+
+// helpPage lists the keys
+func helpPage() *Page {
+	help := `
+  MOVING
+    j ↓ Ctrl-E          Down one line (a number before a key repeats it)
+    k ↑ y Ctrl-Y        Up one line
+    Space f PgDn        Down one screen
+    b w PgUp            Up one screen
+    d Ctrl-D / u Ctrl-U Down / up half a screen
+    g < Home            Top (or line N with a number)
+    G > End             Bottom
+    ← →                 Scroll sideways
+
+  SEARCHING
+    /text  ?text        Search forward / backward
+    n  N                Next / previous match
+    Esc                 Clear highlights and the selected link
+
+  NAVIGATION
+    ]  [                Next / previous heading
+    o                   Outline: j/k move, Enter or o closes
+    Tab  Shift-Tab      Select the next / previous link
+    Enter               Follow the selected link
+    Backspace B         Back to the previous document
+
+  OTHER
+    r Ctrl-L            Reload the document
+    h                   This help (q returns)
+    q                   Quit`
+	return &Page{Title: "Help: q or Backspace to return", Lines: strings.Split(strings.TrimPrefix(help, "\n"), "\n")}
+}
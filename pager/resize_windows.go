@@ -0,0 +1,39 @@
+//go:build windows
+
+package pager
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// notifyResize sends on ch when the terminal is resized. Windows has no
+// resize signal, so the size is polled.
+func notifyResize(ch chan<- struct{}) (stop func()) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+		for {
+			select {
+			case <-ticker.C:
+				nw, nh, err := term.GetSize(int(os.Stdout.Fd()))
+				if err == nil && (nw != w || nh != h) {
+					w, h = nw, nh
+					select {
+					case ch <- struct{}{}:
+					default:
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,161 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter holds the metadata block at the top of a document
+type FrontMatter struct {
+	Title string
+	Date  time.Time
+	Tags  []string
+	Draft bool
+	// Params holds every key of the block, including the ones above
+	Params map[string]interface{}
+	// Warnings describes values that were ignored, such as a date that
+	// could not be parsed
+	Warnings []string
+}
+
+// Document is the result of rendering a Markdown document
+type Document struct {
+	Output      string
+	FrontMatter FrontMatter
+	// Outline lists the document's headings
+	Outline []Heading
+}
+
+// Title returns the front matter title, or fallback when none is set
+func (d *Document) Title(fallback string) string {
+	if d.FrontMatter.Title != "" {
+		return d.FrontMatter.Title
+	}
+	return fallback
+}
+
+// dateLayouts are the date formats accepted in front matter
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseFrontMatter splits a leading YAML (---) or TOML (+++) block from input.
+// It returns the parsed metadata and the remaining Markdown body. Input
+// without front matter is returned unchanged, and so is input whose leading
+// block is not a YAML or TOML mapping: a --- line may just as well be a
+// thematic break, and "---\n# Heading\n---" a heading between two of them
+// rather than a YAML comment. An empty --- block is empty front matter.
+func ParseFrontMatter(input string) (FrontMatter, string) {
+	var fm FrontMatter
+
+	raw, body, delim, ok := splitFrontMatter(input)
+	if !ok {
+		return fm, input
+	}
+
+	var params map[string]interface{}
+	var err error
+	switch delim {
+	case "---":
+		err = yaml.Unmarshal([]byte(raw), &params)
+	case "+++":
+		err = toml.Unmarshal([]byte(raw), &params)
+	}
+	if err != nil {
+		return fm, input
+	}
+	if params == nil {
+		if delim == "---" && strings.TrimSpace(raw) != "" {
+			// Only comments, which in Markdown are headings
+			return fm, input
+		}
+		params = make(map[string]interface{})
+	}
+
+	fm.Params = params
+	if title, ok := params["title"]; ok {
+		fm.Title = strings.TrimSpace(fmt.Sprint(title))
+	}
+	if draft, ok := params["draft"].(bool); ok {
+		fm.Draft = draft
+	}
+	if date, ok := params["date"]; ok {
+		parsed, err := parseFrontMatterDate(date)
+		if err != nil {
+			fm.Warnings = append(fm.Warnings, err.Error())
+		}
+		fm.Date = parsed
+	}
+	fm.Tags = parseFrontMatterTags(params["tags"])
+
+	return fm, body
+}
+
+// splitFrontMatter finds the raw front matter block and its delimiter
+func splitFrontMatter(input string) (raw, body, delim string, ok bool) {
+	input = strings.TrimPrefix(input, "\ufeff")
+
+	first, rest, found := strings.Cut(input, "\n")
+	if !found {
+		return "", input, "", false
+	}
+	delim = strings.TrimRight(first, " \t\r")
+	if delim != "---" && delim != "+++" {
+		return "", input, "", false
+	}
+
+	var lines []string
+	for rest != "" {
+		line, next, _ := strings.Cut(rest, "\n")
+		if strings.TrimRight(line, " \t\r") == delim {
+			return strings.Join(lines, "\n"), next, delim, true
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+		rest = next
+	}
+
+	// No closing delimiter: this is a thematic break, not front matter
+	return "", input, "", false
+}
+
+func parseFrontMatterDate(value interface{}) (time.Time, error) {
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+
+	// TOML local dates and YAML strings both format as plain text
+	s := strings.TrimSpace(fmt.Sprint(value))
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid front matter date '%s'", s)
+}
+
+func parseFrontMatterTags(value interface{}) []string {
+	var tags []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, tag := range v {
+			if s := strings.TrimSpace(fmt.Sprint(tag)); s != "" {
+				tags = append(tags, s)
+			}
+		}
+	case string:
+		for _, tag := range strings.Split(v, ",") {
+			if s := strings.TrimSpace(tag); s != "" {
+				tags = append(tags, s)
+			}
+		}
+	}
+	return tags
+}
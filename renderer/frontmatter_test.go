@@ -0,0 +1,115 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		title    string
+		date     time.Time
+		body     string
+		warnings int
+	}{
+		{
+			name:  "yaml",
+			input: "---\ntitle: Notes\ndate: 2024-03-01\n---\n# Body\n",
+			title: "Notes",
+			date:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			body:  "# Body\n",
+		},
+		{
+			name:  "toml",
+			input: "+++\ntitle = \"Notes\"\n+++\nBody\n",
+			title: "Notes",
+			body:  "Body\n",
+		},
+		{
+			name:  "no front matter",
+			input: "# Body\n",
+			body:  "# Body\n",
+		},
+		{
+			name:  "leading thematic break",
+			input: "---\nSome text: with a colon, and more: text.\n\n---\nMore text\n",
+			body:  "---\nSome text: with a colon, and more: text.\n\n---\nMore text\n",
+		},
+		{
+			name:  "thematic break around a sentence",
+			input: "---\nJust a sentence.\n---\n",
+			body:  "---\nJust a sentence.\n---\n",
+		},
+		{
+			name:  "thematic break around a list",
+			input: "---\n- one\n- two\n---\n",
+			body:  "---\n- one\n- two\n---\n",
+		},
+		{
+			name:  "heading between thematic breaks",
+			input: "---\n# Heading\n---\nText\n",
+			body:  "---\n# Heading\n---\nText\n",
+		},
+		{
+			name:  "toml comment",
+			input: "+++\n# no keys yet\n+++\nBody\n",
+			body:  "Body\n",
+		},
+		{
+			name:  "empty block",
+			input: "---\n---\nBody\n",
+			body:  "Body\n",
+		},
+		{
+			name:  "unclosed block",
+			input: "---\ntitle: Notes\n",
+			body:  "---\ntitle: Notes\n",
+		},
+		{
+			name:  "invalid toml",
+			input: "+++\nnot toml\n+++\nBody\n",
+			body:  "+++\nnot toml\n+++\nBody\n",
+		},
+		{
+			name:     "invalid date",
+			input:    "---\ntitle: Notes\ndate: next tuesday\n---\nBody\n",
+			title:    "Notes",
+			body:     "Body\n",
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body := ParseFrontMatter(tt.input)
+			if fm.Title != tt.title {
+				t.Errorf("title = %q, want %q", fm.Title, tt.title)
+			}
+			if !fm.Date.Equal(tt.date) {
+				t.Errorf("date = %v, want %v", fm.Date, tt.date)
+			}
+			if body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+			if len(fm.Warnings) != tt.warnings {
+				t.Errorf("warnings = %q, want %d", fm.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestRenderLeadingThematicBreak(t *testing.T) {
+	doc, err := Render(RenderOptions{
+		Input:        "---\nNot front matter.\n\n---\nMore text\n",
+		OutputFormat: "html",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(doc.Output, "<hr") || !strings.Contains(doc.Output, "Not front matter.") {
+		t.Errorf("thematic break or text missing from output:\n%s", doc.Output)
+	}
+}
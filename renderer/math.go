@@ -0,0 +1,70 @@
+package renderer
+
+import (
+	"html"
+	"strings"
+
+	mathjax "github.com/litao91/goldmark-mathjax"
+	"github.com/tacheraSasi/mdcli/tex"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	gmrenderer "github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// mathSource returns the TeX of a math node, without its $ delimiters
+func mathSource(n ast.Node, source []byte) string {
+	var b strings.Builder
+	if _, ok := n.(*mathjax.InlineMath); ok {
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				b.Write(t.Segment.Value(source))
+			}
+		}
+		return b.String()
+	}
+	for i := 0; i < n.Lines().Len(); i++ {
+		segment := n.Lines().At(i)
+		b.Write(segment.Value(source))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// mathMLExtension writes math as MathML instead of MathJax delimiters
+type mathMLExtension struct{}
+
+func (mathMLExtension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(gmrenderer.WithNodeRenderers(
+		util.Prioritized(mathMLRenderer{}, 100),
+	))
+}
+
+type mathMLRenderer struct{}
+
+func (mathMLRenderer) RegisterFuncs(reg gmrenderer.NodeRendererFuncRegisterer) {
+	reg.Register(mathjax.KindInlineMath, renderMathML)
+	reg.Register(mathjax.KindMathBlock, renderMathML)
+}
+
+// renderMathML writes a math node as MathML. TeX that cannot be converted
+// is shown as it is in a math-error span, with the reason as its title.
+func renderMathML(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	_, display := n.(*mathjax.MathBlock)
+	src := mathSource(n, source)
+	out, err := tex.MathML(src, display)
+	if err != nil {
+		out = `<span class="math-error" title="` + html.EscapeString("Cannot render math: "+err.Error()) + `">` +
+			html.EscapeString(src) + "</span>"
+		if display {
+			out = "<p>" + out + "</p>"
+		}
+	}
+	w.WriteString(out)
+	if display {
+		w.WriteString("\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,36 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Heading is an entry of a document's outline
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	// ID is the anchor generated for the heading
+	ID string `json:"id"`
+}
+
+// Outline returns the headings of a parsed document in order
+func Outline(root ast.Node, source []byte) []Heading {
+	var headings []Heading
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		h := Heading{Level: heading.Level, Text: strings.TrimSpace(plainText(heading, source))}
+		if id, ok := heading.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				h.ID = string(b)
+			}
+		}
+		headings = append(headings, h)
+		return ast.WalkSkipChildren, nil
+	})
+	return headings
+}
@@ -0,0 +1,91 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Parsed is a Markdown document parsed by the render pipeline
+type Parsed struct {
+	Root   ast.Node
+	Source []byte
+	// Line is the line of the input Source starts at, after front matter
+	Line        int
+	FrontMatter FrontMatter
+	Outline     []Heading
+	// References are the link reference definitions, which the parser
+	// removes from the tree
+	References []parser.Reference
+}
+
+// Parse splits the front matter off input and parses the rest with the same
+// extensions and heading IDs as Render, for tools that inspect the AST
+func Parse(input string) (*Parsed, error) {
+	fm, body := ParseFrontMatter(input)
+
+	md := newMarkdown(withDefaults(RenderOptions{Autolink: true}))
+	source := []byte(body)
+	pc := parser.NewContext()
+	root := md.Parser().Parse(text.NewReader(source), parser.WithContext(pc))
+	return &Parsed{
+		Root:        root,
+		Source:      source,
+		Line:        1 + strings.Count(input[:len(input)-len(body)], "\n"),
+		FrontMatter: fm,
+		Outline:     Outline(root, source),
+		References:  pc.References(),
+	}, nil
+}
+
+// Position returns the line and column, both 1-based, of a byte offset in
+// Source. Lines are counted in the original input.
+func (p *Parsed) Position(offset int) (line, column int) {
+	if offset > len(p.Source) {
+		offset = len(p.Source)
+	}
+	before := p.Source[:offset]
+	start := bytes.LastIndexByte(before, '\n') + 1
+	return p.Line + bytes.Count(before, []byte("\n")), utf8.RuneCount(before[start:]) + 1
+}
+
+// Offset returns the byte offset in Source where n starts. Inline nodes
+// have no position of their own, so the first text below them is used,
+// falling back to the start of the enclosing block.
+func (p *Parsed) Offset(n ast.Node) int {
+	offset := -1
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			offset = t.Segment.Start
+		case *ast.RawHTML:
+			if t.Segments.Len() > 0 {
+				offset = t.Segments.At(0).Start
+			}
+		default:
+			if c.Type() == ast.TypeBlock && c.Lines().Len() > 0 {
+				offset = c.Lines().At(0).Start
+			}
+		}
+		if offset >= 0 {
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if offset >= 0 {
+		return offset
+	}
+	for b := n.Parent(); b != nil; b = b.Parent() {
+		if b.Type() == ast.TypeBlock && b.Lines().Len() > 0 {
+			return b.Lines().At(0).Start
+		}
+	}
+	return 0
+}
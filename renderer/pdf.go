@@ -0,0 +1,858 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/go-pdf/fpdf"
+	mathjax "github.com/litao91/goldmark-mathjax"
+	"github.com/tacheraSasi/mdcli/themes"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"go.abhg.dev/goldmark/mermaid"
+)
+
+// PDFSource is a single Markdown document laid out by RenderPDF
+type PDFSource struct {
+	Input string
+	// BaseDir is used to resolve relative image paths
+	BaseDir string
+}
+
+// Page layout, in points
+const (
+	pdfMargin      = 56.0
+	pdfBodySize    = 11.0
+	pdfCodeSize    = 9.0
+	pdfTableSize   = 10.0
+	pdfBlockGap    = 8.0
+	pdfListIndent  = 18.0
+	pdfQuoteIndent = 16.0
+	pdfCodePadding = 6.0
+	pdfCellPadding = 4.0
+	pdfLineSpacing = 1.45
+)
+
+var pdfHeadingSizes = []float64{24, 20, 16, 14, 12, 11}
+
+// rgb is a color in 0-255 components
+type rgb struct {
+	r, g, b int
+}
+
+// pdfPalette holds the theme colors used on a page
+type pdfPalette struct {
+	background rgb
+	text       rgb
+	header     rgb
+	link       rgb
+	code       rgb
+	primary    rgb
+	secondary  rgb
+	muted      rgb // code and table backgrounds
+}
+
+// inlineStyle is the formatting applied to a run of inline text
+type inlineStyle struct {
+	size   float64
+	bold   bool
+	italic bool
+	strike bool
+	mono   bool
+	link   string
+	color  rgb
+}
+
+// pdfRenderer lays out a goldmark AST onto fpdf pages
+type pdfRenderer struct {
+	pdf         *fpdf.Fpdf
+	tr          func(string) string
+	colors      pdfPalette
+	syntaxStyle *chroma.Style
+	source      []byte
+	baseDir     string
+	indent      float64
+	lineHeight  float64
+	// outlineLevel is the level of the last bookmark, -1 before the first
+	outlineLevel int
+}
+
+// RenderPDF lays out one or more Markdown documents into a single PDF using
+// the colors of opts.Theme. Every source starts on a new page.
+func RenderPDF(opts RenderOptions, sources ...PDFSource) ([]byte, error) {
+	opts = withDefaults(opts)
+
+	orientation := "P"
+	if opts.Landscape {
+		orientation = "L"
+	}
+	pdf := fpdf.New(orientation, "pt", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetCreator("mdcli", false)
+	pdf.AliasNbPages("")
+
+	r := &pdfRenderer{
+		pdf:          pdf,
+		tr:           pdf.UnicodeTranslatorFromDescriptor(""),
+		colors:       newPDFPalette(themeColors(opts.Theme)),
+		syntaxStyle:  styles.Get(themes.GetSyntaxHighlightingStyle(opts.Theme)),
+		outlineLevel: -1,
+	}
+
+	pdf.SetHeaderFunc(func() {
+		w, h := pdf.GetPageSize()
+		r.setFill(r.colors.background)
+		pdf.Rect(0, 0, w, h, "F")
+	})
+	pdf.SetFooterFunc(func() {
+		w, _ := pdf.GetPageSize()
+		pdf.SetY(-pdfMargin / 1.5)
+		pdf.SetX(pdfMargin)
+		pdf.SetFont("Helvetica", "", 8)
+		r.setText(r.colors.secondary)
+		pdf.CellFormat(w-2*pdfMargin, 10, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	md := newMarkdown(opts)
+	for i, src := range sources {
+		input := src.Input
+		if !opts.RawFrontMatter {
+			fm, body := ParseFrontMatter(input)
+			if i == 0 && fm.Title != "" {
+				pdf.SetTitle(fm.Title, true)
+			}
+			input = body
+		}
+
+		r.source = []byte(input)
+		r.baseDir = src.BaseDir
+		if r.baseDir == "" {
+			r.baseDir = "."
+		}
+		r.indent = 0
+		r.applyIndent()
+
+		doc := md.Parser().Parse(text.NewReader(r.source))
+		if pdf.PageNo() == 0 || pdf.GetY() > pdfMargin {
+			pdf.AddPage()
+		}
+		r.renderBlocks(doc)
+	}
+
+	if pdf.PageNo() == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newPDFPalette converts the hex colors of a theme
+func newPDFPalette(c themes.ThemeColors) pdfPalette {
+	p := pdfPalette{
+		background: parseRGB(c.Background, rgb{255, 255, 255}),
+		text:       parseRGB(c.Text, rgb{36, 41, 46}),
+		primary:    parseRGB(c.Primary, rgb{3, 102, 214}),
+		secondary:  parseRGB(c.Secondary, rgb{88, 96, 105}),
+	}
+	p.header = parseRGB(c.Header, p.text)
+	p.link = parseRGB(c.Link, p.primary)
+	p.code = parseRGB(c.Code, p.text)
+	p.muted = blend(p.background, p.text, 0.08)
+	return p
+}
+
+func parseRGB(hex string, fallback rgb) rgb {
+	r, g, b, err := themes.HexToRGB(hex)
+	if err != nil {
+		return fallback
+	}
+	return rgb{r, g, b}
+}
+
+// blend mixes amount of b into a
+func blend(a, b rgb, amount float64) rgb {
+	mix := func(x, y int) int {
+		return int(float64(x)*(1-amount) + float64(y)*amount)
+	}
+	return rgb{mix(a.r, b.r), mix(a.g, b.g), mix(a.b, b.b)}
+}
+
+func (r *pdfRenderer) setText(c rgb) { r.pdf.SetTextColor(c.r, c.g, c.b) }
+func (r *pdfRenderer) setFill(c rgb) { r.pdf.SetFillColor(c.r, c.g, c.b) }
+func (r *pdfRenderer) setDraw(c rgb) { r.pdf.SetDrawColor(c.r, c.g, c.b) }
+
+// contentWidth is the usable width at the current indentation
+func (r *pdfRenderer) contentWidth() float64 {
+	w, _ := r.pdf.GetPageSize()
+	return w - 2*pdfMargin - r.indent
+}
+
+func (r *pdfRenderer) left() float64 {
+	return pdfMargin + r.indent
+}
+
+func (r *pdfRenderer) applyIndent() {
+	r.pdf.SetLeftMargin(r.left())
+	r.pdf.SetX(r.left())
+}
+
+// ensureSpace starts a new page when less than h points remain
+func (r *pdfRenderer) ensureSpace(h float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY()+h > pageHeight-pdfMargin {
+		r.pdf.AddPage()
+		r.pdf.SetX(r.left())
+	}
+}
+
+func (r *pdfRenderer) bodyStyle() inlineStyle {
+	return inlineStyle{size: pdfBodySize, color: r.colors.text}
+}
+
+// ====================================================================
+// BLOCKS
+// ====================================================================
+
+func (r *pdfRenderer) renderBlocks(parent ast.Node) {
+	for c := parent.FirstChild(); c != nil; c = c.NextSibling() {
+		r.renderBlock(c)
+	}
+}
+
+func (r *pdfRenderer) renderBlock(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Heading:
+		r.renderHeading(n)
+	case *ast.Paragraph:
+		r.renderParagraph(n, r.bodyStyle())
+		r.pdf.Ln(pdfBlockGap)
+	case *ast.TextBlock:
+		r.renderParagraph(n, r.bodyStyle())
+	case *ast.ThematicBreak:
+		r.renderRule()
+	case *ast.FencedCodeBlock:
+		r.renderCode(string(n.Language(r.source)), r.lines(n))
+	case *ast.CodeBlock:
+		r.renderCode("", r.lines(n))
+	case *mathjax.MathBlock:
+		r.renderCode("latex", r.lines(n))
+	case *mermaid.Block:
+		r.renderCode("mermaid", r.lines(n))
+	case *ast.Blockquote:
+		r.renderBlockquote(n)
+	case *ast.List:
+		r.renderList(n)
+		if _, nested := n.Parent().(*ast.ListItem); !nested {
+			r.pdf.Ln(pdfBlockGap)
+		}
+	case *extast.Table:
+		r.renderTable(n)
+	case *TOC:
+		r.renderTOC(n)
+	case *ast.HTMLBlock, *mermaid.ScriptBlock:
+		// Raw HTML and scripts have no meaning on paper
+	default:
+		r.renderBlocks(n)
+	}
+}
+
+func (r *pdfRenderer) renderHeading(n *ast.Heading) {
+	level := n.Level
+	if level < 1 {
+		level = 1
+	}
+	if level > len(pdfHeadingSizes) {
+		level = len(pdfHeadingSizes)
+	}
+	size := pdfHeadingSizes[level-1]
+
+	r.pdf.Ln(size * 0.4)
+	// Keep the heading together with at least a line of what follows
+	r.ensureSpace(size*pdfLineSpacing + pdfBodySize*pdfLineSpacing*2)
+	// Outline entries may only go one level deeper than the one before, so
+	// documents that start below # or skip levels still nest correctly
+	r.outlineLevel = min(level-1, r.outlineLevel+1)
+	r.pdf.Bookmark(r.tr(plainText(n, r.source)), r.outlineLevel, -1)
+
+	style := inlineStyle{size: size, bold: true, color: r.colors.header}
+	r.renderParagraph(n, style)
+
+	if level <= 2 {
+		y := r.pdf.GetY() + 2
+		r.setDraw(r.colors.secondary)
+		r.pdf.SetLineWidth(0.5)
+		r.pdf.Line(r.left(), y, r.left()+r.contentWidth(), y)
+		r.pdf.Ln(6)
+	}
+	r.pdf.Ln(pdfBlockGap / 2)
+}
+
+// renderParagraph writes the inline children of n as flowing text
+func (r *pdfRenderer) renderParagraph(n ast.Node, style inlineStyle) {
+	previous := r.lineHeight
+	r.lineHeight = style.size * pdfLineSpacing
+	r.pdf.SetX(r.left())
+	r.writeInlines(n, style)
+	r.pdf.Ln(r.lineHeight)
+	r.lineHeight = previous
+}
+
+// renderTOC lists the headings of a table of contents, indented by depth
+func (r *pdfRenderer) renderTOC(n *TOC) {
+	entries := n.Entries()
+	if len(entries) == 0 {
+		return
+	}
+	previous, previousHeight := r.indent, r.lineHeight
+	style := r.bodyStyle()
+	style.color = r.colors.link
+	r.lineHeight = style.size * pdfLineSpacing
+	for i, depth := range tocDepths(entries) {
+		r.indent = previous + float64(depth)*pdfListIndent
+		r.applyIndent()
+		r.ensureSpace(r.lineHeight)
+		r.pdf.SetX(r.left())
+		r.writeText(entries[i].Text, style)
+		r.pdf.Ln(r.lineHeight)
+	}
+	r.indent = previous
+	r.lineHeight = previousHeight
+	r.applyIndent()
+	r.pdf.Ln(pdfBlockGap)
+}
+
+func (r *pdfRenderer) renderRule() {
+	r.pdf.Ln(pdfBlockGap / 2)
+	y := r.pdf.GetY()
+	r.setDraw(r.colors.secondary)
+	r.pdf.SetLineWidth(1)
+	r.pdf.Line(r.left(), y, r.left()+r.contentWidth(), y)
+	r.pdf.Ln(pdfBlockGap * 1.5)
+}
+
+func (r *pdfRenderer) renderBlockquote(n *ast.Blockquote) {
+	startPage, startY := r.pdf.PageNo(), r.pdf.GetY()
+	barX := r.left() + 3
+
+	r.indent += pdfQuoteIndent
+	r.applyIndent()
+	r.renderBlocks(n)
+	r.indent -= pdfQuoteIndent
+	r.applyIndent()
+
+	endY := r.pdf.GetY() - pdfBlockGap
+	if r.pdf.PageNo() != startPage {
+		startY = pdfMargin
+	}
+	r.setDraw(r.colors.primary)
+	r.pdf.SetLineWidth(3)
+	r.pdf.Line(barX, startY, barX, endY)
+	r.pdf.SetLineWidth(0.5)
+}
+
+func (r *pdfRenderer) renderList(list *ast.List) {
+	// Start is 1 for "1." and 0 for "0.", which is kept as written
+	number := list.Start
+
+	r.indent += pdfListIndent
+	r.applyIndent()
+
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		r.ensureSpace(pdfBodySize * pdfLineSpacing)
+
+		marker := r.tr("•")
+		if list.IsOrdered() {
+			marker = fmt.Sprintf("%d%c", number, list.Marker)
+			number++
+		}
+		if isTaskItem(item) {
+			marker = ""
+		}
+
+		if marker != "" {
+			r.pdf.SetFont("Helvetica", "", pdfBodySize)
+			r.setText(r.colors.secondary)
+			r.pdf.SetX(r.left() - pdfListIndent)
+			r.pdf.CellFormat(pdfListIndent-4, pdfBodySize*pdfLineSpacing, marker, "", 0, "R", false, 0, "")
+		}
+		r.pdf.SetX(r.left())
+		r.renderBlocks(item)
+	}
+
+	r.indent -= pdfListIndent
+	r.applyIndent()
+}
+
+// isTaskItem reports whether a list item starts with a GFM task checkbox
+func isTaskItem(item ast.Node) bool {
+	first := item.FirstChild()
+	if first == nil {
+		return false
+	}
+	_, ok := first.FirstChild().(*extast.TaskCheckBox)
+	return ok
+}
+
+// lines joins the raw source lines of a code-like block
+func (r *pdfRenderer) lines(n ast.Node) string {
+	var sb strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		sb.Write(line.Value(r.source))
+	}
+	return sb.String()
+}
+
+// codeSegment is a run of characters sharing one chroma style
+type codeSegment struct {
+	text  string
+	entry chroma.StyleEntry
+}
+
+func (r *pdfRenderer) renderCode(lang, code string) {
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	code = strings.ReplaceAll(strings.TrimRight(code, "\n"), "\t", "    ")
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return
+	}
+
+	background := r.colors.muted
+	if bg := r.syntaxStyle.Get(chroma.Background).Background; bg.IsSet() {
+		background = rgb{int(bg.Red()), int(bg.Green()), int(bg.Blue())}
+	}
+	defaultColor := r.colors.text
+	if fg := r.syntaxStyle.Get(chroma.Background).Colour; fg.IsSet() {
+		defaultColor = rgb{int(fg.Red()), int(fg.Green()), int(fg.Blue())}
+	}
+
+	r.pdf.SetFont("Courier", "", pdfCodeSize)
+	lineHeight := pdfCodeSize * 1.35
+	width := r.contentWidth()
+	maxChars := int((width - 2*pdfCodePadding) / r.pdf.GetStringWidth("m"))
+	if maxChars < 1 {
+		maxChars = 1
+	}
+
+	// Break every source line into visual lines of at most maxChars
+	var visual [][]codeSegment
+	for _, tokens := range chroma.SplitTokensIntoLines(iterator.Tokens()) {
+		var current []codeSegment
+		count := 0
+		for _, tok := range tokens {
+			value := []rune(strings.TrimRight(tok.Value, "\n"))
+			entry := r.syntaxStyle.Get(tok.Type)
+			for len(value) > 0 {
+				if count == maxChars {
+					visual = append(visual, current)
+					current, count = nil, 0
+				}
+				n := maxChars - count
+				if n > len(value) {
+					n = len(value)
+				}
+				current = append(current, codeSegment{text: string(value[:n]), entry: entry})
+				count += n
+				value = value[n:]
+			}
+		}
+		visual = append(visual, current)
+	}
+
+	x := r.left()
+	r.setFill(background)
+	r.ensureSpace(lineHeight + pdfCodePadding)
+	r.pdf.Rect(x, r.pdf.GetY(), width, pdfCodePadding, "F")
+	r.pdf.SetY(r.pdf.GetY() + pdfCodePadding)
+
+	for _, line := range visual {
+		r.ensureSpace(lineHeight)
+		y := r.pdf.GetY()
+		r.setFill(background)
+		r.pdf.Rect(x, y, width, lineHeight, "F")
+		r.pdf.SetXY(x+pdfCodePadding, y)
+		for _, seg := range line {
+			fontStyle := ""
+			if seg.entry.Bold == chroma.Yes {
+				fontStyle += "B"
+			}
+			if seg.entry.Italic == chroma.Yes {
+				fontStyle += "I"
+			}
+			r.pdf.SetFont("Courier", fontStyle, pdfCodeSize)
+			color := defaultColor
+			if seg.entry.Colour.IsSet() {
+				color = rgb{int(seg.entry.Colour.Red()), int(seg.entry.Colour.Green()), int(seg.entry.Colour.Blue())}
+			}
+			r.setText(color)
+			txt := r.tr(seg.text)
+			r.pdf.CellFormat(r.pdf.GetStringWidth(txt), lineHeight, txt, "", 0, "L", false, 0, "")
+		}
+		r.pdf.SetXY(x, y+lineHeight)
+	}
+
+	r.setFill(background)
+	r.pdf.Rect(x, r.pdf.GetY(), width, pdfCodePadding, "F")
+	r.pdf.SetY(r.pdf.GetY() + pdfCodePadding)
+	r.pdf.Ln(pdfBlockGap)
+}
+
+// ====================================================================
+// TABLES
+// ====================================================================
+
+func (r *pdfRenderer) renderTable(t *extast.Table) {
+	var rows [][]string
+	for row := t.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, r.tr(plainText(cell, r.source)))
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := len(t.Alignments)
+	for _, cells := range rows {
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+	}
+	hasHeader := false
+	if _, ok := t.FirstChild().(*extast.TableHeader); ok {
+		hasHeader = true
+	}
+
+	// Natural column widths, shrunk proportionally to fit the page
+	widths := make([]float64, columns)
+	total := 0.0
+	for i, cells := range rows {
+		style := ""
+		if i == 0 && hasHeader {
+			style = "B"
+		}
+		r.pdf.SetFont("Helvetica", style, pdfTableSize)
+		for c, cell := range cells {
+			if w := r.pdf.GetStringWidth(cell) + 2*pdfCellPadding + 2; w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+	for _, w := range widths {
+		total += w
+	}
+	available := r.contentWidth()
+	if total > available {
+		for c := range widths {
+			widths[c] = widths[c] / total * available
+		}
+	}
+
+	lineHeight := pdfTableSize * 1.35
+
+	// wrapRow splits every cell of a row into lines and returns the row height
+	wrapRow := func(cells []string, header bool) ([][][]byte, float64) {
+		style := ""
+		if header {
+			style = "B"
+		}
+		r.pdf.SetFont("Helvetica", style, pdfTableSize)
+
+		wrapped := make([][][]byte, columns)
+		height := lineHeight
+		for c := 0; c < columns; c++ {
+			cell := ""
+			if c < len(cells) {
+				cell = cells[c]
+			}
+			wrapped[c] = r.pdf.SplitLines([]byte(cell), widths[c]-2*pdfCellPadding)
+			if h := float64(len(wrapped[c])) * lineHeight; h > height {
+				height = h
+			}
+		}
+		return wrapped, height + 2*pdfCellPadding
+	}
+
+	drawRow := func(cells []string, header bool) {
+		wrapped, height := wrapRow(cells, header)
+
+		x, y := r.left(), r.pdf.GetY()
+		r.setDraw(r.colors.secondary)
+		r.pdf.SetLineWidth(0.5)
+		for c := 0; c < columns; c++ {
+			fill := "D"
+			if header {
+				r.setFill(r.colors.muted)
+				fill = "FD"
+			}
+			r.pdf.Rect(x, y, widths[c], height, fill)
+
+			align := "L"
+			if c < len(t.Alignments) {
+				switch t.Alignments[c] {
+				case extast.AlignCenter:
+					align = "C"
+				case extast.AlignRight:
+					align = "R"
+				}
+			}
+			r.setText(r.colors.text)
+			if header {
+				r.setText(r.colors.header)
+			}
+			for i, line := range wrapped[c] {
+				r.pdf.SetXY(x+pdfCellPadding, y+pdfCellPadding+float64(i)*lineHeight)
+				r.pdf.CellFormat(widths[c]-2*pdfCellPadding, lineHeight, string(line), "", 0, align, false, 0, "")
+			}
+			x += widths[c]
+		}
+		r.pdf.SetXY(r.left(), y+height)
+	}
+
+	for i, cells := range rows {
+		header := i == 0 && hasHeader
+		_, pageHeight := r.pdf.GetPageSize()
+		if _, height := wrapRow(cells, header); r.pdf.GetY()+height > pageHeight-pdfMargin {
+			r.pdf.AddPage()
+			// Repeat the header row at the top of every page
+			if hasHeader && !header {
+				drawRow(rows[0], true)
+			}
+		}
+		drawRow(cells, header)
+	}
+	r.pdf.Ln(pdfBlockGap)
+}
+
+// ====================================================================
+// INLINES
+// ====================================================================
+
+func (r *pdfRenderer) writeInlines(parent ast.Node, style inlineStyle) {
+	for c := parent.FirstChild(); c != nil; c = c.NextSibling() {
+		switch n := c.(type) {
+		case *ast.Text:
+			r.writeText(string(n.Segment.Value(r.source)), style)
+			if n.HardLineBreak() {
+				r.pdf.Ln(r.lineHeight)
+				r.pdf.SetX(r.left())
+			} else if n.SoftLineBreak() {
+				r.writeText(" ", style)
+			}
+		case *ast.String:
+			r.writeText(string(n.Value), style)
+		case *ast.CodeSpan:
+			s := style
+			s.mono = true
+			s.color = r.colors.code
+			r.writeText(plainText(n, r.source), s)
+		case *mathjax.InlineMath:
+			s := style
+			s.mono = true
+			s.color = r.colors.code
+			r.writeText(plainText(n, r.source), s)
+		case *ast.Emphasis:
+			s := style
+			if n.Level >= 2 {
+				s.bold = true
+			} else {
+				s.italic = true
+			}
+			r.writeInlines(n, s)
+		case *extast.Strikethrough:
+			s := style
+			s.strike = true
+			r.writeInlines(n, s)
+		case *ast.Link:
+			s := style
+			s.link = string(n.Destination)
+			s.color = r.colors.link
+			r.writeInlines(n, s)
+		case *ast.AutoLink:
+			s := style
+			s.link = string(n.URL(r.source))
+			if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(s.link, "mailto:") {
+				s.link = "mailto:" + s.link
+			}
+			s.color = r.colors.link
+			r.writeText(string(n.Label(r.source)), s)
+		case *ast.Image:
+			r.writeImage(n, style)
+		case *extast.TaskCheckBox:
+			r.writeCheckBox(n.IsChecked, style)
+		case *ast.RawHTML:
+			// Inline HTML is dropped
+		default:
+			r.writeInlines(n, style)
+		}
+	}
+}
+
+func (r *pdfRenderer) writeText(txt string, style inlineStyle) {
+	if txt == "" {
+		return
+	}
+
+	family := "Helvetica"
+	size := style.size
+	if style.mono {
+		family = "Courier"
+		size--
+	}
+	fontStyle := ""
+	if style.bold {
+		fontStyle += "B"
+	}
+	if style.italic {
+		fontStyle += "I"
+	}
+	if style.strike {
+		fontStyle += "S"
+	}
+	if style.link != "" {
+		fontStyle += "U"
+	}
+	r.pdf.SetFont(family, fontStyle, size)
+	r.setText(style.color)
+
+	if style.link != "" {
+		r.pdf.WriteLinkString(r.lineHeight, r.tr(txt), style.link)
+		return
+	}
+	r.pdf.Write(r.lineHeight, r.tr(txt))
+}
+
+func (r *pdfRenderer) writeCheckBox(checked bool, style inlineStyle) {
+	glyph := "o" // ❏ in ZapfDingbats
+	if checked {
+		glyph = "4" // ✔
+	}
+	r.pdf.SetFont("ZapfDingbats", "", style.size)
+	r.setText(r.colors.primary)
+	r.pdf.Write(r.lineHeight, glyph)
+	r.writeText(" ", style)
+}
+
+// writeImage places a local image on its own line, scaled to fit the page.
+// Remote or unsupported images fall back to their alt text.
+func (r *pdfRenderer) writeImage(n *ast.Image, style inlineStyle) {
+	alt := plainText(n, r.source)
+	path, imageType, ok := r.resolveImage(string(n.Destination))
+	if !ok {
+		s := style
+		s.italic = true
+		s.color = r.colors.secondary
+		r.writeText(fmt.Sprintf("[%s]", alt), s)
+		return
+	}
+
+	options := fpdf.ImageOptions{ImageType: imageType, ReadDpi: true}
+	info := r.pdf.RegisterImageOptions(path, options)
+	if !r.pdf.Ok() || info == nil {
+		// A broken image should not abort the whole document
+		r.pdf.ClearError()
+		s := style
+		s.italic = true
+		s.color = r.colors.secondary
+		r.writeText(fmt.Sprintf("[%s]", alt), s)
+		return
+	}
+
+	_, pageHeight := r.pdf.GetPageSize()
+	w, h := info.Width(), info.Height()
+	if maxW := r.contentWidth(); w > maxW {
+		h = h * maxW / w
+		w = maxW
+	}
+	if maxH := pageHeight - 2*pdfMargin - r.lineHeight; h > maxH {
+		w = w * maxH / h
+		h = maxH
+	}
+
+	if r.pdf.GetX() > r.left() {
+		r.pdf.Ln(r.lineHeight)
+	}
+	r.ensureSpace(h)
+	r.pdf.ImageOptions(path, r.left(), r.pdf.GetY(), w, h, true, options, 0, "")
+	r.pdf.SetX(r.left())
+}
+
+// resolveImage maps an image destination to a readable local file. Only
+// files inside the base directory are embedded, also after resolving
+// symlinks, so a document cannot pull in arbitrary files from the disk.
+func (r *pdfRenderer) resolveImage(dest string) (path, imageType string, ok bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return "", "", false
+	}
+
+	rel := filepath.FromSlash(u.Path)
+	if !filepath.IsLocal(rel) {
+		return "", "", false
+	}
+	path, err = filepath.EvalSymlinks(filepath.Join(r.baseDir, rel))
+	if err != nil {
+		return "", "", false
+	}
+	root, err := filepath.EvalSymlinks(r.baseDir)
+	if err != nil {
+		return "", "", false
+	}
+	if inside, err := filepath.Rel(root, path); err != nil || !filepath.IsLocal(inside) {
+		return "", "", false
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return path, "png", true
+	case ".jpg", ".jpeg":
+		return path, "jpg", true
+	case ".gif":
+		return path, "gif", true
+	}
+	return "", "", false
+}
+
+// plainText concatenates the text content below n
+func plainText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := node.(type) {
+		case *ast.Text:
+			sb.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(t.Value)
+		case *ast.AutoLink:
+			sb.Write(t.Label(source))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	pdfObject   = regexp.MustCompile(`(?s)(\d+) 0 obj\s*<<(.*?)>>\s*endobj`)
+	pdfTitle    = regexp.MustCompile(`/Title \((.*?)\)`)
+	pdfParent   = regexp.MustCompile(`/Parent (\d+) 0 R`)
+	pdfOutlines = regexp.MustCompile(`/Type /Outlines`)
+)
+
+// outlineParents maps the title of every outline entry in a PDF to the
+// title of its parent, or to "" for entries at the top level
+func outlineParents(t *testing.T, pdf []byte) map[string]string {
+	t.Helper()
+	titles := map[string]string{}
+	parents := map[string]string{}
+	root := ""
+	for _, m := range pdfObject.FindAllSubmatch(pdf, -1) {
+		id, dict := string(m[1]), m[2]
+		if pdfOutlines.Match(dict) {
+			root = id
+			continue
+		}
+		title := pdfTitle.FindSubmatch(dict)
+		parent := pdfParent.FindSubmatch(dict)
+		if title == nil || parent == nil {
+			continue
+		}
+		if string(parent[1]) == id {
+			t.Fatalf("outline entry %q is its own parent", title[1])
+		}
+		titles[id] = string(title[1])
+		parents[id] = string(parent[1])
+	}
+	if root == "" {
+		t.Fatal("PDF has no outline")
+	}
+
+	tree := map[string]string{}
+	for id, title := range titles {
+		parent := parents[id]
+		if parent != root {
+			if _, ok := titles[parent]; !ok {
+				t.Fatalf("outline entry %q has unknown parent %s", title, parent)
+			}
+		}
+		tree[title] = titles[parent]
+	}
+	return tree
+}
+
+func TestRenderPDFOutline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "levels in order",
+			input: "# A\n## B\n### C\n## D\n",
+			want:  map[string]string{"A": "", "B": "A", "C": "B", "D": "A"},
+		},
+		{
+			name:  "starts below the top level",
+			input: "## A\n#### B\n",
+			want:  map[string]string{"A": "", "B": "A"},
+		},
+		{
+			name:  "skipped level",
+			input: "# A\n### B\n## C\n# D\n",
+			want:  map[string]string{"A": "", "B": "A", "C": "A", "D": ""},
+		},
+		{
+			name:  "deeper heading first",
+			input: "### A\n# B\n## C\n",
+			want:  map[string]string{"A": "", "B": "", "C": "B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf, err := RenderPDF(RenderOptions{}, PDFSource{Input: tt.input})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := outlineParents(t, pdf)
+			if len(got) != len(tt.want) {
+				t.Fatalf("outline has %d entries, want %d: %v", len(got), len(tt.want), got)
+			}
+			for title, parent := range tt.want {
+				if got[title] != parent {
+					t.Errorf("parent of %q = %q, want %q", title, got[title], parent)
+				}
+			}
+		})
+	}
+}
+
+var pdfStream = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// pdfContent returns the decompressed content streams of a PDF
+func pdfContent(t *testing.T, pdf []byte) string {
+	t.Helper()
+	var sb strings.Builder
+	for _, m := range pdfStream.FindAllSubmatch(pdf, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.Write(data)
+	}
+	return sb.String()
+}
+
+func TestRenderPDFListStart(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"0. zero\n1. one\n", []string{"(0.)", "(1.)"}},
+		{"1. one\n2. two\n", []string{"(1.)", "(2.)"}},
+		{"7) seven\n8) eight\n", []string{"(7\\))", "(8\\))"}},
+	}
+
+	for _, tt := range tests {
+		pdf, err := RenderPDF(RenderOptions{}, PDFSource{Input: tt.input})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := pdfContent(t, pdf)
+		for _, want := range tt.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("PDF of %q does not show the marker %s", tt.input, want)
+			}
+		}
+	}
+}
+
+func TestResolveImage(t *testing.T) {
+	outside := t.TempDir()
+	base := t.TempDir()
+	for _, name := range []string{filepath.Join(base, "a.png"), filepath.Join(base, "img", "b.jpg"), filepath.Join(outside, "secret.png")} {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.png"), filepath.Join(base, "link.png")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	tests := []struct {
+		dest string
+		want string
+	}{
+		{"a.png", "png"},
+		{"./img/b.jpg", "jpg"},
+		{"img/../a.png", "png"},
+		{"missing.png", ""},
+		{"../" + filepath.Base(outside) + "/secret.png", ""},
+		{filepath.ToSlash(filepath.Join(outside, "secret.png")), ""},
+		{"file://" + filepath.ToSlash(filepath.Join(outside, "secret.png")), ""},
+		{"link.png", ""},
+		{"https://example.com/a.png", ""},
+	}
+
+	r := &pdfRenderer{baseDir: base}
+	for _, tt := range tests {
+		if _, imageType, _ := r.resolveImage(tt.dest); imageType != tt.want {
+			t.Errorf("resolveImage(%q) type = %q, want %q", tt.dest, imageType, tt.want)
+		}
+	}
+}
@@ -5,47 +5,127 @@ import (
 	"os"
 	"strings"
 
-	markdown "github.com/MichaelMure/go-term-markdown"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
 	mathjax "github.com/litao91/goldmark-mathjax"
 	"github.com/tacheraSasi/mdcli/themes"
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
 	"go.abhg.dev/goldmark/mermaid"
 )
 
+// MaxWidth is the widest text and terminal output, in columns. Wider
+// widths are reduced to it, and widths below 1 mean the default of 80.
+const MaxWidth = 1000
+
 type RenderOptions struct {
 	Input        string
 	Autolink     bool
 	Theme        string
 	Width        int
 	OutputFormat string
+	// BaseDir is used to resolve relative image paths (PDF output)
+	BaseDir string
+	// RawFrontMatter leaves a leading front matter block in the Markdown
+	RawFrontMatter bool
+	// NoScripts omits the CDN scripts extensions add to HTML output, for
+	// pages that load their own copies (serve, site build)
+	NoScripts bool
+	// HighlightClasses marks code with chroma CSS classes instead of inline
+	// styles. The page must include themes.SyntaxCSS.
+	HighlightClasses bool
+	// Landscape lays PDF pages out in landscape orientation
+	Landscape bool
+	// MathML converts math to MathML in HTML output, so browsers show it
+	// without MathJax
+	MathML bool
 }
 
-func Render(opts RenderOptions) (string, error) {
-	// Set defaults
+func Render(opts RenderOptions) (*Document, error) {
+	opts = withDefaults(opts)
+
+	doc := &Document{}
+	if !opts.RawFrontMatter {
+		fm, body := ParseFrontMatter(opts.Input)
+		doc.FrontMatter = fm
+		opts.Input = body
+	}
+
+	md := newMarkdown(opts)
+	source := []byte(opts.Input)
+	root := md.Parser().Parse(text.NewReader(source))
+	doc.Outline = Outline(root, source)
+
+	if opts.OutputFormat == "pdf" {
+		// The front matter was already stripped above
+		opts.RawFrontMatter = true
+		out, err := RenderPDF(opts, PDFSource{Input: opts.Input, BaseDir: opts.BaseDir})
+		if err != nil {
+			return nil, err
+		}
+		doc.Output = string(out)
+		return doc, nil
+	}
+
+	if opts.OutputFormat != "html" && opts.OutputFormat != "text" && opts.OutputFormat != "plain" {
+		// terminal, also used for unknown formats
+		doc.Output = renderTerminal(root, source, opts)
+		return doc, nil
+	}
+
+	if opts.OutputFormat != "html" {
+		doc.Output = renderText(root, source, opts.Width)
+		return doc, nil
+	}
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, source, root); err != nil {
+		return nil, err
+	}
+	doc.Output = themedHTML(buf.String(), opts.Theme)
+	return doc, nil
+}
+
+// withDefaults fills in the zero values of opts
+func withDefaults(opts RenderOptions) RenderOptions {
 	if opts.Theme == "" {
 		opts.Theme = "dracula"
 	}
-	if opts.Width == 0 {
+	if opts.Width <= 0 {
 		opts.Width = 80
 	}
+	opts.Width = min(opts.Width, MaxWidth)
 	if opts.OutputFormat == "" {
 		opts.OutputFormat = "terminal"
 	}
+	return opts
+}
 
+// newMarkdown builds the goldmark pipeline shared by every output format
+func newMarkdown(opts RenderOptions) goldmark.Markdown {
 	// Get syntax highlighting style for theme
 	syntaxStyle := themes.GetSyntaxHighlightingStyle(opts.Theme)
-	
-	var md goldmark.Markdown
+
+	highlightOpts := []highlighting.Option{
+		highlighting.WithStyle(syntaxStyle),
+	}
+	if opts.HighlightClasses {
+		highlightOpts = append(highlightOpts, highlighting.WithFormatOptions(
+			chromahtml.WithClasses(true),
+		))
+	}
+
 	extensions := []goldmark.Extender{
 		extension.GFM,
-		highlighting.NewHighlighting(
-			highlighting.WithStyle(syntaxStyle),
-		),
+		highlighting.NewHighlighting(highlightOpts...),
 		mathjax.MathJax,
-		&mermaid.Extender{},
+		&mermaid.Extender{NoScript: opts.NoScripts},
+		tocExtension{},
+	}
+	if opts.MathML {
+		extensions = append(extensions, mathMLExtension{})
 	}
 
 	if opts.Autolink {
@@ -57,34 +137,19 @@ func Render(opts RenderOptions) (string, error) {
 		))
 	}
 
-	md = goldmark.New(
+	return goldmark.New(
 		goldmark.WithExtensions(extensions...),
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(),
 		),
 	)
-
-	var buf bytes.Buffer
-	if err := md.Convert([]byte(opts.Input), &buf); err != nil {
-		return "", err
-	}
-
-	switch opts.OutputFormat {
-	case "html":
-		return buf.String(), nil
-	case "text", "plain":
-		return stripHTML(buf.String()), nil
-	default: // terminal
-		result := markdown.Render(buf.String(), opts.Width, 6)
-		return string(result), nil
-	}
 }
 
-// stripHTML removes HTML tags from content for plain text output
+// stripHTML removes HTML tags from content
 func stripHTML(content string) string {
 	var result strings.Builder
 	inTag := false
-	
+
 	for _, char := range content {
 		if char == '<' {
 			inTag = true
@@ -94,11 +159,10 @@ func stripHTML(content string) string {
 			result.WriteRune(char)
 		}
 	}
-	
+
 	return result.String()
 }
 
-
 func ReadFile(file string) (string, error) {
 	// Reading file
 	content, err := os.ReadFile(file)
@@ -106,4 +170,4 @@ func ReadFile(file string) (string, error) {
 		return "", err
 	}
 	return string(content), nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,645 @@
+package renderer
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/fatih/color"
+	mathjax "github.com/litao91/goldmark-mathjax"
+	"github.com/mattn/go-runewidth"
+	"github.com/tacheraSasi/mdcli/diagram"
+	"github.com/tacheraSasi/mdcli/tex"
+	"github.com/tacheraSasi/mdcli/themes"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/util"
+	"go.abhg.dev/goldmark/mermaid"
+)
+
+// terminalMargin is the number of columns left blank on the left
+const terminalMargin = 2
+
+// colorMode is the range of colors the terminal can show
+type colorMode int
+
+const (
+	colorNone colorMode = iota
+	color256
+	colorTrue
+)
+
+// terminalColorMode picks the color mode from the environment. Terminals
+// that do not announce 24-bit color get the 256-color palette.
+func terminalColorMode() colorMode {
+	if color.NoColor {
+		return colorNone
+	}
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorTrue
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		return colorTrue
+	}
+	return color256
+}
+
+// ansiEscape matches the SGR and OSC 8 sequences the terminal renderer writes
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m|\x1b\\]8;[^\x1b\a]*(\x1b\\\\|\a)")
+
+// displayWidth returns the number of columns s takes up on screen
+func displayWidth(s string) int {
+	if strings.Contains(s, "\x1b") {
+		s = ansiEscape.ReplaceAllString(s, "")
+	}
+	return runewidth.StringWidth(s)
+}
+
+// termRenderer lays out a goldmark AST for the terminal
+type termRenderer struct {
+	source []byte
+	colors themes.ThemeColors
+	mode   colorMode
+	syntax *chroma.Style
+	// depth is the nesting level of the list being written
+	depth int
+}
+
+// renderTerminal renders the document below root for the terminal in the
+// theme palette, wrapped at width
+func renderTerminal(root ast.Node, source []byte, opts RenderOptions) string {
+	r := &termRenderer{
+		source: source,
+		colors: themeColors(opts.Theme),
+		mode:   terminalColorMode(),
+		syntax: styles.Get(themes.GetSyntaxHighlightingStyle(opts.Theme)),
+	}
+
+	lines := r.blocks(root, max(textMinWidth, opts.Width-terminalMargin), false)
+	if len(lines) == 0 {
+		return ""
+	}
+	margin := strings.Repeat(" ", terminalMargin)
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = margin + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// fg returns the SGR parameters for a foreground color in the color mode,
+// followed by attrs. Invalid colors keep the terminal default.
+func (r *termRenderer) fg(hex string, attrs ...string) string {
+	if r.mode == colorNone {
+		return ""
+	}
+	params := attrs
+	if red, green, blue, err := themes.HexToRGB(hex); err == nil {
+		if r.mode == colorTrue {
+			params = append(params, fmt.Sprintf("38;2;%d;%d;%d", red, green, blue))
+		} else {
+			params = append(params, fmt.Sprintf("38;5;%d", xterm256(red, green, blue)))
+		}
+	}
+	return strings.Join(params, ";")
+}
+
+// attr returns an SGR attribute such as bold, or nothing without color
+func (r *termRenderer) attr(params string) string {
+	if r.mode == colorNone {
+		return ""
+	}
+	return params
+}
+
+// paint wraps s in the SGR parameters style
+func paint(s, style string) string {
+	if style == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + style + "m" + s + "\x1b[0m"
+}
+
+// joinStyle combines the SGR parameters of nested styles
+func joinStyle(outer, inner string) string {
+	if outer == "" || inner == "" {
+		return outer + inner
+	}
+	return outer + ";" + inner
+}
+
+// xterm256 returns the closest color of the xterm 256-color palette,
+// choosing between the 6x6x6 cube and the gray ramp
+func xterm256(red, green, blue int) int {
+	level := func(c int) int {
+		if c < 48 {
+			return 0
+		}
+		if c < 115 {
+			return 1
+		}
+		return (c - 35) / 40
+	}
+	value := func(l int) int {
+		if l == 0 {
+			return 0
+		}
+		return 55 + 40*l
+	}
+	sq := func(a, b int) int { return (a - b) * (a - b) }
+
+	lr, lg, lb := level(red), level(green), level(blue)
+	cr, cg, cb := value(lr), value(lg), value(lb)
+	cube := 16 + 36*lr + 6*lg + lb
+
+	gray := (red + green + blue) / 3
+	grayIndex := 23
+	if gray < 238 {
+		grayIndex = max(0, (gray-3)/10)
+	}
+	gv := 8 + 10*grayIndex
+
+	if sq(gv, red)+sq(gv, green)+sq(gv, blue) < sq(cr, red)+sq(cg, green)+sq(cb, blue) {
+		return 232 + grayIndex
+	}
+	return cube
+}
+
+// blocks returns the lines of the blocks below n, separated by blank
+// lines unless tight
+func (r *termRenderer) blocks(n ast.Node, width int, tight bool) []string {
+	var lines []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		block := r.block(c, width)
+		if len(block) == 0 {
+			continue
+		}
+		if len(lines) > 0 && !tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+func (r *termRenderer) block(n ast.Node, width int) []string {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return wrapText(r.inline(n, ""), width)
+	case *ast.Heading:
+		return r.heading(n, width)
+	case *ast.ThematicBreak:
+		return []string{paint(strings.Repeat("─", width), r.fg(r.colors.Secondary))}
+	case *ast.Blockquote:
+		bar := paint("┃ ", r.fg(r.colors.Primary))
+		var lines []string
+		for _, line := range r.blocks(n, textWidth(width, 2), false) {
+			lines = append(lines, bar+line)
+		}
+		return lines
+	case *ast.List:
+		return r.list(n, width)
+	case *ast.FencedCodeBlock:
+		return r.code(n, string(n.Language(r.source)))
+	case *ast.CodeBlock:
+		return r.code(n, "")
+	case *mathjax.MathBlock:
+		return r.math(n, width)
+	case *mermaid.Block:
+		return r.diagram(n, width)
+	case *ast.HTMLBlock:
+		var raw strings.Builder
+		for i := 0; i < n.Lines().Len(); i++ {
+			segment := n.Lines().At(i)
+			raw.Write(segment.Value(r.source))
+		}
+		var lines []string
+		for _, line := range strings.Split(html.UnescapeString(stripHTML(raw.String())), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, wrapText(paint(line, r.fg(r.colors.Secondary)), width)...)
+			}
+		}
+		return lines
+	case *extast.Table:
+		return r.table(n, width)
+	case *TOC:
+		entries := n.Entries()
+		var lines []string
+		for i, depth := range tocDepths(entries) {
+			bullet := paint("•", r.fg(r.colors.Accent))
+			lines = append(lines, strings.Repeat("  ", depth)+bullet+" "+paint(entries[i].Text, r.fg(r.colors.Link)))
+		}
+		return lines
+	case *mermaid.ScriptBlock:
+		return nil
+	}
+	return r.blocks(n, width, false)
+}
+
+// heading writes levels 1 and 2 in the header color, level 1 with a rule
+// below, and deeper levels in the primary color
+func (r *termRenderer) heading(n *ast.Heading, width int) []string {
+	style := r.fg(r.colors.Header, "1")
+	if n.Level > 2 {
+		style = r.fg(r.colors.Primary, "1")
+	}
+	if n.Level > 3 {
+		style = r.fg(r.colors.Primary)
+	}
+	lines := wrapText(r.inline(n, style), width)
+	if n.Level == 1 && len(lines) > 0 {
+		lines = append(lines, paint(strings.Repeat("─", width), r.fg(r.colors.Secondary)))
+	}
+	return lines
+}
+
+// list writes items after a bullet, number or checkbox in the accent
+// color, with their content indented past the marker
+func (r *termRenderer) list(n *ast.List, width int) []string {
+	bullets := []string{"•", "◦", "▪"}
+	bullet := bullets[r.depth%len(bullets)]
+	r.depth++
+	defer func() { r.depth-- }()
+
+	markerWidth := 1
+	if n.IsOrdered() {
+		markerWidth = len(fmt.Sprintf("%d.", n.Start+n.ChildCount()-1))
+	}
+
+	var lines []string
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		if item != n.FirstChild() && !n.IsTight {
+			lines = append(lines, "")
+		}
+		marker := paint(bullet, r.fg(r.colors.Accent))
+		if n.IsOrdered() {
+			marker = paint(fmt.Sprintf("%d.", number), r.fg(r.colors.Accent))
+			number++
+		}
+		if box := taskCheckBox(item); box != nil {
+			if box.IsChecked {
+				marker = paint("☑", r.fg(r.colors.Accent))
+			} else {
+				marker = paint("☐", r.fg(r.colors.Secondary))
+			}
+		}
+		marker += strings.Repeat(" ", max(0, markerWidth-displayWidth(marker)))
+		indent := strings.Repeat(" ", markerWidth+1)
+
+		content := r.blocks(item, textWidth(width, len(indent)), n.IsTight)
+		if len(content) == 0 {
+			lines = append(lines, marker)
+			continue
+		}
+		for i, line := range content {
+			switch {
+			case i == 0:
+				line = marker + " " + line
+			case line != "":
+				line = indent + line
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// taskCheckBox returns the checkbox starting a task list item, if any
+func taskCheckBox(item ast.Node) *extast.TaskCheckBox {
+	first := item.FirstChild()
+	if first == nil {
+		return nil
+	}
+	box, _ := first.FirstChild().(*extast.TaskCheckBox)
+	return box
+}
+
+// code writes the lines of a code block behind a gutter, highlighted by
+// chroma in the theme's syntax style
+func (r *termRenderer) code(n ast.Node, language string) []string {
+	var source strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		segment := n.Lines().At(i)
+		source.Write(segment.Value(r.source))
+	}
+	code := strings.TrimRight(strings.ReplaceAll(source.String(), "\t", "    "), "\n")
+
+	gutter := paint("│ ", r.fg(r.colors.Secondary))
+	var lines []string
+	if language != "" {
+		lines = append(lines, paint(language, r.fg(r.colors.Secondary, "3")))
+	}
+	for _, line := range strings.Split(r.highlight(code, language), "\n") {
+		lines = append(lines, gutter+line)
+	}
+	return lines
+}
+
+// diagram draws a Mermaid block, or shows its source below a notice when
+// the diagram cannot be drawn
+func (r *termRenderer) diagram(n *mermaid.Block, width int) []string {
+	var source strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		segment := n.Lines().At(i)
+		source.Write(segment.Value(r.source))
+	}
+	lines, err := diagram.Render(source.String(), width)
+	if err != nil {
+		notice := paint("Diagram not drawn: "+err.Error(), r.fg(r.colors.Secondary, "3"))
+		return append([]string{notice}, r.code(n, "mermaid")...)
+	}
+	for i, line := range lines {
+		lines[i] = paint(line, r.fg(r.colors.Primary))
+	}
+	return lines
+}
+
+// math draws display math as Unicode text, or shows its TeX as code when
+// it cannot be converted or is too wide
+func (r *termRenderer) math(n *mathjax.MathBlock, width int) []string {
+	lines, err := tex.Block(mathSource(n, r.source))
+	if err != nil {
+		return r.code(n, "latex")
+	}
+	for i, line := range lines {
+		if runewidth.StringWidth(line)+2 > width {
+			return r.code(n, "latex")
+		}
+		lines[i] = "  " + paint(line, r.fg(r.colors.Code))
+	}
+	return lines
+}
+
+// highlight colors code by token. Every line is painted separately so it
+// can be prefixed on its own.
+func (r *termRenderer) highlight(code, language string) string {
+	if r.mode == colorNone {
+		return code
+	}
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		return paint(code, r.fg(r.colors.Code))
+	}
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return paint(code, r.fg(r.colors.Code))
+	}
+
+	text := r.syntax.Get(chroma.Background).Colour
+	var b strings.Builder
+	for _, token := range tokens.Tokens() {
+		entry := r.syntax.Get(token.Type)
+		var attrs []string
+		if entry.Bold == chroma.Yes {
+			attrs = append(attrs, "1")
+		}
+		if entry.Italic == chroma.Yes {
+			attrs = append(attrs, "3")
+		}
+		style := strings.Join(attrs, ";")
+		// Plain text keeps the terminal's own foreground
+		if entry.Colour.IsSet() && entry.Colour != text {
+			style = r.fg(entry.Colour.String(), attrs...)
+		}
+		for i, part := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(paint(part, style))
+		}
+	}
+	return b.String()
+}
+
+// table draws a table with box-drawing borders. Columns are narrowed and
+// their cells wrapped when the table is wider than width.
+func (r *termRenderer) table(n *extast.Table, width int) []string {
+	var rows [][]string
+	var widths []int
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		style := ""
+		if _, ok := row.(*extast.TableHeader); ok {
+			style = r.fg(r.colors.Header, "1")
+		}
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			text := strings.Join(strings.Fields(r.inline(cell, style)), " ")
+			if len(cells) >= len(widths) {
+				widths = append(widths, 1)
+			}
+			widths[len(cells)] = max(widths[len(cells)], displayWidth(text))
+			cells = append(cells, text)
+		}
+		rows = append(rows, cells)
+	}
+	if len(widths) == 0 {
+		return nil
+	}
+
+	// Each column takes its width plus a space either side and a border
+	for total(widths)+3*len(widths)+1 > width {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 4 {
+			break
+		}
+		widths[widest]--
+	}
+
+	border := r.fg(r.colors.Secondary)
+	rule := func(left, mid, right string) string {
+		var parts []string
+		for _, w := range widths {
+			parts = append(parts, strings.Repeat("─", w+2))
+		}
+		return paint(left+strings.Join(parts, mid)+right, border)
+	}
+	bar := paint("│", border)
+
+	lines := []string{rule("┌", "┬", "┐")}
+	for i, cells := range rows {
+		wrapped := make([][]string, len(widths))
+		height := 1
+		for j := range widths {
+			if j < len(cells) {
+				wrapped[j] = wrapText(cells[j], widths[j])
+			}
+			height = max(height, len(wrapped[j]))
+		}
+		for k := 0; k < height; k++ {
+			var b strings.Builder
+			b.WriteString(bar)
+			for j, w := range widths {
+				cell := ""
+				if k < len(wrapped[j]) {
+					cell = wrapped[j][k]
+				}
+				align := extast.AlignNone
+				if j < len(n.Alignments) {
+					align = n.Alignments[j]
+				}
+				b.WriteString(" " + alignCell(cell, w, align) + " " + bar)
+			}
+			lines = append(lines, b.String())
+		}
+		if i == 0 && len(rows) > 1 {
+			lines = append(lines, rule("├", "┼", "┤"))
+		}
+	}
+	return append(lines, rule("└", "┴", "┘"))
+}
+
+// total returns the sum of widths
+func total(widths []int) int {
+	sum := 0
+	for _, w := range widths {
+		sum += w
+	}
+	return sum
+}
+
+// alignCell pads cell to width according to align
+func alignCell(cell string, width int, align extast.Alignment) string {
+	padding := max(0, width-displayWidth(cell))
+	switch align {
+	case extast.AlignRight:
+		return strings.Repeat(" ", padding) + cell
+	case extast.AlignCenter:
+		return strings.Repeat(" ", padding/2) + cell + strings.Repeat(" ", padding-padding/2)
+	}
+	return cell + strings.Repeat(" ", padding)
+}
+
+// inline returns the inline content below n painted in style. Hard line
+// breaks are kept as newlines.
+func (r *termRenderer) inline(n ast.Node, style string) string {
+	var b strings.Builder
+	r.writeInline(&b, n, style, "")
+	return b.String()
+}
+
+func (r *termRenderer) writeInline(b *strings.Builder, n ast.Node, style, link string) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			value := c.Segment.Value(r.source)
+			if !c.IsRaw() {
+				value = util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+			}
+			r.writeWords(b, string(value), style, link)
+			if c.HardLineBreak() {
+				b.WriteByte('\n')
+			} else if c.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			r.writeWords(b, string(c.Value), style, link)
+		case *ast.CodeSpan:
+			var code strings.Builder
+			for t := c.FirstChild(); t != nil; t = t.NextSibling() {
+				if t, ok := t.(*ast.Text); ok {
+					code.Write(t.Segment.Value(r.source))
+				}
+			}
+			text := strings.ReplaceAll(code.String(), "\n", " ")
+			if r.mode == colorNone {
+				text = "`" + text + "`"
+			}
+			r.writeWords(b, text, joinStyle(style, r.fg(r.colors.Code)), link)
+		case *ast.Emphasis:
+			attr := "3"
+			if c.Level == 2 {
+				attr = "1"
+			}
+			r.writeInline(b, c, joinStyle(style, r.attr(attr)), link)
+		case *extast.Strikethrough:
+			r.writeInline(b, c, joinStyle(style, r.attr("9")), link)
+		case *ast.AutoLink:
+			dest := string(c.URL(r.source))
+			if c.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(dest, "mailto:") {
+				dest = "mailto:" + dest
+			}
+			r.writeWords(b, string(c.Label(r.source)), joinStyle(style, r.fg(r.colors.Link, "4")), dest)
+		case *ast.Link:
+			dest := string(c.Destination)
+			text := r.inline(c, "")
+			r.writeInline(b, c, joinStyle(style, r.fg(r.colors.Link, "4")), dest)
+			r.writeTarget(b, dest, text)
+		case *ast.Image:
+			dest := string(c.Destination)
+			alt := r.inline(c, "")
+			label := "[image]"
+			if alt != "" {
+				label = "[image: " + ansiEscape.ReplaceAllString(alt, "") + "]"
+			}
+			r.writeWords(b, label, joinStyle(style, r.fg(r.colors.Link)), dest)
+			r.writeTarget(b, dest, "")
+		case *mathjax.InlineMath:
+			source := mathSource(c, r.source)
+			if text, err := tex.Inline(source); err == nil {
+				source = text
+			}
+			r.writeWords(b, source, joinStyle(style, r.fg(r.colors.Code)), link)
+		case *extast.TaskCheckBox, *ast.RawHTML:
+			// Checkboxes are drawn as the list marker, and inline tags
+			// have no text of their own
+		default:
+			r.writeInline(b, c, style, link)
+		}
+	}
+}
+
+// writeWords writes text painted in style, as an OSC 8 hyperlink to link
+// when set. Every word is wrapped on its own so lines can break between
+// words without carrying escape sequences over.
+func (r *termRenderer) writeWords(b *strings.Builder, text, style, link string) {
+	if r.mode == colorNone || (style == "" && link == "") {
+		b.WriteString(text)
+		return
+	}
+	start, end := "", ""
+	if link != "" {
+		start = "\x1b]8;;" + strings.ReplaceAll(link, " ", "%20") + "\x1b\\"
+		end = "\x1b]8;;\x1b\\"
+	}
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			b.WriteString(start + paint(word.String(), style) + end)
+			word.Reset()
+		}
+	}
+	for _, ch := range text {
+		if ch == ' ' || ch == '\n' {
+			flush()
+			b.WriteRune(ch)
+			continue
+		}
+		word.WriteRune(ch)
+	}
+	flush()
+}
+
+// writeTarget shows the target of a link after its text when the
+// terminal cannot make the text clickable
+func (r *termRenderer) writeTarget(b *strings.Builder, dest, text string) {
+	if r.mode != colorNone || dest == "" || dest == text || strings.HasPrefix(dest, "#") {
+		return
+	}
+	b.WriteString(" <" + dest + ">")
+}
@@ -0,0 +1,39 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestRenderTerminalDiagram(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"drawn", "```mermaid\ngraph TD\n  A[Start] --> B[End]\n```\n", []string{"│ Start │", "▼"}},
+		{"unsupported", "```mermaid\npie title Pets\n  \"Dogs\" : 386\n```\n", []string{
+			"Diagram not drawn: unsupported diagram type: pie",
+			"│ pie title Pets",
+			`│   "Dogs" : 386`,
+		}},
+	}
+
+	for _, tt := range tests {
+		doc, err := Render(RenderOptions{Input: tt.source, OutputFormat: "terminal"})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(doc.Output, want) {
+				t.Errorf("%s: output\n%s\nwant it to contain %q", tt.name, doc.Output, want)
+			}
+		}
+	}
+}
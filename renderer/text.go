@@ -0,0 +1,340 @@
+package renderer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	mathjax "github.com/litao91/goldmark-mathjax"
+	"github.com/mattn/go-runewidth"
+	"github.com/tacheraSasi/mdcli/tex"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/util"
+	"go.abhg.dev/goldmark/mermaid"
+)
+
+// textMinWidth is the narrowest width nested blocks are wrapped at
+const textMinWidth = 20
+
+// textRenderer lays out a goldmark AST as plain text
+type textRenderer struct {
+	source []byte
+	// refs are the link targets listed at the end, numbered from 1
+	refs    []string
+	refNums map[string]int
+}
+
+// renderText returns the plain text of a document wrapped at width:
+// underlined headings, list markers, aligned tables and code indented by
+// four spaces. Link targets are numbered and listed at the end.
+func renderText(root ast.Node, source []byte, width int) string {
+	r := &textRenderer{source: source, refNums: make(map[string]int)}
+	lines := r.blocks(root, width, false)
+	if len(r.refs) > 0 {
+		lines = append(lines, "")
+		for i, ref := range r.refs {
+			lines = append(lines, fmt.Sprintf("[%d] %s", i+1, ref))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// blocks returns the lines of the blocks below n, separated by blank
+// lines unless tight
+func (r *textRenderer) blocks(n ast.Node, width int, tight bool) []string {
+	var lines []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		block := r.block(c, width)
+		if len(block) == 0 {
+			continue
+		}
+		if len(lines) > 0 && !tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+func (r *textRenderer) block(n ast.Node, width int) []string {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return wrapText(r.inline(n), width)
+	case *ast.Heading:
+		lines := wrapText(r.inline(n), width)
+		if n.Level > 2 || len(lines) == 0 {
+			return lines
+		}
+		underline := "="
+		if n.Level == 2 {
+			underline = "-"
+		}
+		longest := 0
+		for _, line := range lines {
+			longest = max(longest, runewidth.StringWidth(line))
+		}
+		return append(lines, strings.Repeat(underline, longest))
+	case *ast.ThematicBreak:
+		return []string{strings.Repeat("-", width)}
+	case *ast.Blockquote:
+		var lines []string
+		for _, line := range r.blocks(n, textWidth(width, 2), false) {
+			lines = append(lines, strings.TrimRight("> "+line, " "))
+		}
+		return lines
+	case *ast.List:
+		return r.list(n, width)
+	case *mathjax.MathBlock:
+		lines, err := tex.Block(mathSource(n, r.source))
+		if err != nil {
+			lines = strings.Split(mathSource(n, r.source), "\n")
+		}
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = "    " + line
+			}
+		}
+		return lines
+	case *ast.FencedCodeBlock, *ast.CodeBlock, *mermaid.Block:
+		var lines []string
+		for i := 0; i < n.Lines().Len(); i++ {
+			segment := n.Lines().At(i)
+			line := strings.TrimRight(string(segment.Value(r.source)), "\r\n")
+			if line == "" {
+				lines = append(lines, "")
+				continue
+			}
+			lines = append(lines, "    "+line)
+		}
+		return lines
+	case *ast.HTMLBlock:
+		var raw strings.Builder
+		for i := 0; i < n.Lines().Len(); i++ {
+			segment := n.Lines().At(i)
+			raw.Write(segment.Value(r.source))
+		}
+		var lines []string
+		for _, line := range strings.Split(html.UnescapeString(stripHTML(raw.String())), "\n") {
+			if line = strings.TrimRight(line, " \t\r"); strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	case *extast.Table:
+		return r.table(n)
+	case *TOC:
+		entries := n.Entries()
+		var lines []string
+		for i, depth := range tocDepths(entries) {
+			lines = append(lines, strings.Repeat("  ", depth)+"- "+entries[i].Text)
+		}
+		return lines
+	case *mermaid.ScriptBlock:
+		return nil
+	}
+	return r.blocks(n, width, false)
+}
+
+// textWidth returns the width left after indenting by n columns
+func textWidth(width, n int) int {
+	return max(textMinWidth, width-n)
+}
+
+// list writes bullets as "-" and numbers as "1.", with item content
+// indented past the marker
+func (r *textRenderer) list(n *ast.List, width int) []string {
+	var lines []string
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		if item != n.FirstChild() && !n.IsTight {
+			lines = append(lines, "")
+		}
+		marker := "-"
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+		indent := strings.Repeat(" ", len(marker)+1)
+
+		content := r.blocks(item, textWidth(width, len(indent)), n.IsTight)
+		if len(content) == 0 {
+			lines = append(lines, marker)
+			continue
+		}
+		for i, line := range content {
+			switch {
+			case i == 0:
+				line = marker + " " + line
+			case line != "":
+				line = indent + line
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// table lays out a table in columns padded to the widest cell, with a
+// dashed line below the header
+func (r *textRenderer) table(n *extast.Table) []string {
+	var rows [][]string
+	var widths []int
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			text := strings.Join(strings.Fields(r.inline(cell)), " ")
+			if len(cells) >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[len(cells)] = max(widths[len(cells)], runewidth.StringWidth(text))
+			cells = append(cells, text)
+		}
+		rows = append(rows, cells)
+	}
+
+	var lines []string
+	for i, cells := range rows {
+		var b strings.Builder
+		for j, cell := range cells {
+			if j > 0 {
+				b.WriteString("  ")
+			}
+			padding := widths[j] - runewidth.StringWidth(cell)
+			align := extast.AlignNone
+			if j < len(n.Alignments) {
+				align = n.Alignments[j]
+			}
+			switch align {
+			case extast.AlignRight:
+				b.WriteString(strings.Repeat(" ", padding) + cell)
+			case extast.AlignCenter:
+				b.WriteString(strings.Repeat(" ", padding/2) + cell + strings.Repeat(" ", padding-padding/2))
+			default:
+				b.WriteString(cell + strings.Repeat(" ", padding))
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+
+		if i == 0 {
+			var dashes []string
+			for _, w := range widths {
+				dashes = append(dashes, strings.Repeat("-", max(w, 1)))
+			}
+			lines = append(lines, strings.Join(dashes, "  "))
+		}
+	}
+	return lines
+}
+
+// inline returns the text of the inline nodes below n. Hard line breaks
+// are kept as newlines.
+func (r *textRenderer) inline(n ast.Node) string {
+	var b strings.Builder
+	r.writeInline(&b, n)
+	return b.String()
+}
+
+func (r *textRenderer) writeInline(b *strings.Builder, n ast.Node) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			value := c.Segment.Value(r.source)
+			if !c.IsRaw() {
+				value = util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+			}
+			b.Write(value)
+			if c.HardLineBreak() {
+				b.WriteByte('\n')
+			} else if c.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(c.Value)
+		case *ast.CodeSpan:
+			var code strings.Builder
+			r.writeInline(&code, c)
+			b.WriteString(strings.ReplaceAll(code.String(), "\n", " "))
+		case *ast.AutoLink:
+			b.Write(c.Label(r.source))
+		case *ast.Link:
+			text := r.inline(c)
+			b.WriteString(text)
+			r.writeRef(b, string(c.Destination), text)
+		case *ast.Image:
+			alt := r.inline(c)
+			if alt == "" {
+				b.WriteString("[image]")
+			} else {
+				b.WriteString("[image: " + alt + "]")
+			}
+			r.writeRef(b, string(c.Destination), "")
+		case *extast.TaskCheckBox:
+			if c.IsChecked {
+				b.WriteString("[x] ")
+			} else {
+				b.WriteString("[ ] ")
+			}
+		case *mathjax.InlineMath:
+			source := mathSource(c, r.source)
+			if text, err := tex.Inline(source); err == nil {
+				source = text
+			}
+			b.WriteString(source)
+		case *ast.RawHTML:
+			// Inline tags have no text of their own
+		default:
+			r.writeInline(b, c)
+		}
+	}
+}
+
+// writeRef writes the reference number of a link target, unless the
+// target is already shown as the text or points within the document
+func (r *textRenderer) writeRef(b *strings.Builder, dest, text string) {
+	if dest == "" || dest == text || strings.HasPrefix(dest, "#") || strings.TrimPrefix(dest, "mailto:") == text {
+		return
+	}
+	num, ok := r.refNums[dest]
+	if !ok {
+		r.refs = append(r.refs, dest)
+		num = len(r.refs)
+		r.refNums[dest] = num
+	}
+	fmt.Fprintf(b, " [%d]", num)
+}
+
+// wrapText wraps text at width on spaces, keeping its line breaks. Words
+// longer than width get a line of their own.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		lineWidth := 0
+		for _, word := range strings.Fields(paragraph) {
+			w := displayWidth(word)
+			if line != "" && lineWidth+1+w > width {
+				lines = append(lines, line)
+				line, lineWidth = "", 0
+			}
+			if line != "" {
+				line += " "
+				lineWidth++
+			}
+			line += word
+			lineWidth += w
+		}
+		lines = append(lines, line)
+	}
+	// A trailing hard break leaves an empty line
+	for len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,27 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/tacheraSasi/mdcli/themes"
+)
+
+// themedHTML wraps an HTML fragment in an element styled by the theme palette
+func themedHTML(body, theme string) string {
+	var b strings.Builder
+	b.WriteString("<style>\n")
+	b.WriteString(themes.CSS(themeColors(theme), ".mdcli-document"))
+	b.WriteString("</style>\n<div class=\"mdcli-document\">\n")
+	b.WriteString(body)
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// themeColors returns the palette of the named theme, falling back to dracula
+func themeColors(name string) themes.ThemeColors {
+	theme, err := themes.GetTheme(name)
+	if err != nil {
+		theme = themes.AvailableThemes["dracula"]
+	}
+	return theme.Colors
+}
@@ -0,0 +1,199 @@
+package renderer
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	gmrenderer "github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Table of contents placeholders: a "[TOC]" paragraph or a "<!-- toc -->"
+// comment, both optionally limiting the levels listed, e.g. "[TOC max=3]"
+// or "<!-- toc min=2 max=3 -->"
+var (
+	tocParagraph = regexp.MustCompile(`^\[(?i:toc)((?:\s+\w+=\d+)*)\s*\]$`)
+	tocComment   = regexp.MustCompile(`^<!--\s*(?i:toc)((?:\s+\w+=\d+)*)\s*-->$`)
+)
+
+// KindTOC is the node kind of TOC
+var KindTOC = ast.NewNodeKind("TOC")
+
+// TOC is a table of contents placeholder, listing the document headings
+// between MinLevel and MaxLevel
+type TOC struct {
+	ast.BaseBlock
+	MinLevel int
+	MaxLevel int
+	Headings []Heading
+}
+
+// Kind implements ast.Node
+func (n *TOC) Kind() ast.NodeKind { return KindTOC }
+
+// Dump implements ast.Node
+func (n *TOC) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"MinLevel": strconv.Itoa(n.MinLevel),
+		"MaxLevel": strconv.Itoa(n.MaxLevel),
+	}, nil)
+}
+
+// Entries returns the headings the table of contents lists
+func (n *TOC) Entries() []Heading {
+	var entries []Heading
+	for _, h := range n.Headings {
+		if h.Level >= n.MinLevel && h.Level <= n.MaxLevel {
+			entries = append(entries, h)
+		}
+	}
+	return entries
+}
+
+// tocExtension replaces TOC placeholders with the document outline
+type tocExtension struct{}
+
+func (tocExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(tocTransformer{}, 100),
+	))
+	m.Renderer().AddOptions(gmrenderer.WithNodeRenderers(
+		util.Prioritized(tocHTMLRenderer{}, 100),
+	))
+}
+
+type tocTransformer struct{}
+
+func (tocTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var placeholders []ast.Node
+	var tocs []*TOC
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		var m []string
+		switch n.(type) {
+		case *ast.Paragraph:
+			m = tocParagraph.FindStringSubmatch(blockText(n, source))
+		case *ast.HTMLBlock:
+			m = tocComment.FindStringSubmatch(blockText(n, source))
+		default:
+			return ast.WalkContinue, nil
+		}
+		if m != nil {
+			toc := &TOC{MinLevel: 1, MaxLevel: 6}
+			parseTOCLimits(toc, m[1])
+			toc.SetLines(n.Lines())
+			placeholders = append(placeholders, n)
+			tocs = append(tocs, toc)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	if len(placeholders) == 0 {
+		return
+	}
+
+	headings := Outline(doc, source)
+	for i, n := range placeholders {
+		tocs[i].Headings = headings
+		n.Parent().ReplaceChild(n.Parent(), n, tocs[i])
+	}
+}
+
+// blockText returns the trimmed source lines of a block
+func blockText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		b.Write(segment.Value(source))
+	}
+	if block, ok := n.(*ast.HTMLBlock); ok && block.HasClosure() {
+		b.Write(block.ClosureLine.Value(source))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseTOCLimits applies the "min=N max=N" options of a placeholder
+func parseTOCLimits(toc *TOC, options string) {
+	for _, field := range strings.Fields(options) {
+		key, value, _ := strings.Cut(field, "=")
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 1 || level > 6 {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "min":
+			toc.MinLevel = level
+		case "max", "depth":
+			toc.MaxLevel = level
+		}
+	}
+}
+
+// tocDepths returns the list nesting depth of every heading: a heading is
+// nested below the closest preceding heading of a lower level
+func tocDepths(headings []Heading) []int {
+	depths := make([]int, len(headings))
+	var stack []int
+	for i, h := range headings {
+		for len(stack) > 0 && stack[len(stack)-1] >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		depths[i] = len(stack)
+		stack = append(stack, h.Level)
+	}
+	return depths
+}
+
+type tocHTMLRenderer struct{}
+
+func (tocHTMLRenderer) RegisterFuncs(reg gmrenderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindTOC, renderTOCHTML)
+}
+
+// renderTOCHTML writes a table of contents as nested lists of anchor links.
+// Items are indented by depth so the text output keeps the structure.
+func renderTOCHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	entries := node.(*TOC).Entries()
+	if len(entries) == 0 {
+		return ast.WalkSkipChildren, nil
+	}
+
+	w.WriteString("<nav class=\"toc\"><ul>\n")
+	depths := tocDepths(entries)
+	for i, h := range entries {
+		depth := depths[i]
+		if i > 0 {
+			if depth > depths[i-1] {
+				w.WriteString("<ul>\n")
+			} else {
+				w.WriteString("</li>")
+				for d := depths[i-1]; d > depth; d-- {
+					w.WriteString("</ul></li>")
+				}
+				w.WriteString("\n")
+			}
+		}
+		fmt.Fprintf(w, "%s<li><a href=\"#%s\">%s</a>",
+			strings.Repeat("  ", depth), html.EscapeString(h.ID), html.EscapeString(h.Text))
+	}
+	w.WriteString("</li>")
+	for d := depths[len(depths)-1]; d > 0; d-- {
+		w.WriteString("</ul></li>")
+	}
+	w.WriteString("</ul></nav>\n")
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Section is the text of a document between two headings
+type Section struct {
+	// Heading is empty for the text before the first heading
+	Heading string
+	// Anchor is the id of the heading element
+	Anchor string
+	Text   string
+}
+
+// inline elements do not separate words
+var inline = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Code: true, atom.Del: true,
+	atom.Em: true, atom.I: true, atom.Kbd: true, atom.Mark: true, atom.S: true,
+	atom.Span: true, atom.Strong: true, atom.Sub: true, atom.Sup: true,
+}
+
+// SectionsFromHTML splits rendered HTML into heading-anchored sections.
+// Style, script and nav elements, such as a table of contents, are skipped.
+func SectionsFromHTML(content string) []Section {
+	var sections []Section
+	current := Section{}
+	var text, heading strings.Builder
+	inHeading := atom.Atom(0)
+	skip := 0
+
+	flush := func() {
+		current.Text = collapseSpace(text.String())
+		if current.Heading != "" || current.Text != "" {
+			sections = append(sections, current)
+		}
+		text.Reset()
+	}
+
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			flush()
+			return sections
+
+		case html.StartTagToken:
+			tok := z.Token()
+			switch tok.DataAtom {
+			case atom.Style, atom.Script, atom.Nav:
+				skip++
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				flush()
+				current = Section{}
+				for _, attr := range tok.Attr {
+					if attr.Key == "id" {
+						current.Anchor = attr.Val
+					}
+				}
+				inHeading = tok.DataAtom
+				heading.Reset()
+			default:
+				// Keep words in adjacent blocks apart
+				if !inline[tok.DataAtom] {
+					text.WriteByte(' ')
+				}
+			}
+
+		case html.EndTagToken:
+			tok := z.Token()
+			switch {
+			case tok.DataAtom == atom.Style || tok.DataAtom == atom.Script || tok.DataAtom == atom.Nav:
+				if skip > 0 {
+					skip--
+				}
+			case inHeading != 0 && tok.DataAtom == inHeading:
+				current.Heading = collapseSpace(heading.String())
+				inHeading = 0
+			default:
+				if !inline[tok.DataAtom] {
+					text.WriteByte(' ')
+				}
+			}
+
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			if inHeading != 0 {
+				heading.Write(z.Text())
+			} else {
+				text.Write(z.Text())
+			}
+		}
+	}
+}
+
+// collapseSpace trims s and replaces runs of whitespace with one space
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
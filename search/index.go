@@ -0,0 +1,500 @@
+// Package search implements an in-memory full-text index of rendered
+// Markdown documents.
+package search
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snippetLength is the approximate length of a result snippet in bytes
+const snippetLength = 160
+
+// maxPrefixTerms limits how many terms a trailing prefix expands to
+const maxPrefixTerms = 50
+
+// Result is a document matching a query
+type Result struct {
+	Path    string  `json:"path"`
+	Title   string  `json:"title"`
+	Heading string  `json:"heading,omitempty"`
+	Anchor  string  `json:"anchor,omitempty"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+	// The HTML fields hold the title, heading and snippet escaped, with
+	// the query terms wrapped in <mark>
+	TitleHTML   string `json:"titleHtml"`
+	HeadingHTML string `json:"headingHtml,omitempty"`
+	SnippetHTML string `json:"snippetHtml"`
+}
+
+// Index maps terms to the positions they occur at in every document. It is
+// safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string][]position
+}
+
+// document is an indexed file
+type document struct {
+	title    string
+	sections []section
+	titles   map[string]bool
+	terms    []string
+}
+
+// section holds a Section with the byte span of every text token. The
+// heading tokens come first, so positions below textStart are heading
+// matches.
+type section struct {
+	Section
+	textStart int
+	spans     [][2]int
+}
+
+// position is a token occurrence within a document
+type position struct {
+	section int
+	offset  int
+}
+
+// New returns an empty index
+func New() *Index {
+	return &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string][]position),
+	}
+}
+
+// Add indexes the sections of the document at path, replacing any previous
+// version of it
+func (ix *Index) Add(path, title string, sections []Section) {
+	doc := &document{title: title, titles: make(map[string]bool)}
+	for _, term := range tokenize(title) {
+		doc.titles[term.text] = true
+	}
+
+	found := make(map[string][]position)
+	for i, s := range sections {
+		sec := section{Section: s}
+		pos := 0
+		for _, term := range tokenize(s.Heading) {
+			found[term.text] = append(found[term.text], position{i, pos})
+			pos++
+		}
+		if pos > 0 {
+			// Leave a gap so phrases do not span the heading and the text
+			pos++
+		}
+		sec.textStart = pos
+		for _, term := range tokenize(s.Text) {
+			found[term.text] = append(found[term.text], position{i, pos})
+			sec.spans = append(sec.spans, [2]int{term.start, term.end})
+			pos++
+		}
+		doc.sections = append(doc.sections, sec)
+	}
+	for term := range found {
+		doc.terms = append(doc.terms, term)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(path)
+	ix.docs[path] = doc
+	for term, positions := range found {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[string][]position)
+		}
+		ix.postings[term][path] = positions
+	}
+}
+
+// Remove drops the document at path from the index
+func (ix *Index) Remove(path string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(path)
+}
+
+func (ix *Index) remove(path string) {
+	doc, ok := ix.docs[path]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms {
+		delete(ix.postings[term], path)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.docs, path)
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// clause is a term or quoted phrase of a query. Every clause must match.
+type clause struct {
+	terms []string
+	// prefix lets the last term match any term starting with it
+	prefix bool
+}
+
+var queryRegex = regexp.MustCompile(`"([^"]*)"?|\S+`)
+
+// parseQuery splits a query into clauses. Words joined by punctuation, such
+// as "go-mod", are matched as a phrase. A trailing bare word is matched as
+// a prefix so results appear while typing.
+func parseQuery(query string) []clause {
+	var clauses []clause
+	for _, m := range queryRegex.FindAllStringSubmatch(query, -1) {
+		text := m[0]
+		if strings.HasPrefix(text, `"`) {
+			text = m[1]
+		}
+		var terms []string
+		for _, t := range tokenize(text) {
+			terms = append(terms, t.text)
+		}
+		if len(terms) > 0 {
+			clauses = append(clauses, clause{terms: terms})
+		}
+	}
+
+	trimmed := strings.TrimRightFunc(query, unicode.IsSpace)
+	if n := len(clauses); n > 0 && trimmed == query && !strings.HasSuffix(query, `"`) {
+		clauses[n-1].prefix = true
+	}
+	return clauses
+}
+
+// Search returns the documents matching query, best first, and the total
+// number of matches. A limit of 0 returns every match.
+func (ix *Index) Search(query string, limit int) ([]Result, int) {
+	clauses := parseQuery(query)
+	if len(clauses) == 0 {
+		return nil, 0
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// Candidates must match every clause
+	var matches []map[string][]position
+	for _, c := range clauses {
+		m := ix.matchClause(c)
+		if len(m) == 0 {
+			return nil, 0
+		}
+		matches = append(matches, m)
+	}
+
+	total := float64(len(ix.docs))
+	var results []Result
+	for path := range matches[0] {
+		doc := ix.docs[path]
+		score := 0.0
+		weights := make([]float64, len(doc.sections))
+		var hits []position
+		ok := true
+
+		for i, c := range clauses {
+			positions, found := matches[i][path]
+			if !found {
+				ok = false
+				break
+			}
+			idf := math.Log(1 + total/float64(len(matches[i])))
+			weight := idf * float64(len(c.terms))
+			score += (1 + math.Log(float64(len(positions)))) * weight
+
+			headingHit := false
+			for _, p := range positions {
+				w := 1.0
+				if p.offset < doc.sections[p.section].textStart {
+					w = 3
+					headingHit = true
+				}
+				weights[p.section] += w * weight
+			}
+			if headingHit {
+				score += 2 * weight
+			}
+			if doc.inTitle(c) {
+				score += 3 * weight
+			}
+			hits = append(hits, positions...)
+		}
+		if !ok {
+			continue
+		}
+
+		best := 0
+		for i, w := range weights {
+			if w > weights[best] {
+				best = i
+			}
+		}
+		sec := doc.sections[best]
+		snippet := sec.snippet(best, hits)
+		// A heading directly followed by a subheading has no text of its own
+		for i := best + 1; snippet == "" && i < len(doc.sections); i++ {
+			snippet = doc.sections[i].snippet(i, nil)
+		}
+		results = append(results, Result{
+			Path:        path,
+			Title:       doc.title,
+			Heading:     sec.Heading,
+			Anchor:      sec.Anchor,
+			Snippet:     snippet,
+			Score:       math.Round(score*1000) / 1000,
+			TitleHTML:   highlight(doc.title, clauses),
+			HeadingHTML: highlight(sec.Heading, clauses),
+			SnippetHTML: highlight(snippet, clauses),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Path < results[j].Path
+	})
+
+	count := len(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, count
+}
+
+// matchClause returns, per document, the positions where c starts
+func (ix *Index) matchClause(c clause) map[string][]position {
+	last := len(c.terms) - 1
+	lastPostings := []map[string][]position{ix.postings[c.terms[last]]}
+	if c.prefix {
+		lastPostings = ix.prefixPostings(c.terms[last])
+	}
+
+	if last == 0 {
+		result := make(map[string][]position)
+		for _, postings := range lastPostings {
+			for path, positions := range postings {
+				result[path] = append(result[path], positions...)
+			}
+		}
+		return result
+	}
+
+	// Phrase: every following term must occur at the next offset
+	result := make(map[string][]position)
+	for path, starts := range ix.postings[c.terms[0]] {
+		for _, start := range starts {
+			if ix.phraseAt(path, start, c.terms[1:last]) && anyAt(lastPostings, path, start, last) {
+				result[path] = append(result[path], start)
+			}
+		}
+	}
+	return result
+}
+
+// prefixPostings returns the postings of every term starting with prefix
+func (ix *Index) prefixPostings(prefix string) []map[string][]position {
+	var terms []string
+	for term := range ix.postings {
+		if strings.HasPrefix(term, prefix) {
+			terms = append(terms, term)
+		}
+	}
+	// Prefer the shortest completions when there are too many
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) < len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > maxPrefixTerms {
+		terms = terms[:maxPrefixTerms]
+	}
+
+	var result []map[string][]position
+	for _, term := range terms {
+		result = append(result, ix.postings[term])
+	}
+	return result
+}
+
+// phraseAt reports whether terms follow the token at start
+func (ix *Index) phraseAt(path string, start position, terms []string) bool {
+	for i, term := range terms {
+		if !contains(ix.postings[term][path], position{start.section, start.offset + i + 1}) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyAt reports whether one of postings has a token distance after start
+func anyAt(postings []map[string][]position, path string, start position, distance int) bool {
+	want := position{start.section, start.offset + distance}
+	for _, p := range postings {
+		if contains(p[path], want) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(positions []position, want position) bool {
+	// Positions are appended in document order
+	i := sort.Search(len(positions), func(i int) bool {
+		p := positions[i]
+		return p.section > want.section || p.section == want.section && p.offset >= want.offset
+	})
+	return i < len(positions) && positions[i] == want
+}
+
+// inTitle reports whether every term of c occurs in the document title
+func (d *document) inTitle(c clause) bool {
+	for i, term := range c.terms {
+		if d.titles[term] {
+			continue
+		}
+		if c.prefix && i == len(c.terms)-1 {
+			for t := range d.titles {
+				if strings.HasPrefix(t, term) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// snippet returns the text around the first hit in the section with index
+// idx, or its beginning when it only matched in the heading
+func (s section) snippet(idx int, hits []position) string {
+	start := 0
+	first := -1
+	for _, h := range hits {
+		if h.section != idx || h.offset < s.textStart {
+			continue
+		}
+		if i := h.offset - s.textStart; first < 0 || i < first {
+			first = i
+		}
+	}
+	if first >= 0 {
+		start = s.spans[first][0] - snippetLength/3
+	}
+	if start < 0 {
+		start = 0
+	}
+	// Begin at a word boundary
+	if start > 0 {
+		if i := strings.IndexByte(s.Text[start:], ' '); i >= 0 && i < snippetLength/3 {
+			start += i + 1
+		}
+	}
+
+	end := start + snippetLength
+	if end >= len(s.Text) {
+		end = len(s.Text)
+	} else if i := strings.LastIndexByte(s.Text[start:end], ' '); i > 0 {
+		end = start + i
+	}
+	for start < end && !utf8.RuneStart(s.Text[start]) {
+		start++
+	}
+	for end < len(s.Text) && !utf8.RuneStart(s.Text[end]) {
+		end++
+	}
+
+	snippet := s.Text[start:end]
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(s.Text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// Highlight escapes text for HTML and wraps the words matching query in
+// <mark>. Words are matched in the raw text, so a term never matches inside
+// an entity such as &amp;.
+func Highlight(text, query string) string {
+	return highlight(text, parseQuery(query))
+}
+
+func highlight(text string, clauses []clause) string {
+	terms := make(map[string]bool)
+	var prefixes []string
+	for _, c := range clauses {
+		last := len(c.terms) - 1
+		for i, term := range c.terms {
+			if c.prefix && i == last {
+				prefixes = append(prefixes, term)
+			} else {
+				terms[term] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	end := 0
+	for _, t := range tokenize(text) {
+		match := terms[t.text]
+		for _, prefix := range prefixes {
+			match = match || strings.HasPrefix(t.text, prefix)
+		}
+		if !match {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[end:t.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[t.start:t.end]))
+		b.WriteString("</mark>")
+		end = t.end
+	}
+	b.WriteString(html.EscapeString(text[end:]))
+	return b.String()
+}
+
+// token is a normalized word and its byte span in the source text
+type token struct {
+	text       string
+	start, end int
+}
+
+// tokenize splits s into lower-cased words of letters and digits
+func tokenize(s string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range s {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if word && start < 0 {
+			start = i
+		} else if !word && start >= 0 {
+			tokens = append(tokens, token{strings.ToLower(s[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(s[start:]), start, len(s)})
+	}
+	return tokens
+}
@@ -0,0 +1,173 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func paths(results []Result) []string {
+	var p []string
+	for _, r := range results {
+		p = append(p, r.Path)
+	}
+	return p
+}
+
+func TestSearchRanking(t *testing.T) {
+	ix := New()
+	ix.Add("body.md", "Notes", []Section{{Text: "Run the installer to install it."}})
+	ix.Add("title.md", "Install", []Section{{Text: "Read this first, then install."}})
+	ix.Add("heading.md", "Guide", []Section{{Heading: "Install", Anchor: "install", Text: "Steps follow."}})
+
+	results, total := ix.Search("install ", 0)
+	if total != 3 {
+		t.Fatalf("Search found %d documents %q, want 3", total, paths(results))
+	}
+	if got := strings.Join(paths(results), " "); got != "title.md heading.md body.md" {
+		t.Errorf("Search ranked %s, want title.md heading.md body.md", got)
+	}
+	if results[1].Anchor != "install" {
+		t.Errorf("heading match anchor = %q, want install", results[1].Anchor)
+	}
+
+	if results, _ := ix.Search("install", 1); len(results) != 1 || results[0].Path != "title.md" {
+		t.Errorf("Search with limit 1 = %q, want [title.md]", paths(results))
+	}
+}
+
+func TestSearchQueries(t *testing.T) {
+	ix := New()
+	ix.Add("phrase.md", "A", []Section{{Text: "Build it from source with go."}})
+	ix.Add("reversed.md", "B", []Section{{Text: "The source is built from scratch."}})
+	ix.Add("split.md", "C", []Section{{Heading: "From", Text: "source code"}})
+	ix.Add("module.md", "D", []Section{{Text: "Run go-mod tidy, then go mod download."}})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"source", "phrase.md reversed.md split.md"},
+		{`"from source"`, "phrase.md"},
+		{`"from source`, "phrase.md"},
+		{"from source ", "phrase.md reversed.md split.md"},
+		{"go-mod", "module.md"},
+		{"sour", "phrase.md reversed.md split.md"},
+		{"sour ", ""},
+		{`"sour"`, ""},
+		{"buil", "phrase.md reversed.md"},
+		{"build sour", "phrase.md"},
+		{"missing source", ""},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		results, _ := ix.Search(tt.query, 0)
+		// Order is covered by TestSearchRanking
+		got := paths(results)
+		sort.Strings(got)
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRemove(t *testing.T) {
+	ix := New()
+	ix.Add("a.md", "A", []Section{{Text: "alpha"}})
+	ix.Add("a.md", "A", []Section{{Text: "beta"}})
+	if results, _ := ix.Search("alpha", 0); len(results) != 0 {
+		t.Errorf("Search found replaced text in %q", paths(results))
+	}
+	ix.Remove("a.md")
+	if results, _ := ix.Search("beta", 0); len(results) != 0 || ix.Len() != 0 {
+		t.Errorf("Search found removed document in %q", paths(results))
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	words := make([]string, 100)
+	for i := range words {
+		words[i] = "filler"
+	}
+	words[50] = "needle"
+	text := strings.Join(words, " ")
+
+	ix := New()
+	ix.Add("long.md", "Long", []Section{{Text: text}})
+	ix.Add("short.md", "Short", []Section{{Heading: "Needle", Text: "Short text."}})
+	ix.Add("empty.md", "Empty", []Section{{Heading: "Needle"}, {Heading: "Child", Text: "Child text."}})
+
+	results, _ := ix.Search("needle", 0)
+	snippets := make(map[string]string)
+	for _, r := range results {
+		snippets[r.Path] = r.Snippet
+	}
+
+	long := snippets["long.md"]
+	if !strings.HasPrefix(long, "…filler ") || !strings.HasSuffix(long, " filler…") {
+		t.Errorf("long snippet %q does not start and end at word boundaries with ellipses", long)
+	}
+	if !strings.Contains(long, "needle") {
+		t.Errorf("long snippet %q does not contain the match", long)
+	}
+	if n := len(strings.Trim(long, "…")); n > snippetLength {
+		t.Errorf("long snippet is %d bytes, want at most %d", n, snippetLength)
+	}
+	if got := snippets["short.md"]; got != "Short text." {
+		t.Errorf("heading match snippet = %q, want the whole section text", got)
+	}
+	if got := snippets["empty.md"]; got != "Child text." {
+		t.Errorf("empty section snippet = %q, want the next section's text", got)
+	}
+}
+
+func TestSnippetRunes(t *testing.T) {
+	text := strings.Repeat("ü", 100) + " needle " + strings.Repeat("ü", 100)
+	s := section{Section: Section{Text: text}, spans: [][2]int{{0, 200}, {201, 207}, {208, 408}}}
+	got := s.snippet(0, []position{{0, 1}})
+	for _, r := range got {
+		if r == '�' {
+			t.Fatalf("snippet %q splits a multi-byte character", got)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		query string
+		want  string
+	}{
+		{"Install guide", "install", "<mark>Install</mark> guide"},
+		{"Install guide", "inst", "<mark>Install</mark> guide"},
+		{"Install guide", "inst ", "Install guide"},
+		{"Build from source", `"from source"`, "Build <mark>from</mark> <mark>source</mark>"},
+		{"Tom & Jerry", "amp", "Tom &amp; Jerry"},
+		{"Tom & Jerry", "tom jerry", "<mark>Tom</mark> &amp; <mark>Jerry</mark>"},
+		{"<b>bold</b>", "b", "&lt;<mark>b</mark>&gt;<mark>bold</mark>&lt;/<mark>b</mark>&gt;"},
+		{`say "lt" <now>`, "lt", `say &#34;<mark>lt</mark>&#34; &lt;now&gt;`},
+		{"Grüße", "grü", "<mark>Grüße</mark>"},
+		{"nothing here", "", "nothing here"},
+	}
+
+	for _, tt := range tests {
+		if got := Highlight(tt.text, tt.query); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchHighlight(t *testing.T) {
+	ix := New()
+	ix.Add("a.md", "Q&A", []Section{{Heading: "<Setup>", Text: "Use a & b to set up."}})
+
+	results, _ := ix.Search("setup", 0)
+	if len(results) != 1 {
+		t.Fatalf("Search found %d documents, want 1", len(results))
+	}
+	r := results[0]
+	if r.TitleHTML != "Q&amp;A" || r.HeadingHTML != "&lt;<mark>Setup</mark>&gt;" || r.SnippetHTML != "Use a &amp; b to set up." {
+		t.Errorf("Search highlights = %q, %q, %q", r.TitleHTML, r.HeadingHTML, r.SnippetHTML)
+	}
+}
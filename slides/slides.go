@@ -0,0 +1,175 @@
+// Package slides splits a Markdown document into the slides of a
+// presentation, with optional speaker notes on each slide.
+package slides
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tacheraSasi/mdcli/renderer"
+)
+
+// Ways of splitting a document into slides
+const (
+	// SplitAuto splits on rules when the document has any, and on
+	// headings otherwise
+	SplitAuto = "auto"
+	// SplitRule starts a slide after every "---" line
+	SplitRule = "rule"
+	// SplitHeading starts a slide at every heading up to Options.Level
+	SplitHeading = "heading"
+)
+
+// Options control how a document is split
+type Options struct {
+	// Split is SplitAuto (the default), SplitRule or SplitHeading
+	Split string
+	// Level is the deepest heading level that starts a slide when
+	// splitting on headings. Zero means 2.
+	Level int
+}
+
+// Slide is one slide of a deck
+type Slide struct {
+	// Markdown is the content shown on the slide
+	Markdown string
+	// Notes are the speaker notes, in Markdown
+	Notes string
+	// Line is the line of the input the slide starts at, 1-based
+	Line int
+}
+
+// Deck is a document split into slides
+type Deck struct {
+	FrontMatter renderer.FrontMatter
+	Slides      []Slide
+}
+
+var (
+	fenceOpen = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	heading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]|$)`)
+	notesLine = regexp.MustCompile(`(?i)^notes?:[ \t]*(.*)$`)
+)
+
+// Parse splits a Markdown document into slides.
+//
+// A "---" line after a blank line separates slides; right below text it
+// stays a heading underline. A line starting with "Note:" or "Notes:"
+// turns the rest of its slide into speaker notes. Code blocks and math
+// are never split.
+func Parse(input string, opts Options) (*Deck, error) {
+	if opts.Level == 0 {
+		opts.Level = 2
+	}
+	if opts.Level < 1 || opts.Level > 6 {
+		return nil, fmt.Errorf("heading level %d is not between 1 and 6", opts.Level)
+	}
+
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	fm, body := renderer.ParseFrontMatter(input)
+	first := 1 + strings.Count(input[:len(input)-len(body)], "\n")
+	lines := strings.Split(body, "\n")
+
+	deck := &Deck{FrontMatter: fm}
+	switch opts.Split {
+	case SplitRule:
+		deck.Slides = split(lines, first, 0)
+	case SplitHeading:
+		deck.Slides = split(lines, first, opts.Level)
+	case SplitAuto, "":
+		deck.Slides = split(lines, first, 0)
+		if len(deck.Slides) <= 1 {
+			deck.Slides = split(lines, first, opts.Level)
+		}
+	default:
+		return nil, fmt.Errorf("unknown split mode %q (use auto, rule or heading)", opts.Split)
+	}
+	return deck, nil
+}
+
+// split cuts lines into slides at "---" rules, or when level is set, at
+// headings of that level or above
+func split(lines []string, first, level int) []Slide {
+	var slides []Slide
+	var content, notes []string
+	start := first
+	inNotes := false
+
+	flush := func(next int) {
+		s := Slide{Markdown: trimLines(content), Notes: trimLines(notes)}
+		for i, line := range content {
+			if strings.TrimSpace(line) != "" {
+				start += i
+				break
+			}
+		}
+		s.Line = start
+		if s.Markdown != "" || s.Notes != "" {
+			slides = append(slides, s)
+		}
+		content, notes, inNotes, start = nil, nil, false, next
+	}
+
+	fence := ""
+	inMath := false
+	for i, line := range lines {
+		lineNo := first + i
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		case inMath:
+			inMath = trimmed != "$$"
+		case fenceOpen.MatchString(line):
+			fence = fenceOpen.FindStringSubmatch(line)[1]
+		case trimmed == "$$":
+			inMath = true
+		case level == 0 && strings.TrimRight(line, " \t") == "---" && (i == 0 || strings.TrimSpace(lines[i-1]) == ""):
+			flush(lineNo + 1)
+			continue
+		case level > 0:
+			if m := heading.FindStringSubmatch(line); m != nil && len(m[1]) <= level && (inNotes || hasText(content)) {
+				flush(lineNo)
+			}
+		}
+
+		if fence == "" && !inMath && !inNotes {
+			if m := notesLine.FindStringSubmatch(line); m != nil {
+				inNotes = true
+				line = m[1]
+			}
+		}
+		if inNotes {
+			notes = append(notes, line)
+		} else {
+			content = append(content, line)
+		}
+	}
+	flush(0)
+	return slides
+}
+
+// hasText reports whether any of lines is not blank
+func hasText(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// trimLines joins lines, dropping blank lines at both ends
+func trimLines(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,527 @@
+package tex
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// box is lines of text of equal width, with the index of the baseline
+// the rest of a row lines up with
+type box struct {
+	lines []string
+	base  int
+}
+
+func oneLine(s string) box {
+	return box{lines: []string{s}}
+}
+
+// newBox pads lines to the width of the longest
+func newBox(lines []string, base int) box {
+	w := 0
+	for _, line := range lines {
+		w = max(w, runewidth.StringWidth(line))
+	}
+	for i, line := range lines {
+		lines[i] = line + strings.Repeat(" ", w-runewidth.StringWidth(line))
+	}
+	return box{lines: lines, base: base}
+}
+
+func (b box) width() int {
+	if len(b.lines) == 0 {
+		return 0
+	}
+	return runewidth.StringWidth(b.lines[0])
+}
+
+// hcat sets boxes side by side on a shared baseline
+func hcat(boxes ...box) box {
+	above, below := 0, 0
+	for _, b := range boxes {
+		above = max(above, b.base)
+		below = max(below, len(b.lines)-1-b.base)
+	}
+	builders := make([]strings.Builder, above+below+1)
+	for _, b := range boxes {
+		blank := strings.Repeat(" ", b.width())
+		for i := range builders {
+			if j := i - above + b.base; j >= 0 && j < len(b.lines) {
+				builders[i].WriteString(b.lines[j])
+			} else {
+				builders[i].WriteString(blank)
+			}
+		}
+	}
+	lines := make([]string, len(builders))
+	for i := range builders {
+		lines[i] = builders[i].String()
+	}
+	return box{lines: lines, base: above}
+}
+
+// stack sets boxes one above the other, centered, with the baseline on
+// line base
+func stack(boxes []box, base int) box {
+	w := 0
+	for _, b := range boxes {
+		w = max(w, b.width())
+	}
+	var lines []string
+	for _, b := range boxes {
+		for _, line := range b.lines {
+			left := (w - b.width()) / 2
+			lines = append(lines, strings.Repeat(" ", left)+line+strings.Repeat(" ", w-b.width()-left))
+		}
+	}
+	return box{lines: lines, base: base}
+}
+
+// align pads b to width w, to the right, the left or both sides
+func align(b box, w int, how byte) box {
+	extra := w - b.width()
+	left := 0
+	switch how {
+	case 'r':
+		left = extra
+	case 'c':
+		left = extra / 2
+	}
+	lines := make([]string, len(b.lines))
+	for i, line := range b.lines {
+		lines[i] = strings.Repeat(" ", left) + line + strings.Repeat(" ", extra-left)
+	}
+	return box{lines: lines, base: b.base}
+}
+
+// layout turns parsed nodes into boxes
+type layout struct {
+	// flat keeps everything on one line, as inline math does
+	flat bool
+	// tight leaves out the spaces around operators, as in scripts
+	tight bool
+	// font is the alphabet letters and digits are set in
+	font string
+}
+
+// small is the layout for scripts and inline fractions
+func (l layout) small() layout {
+	return layout{flat: true, tight: true, font: l.font}
+}
+
+// line lays n out flat and returns its only line
+func (l layout) line(n node) string {
+	l.flat = true
+	return l.box(n).lines[0]
+}
+
+func (l layout) box(n node) box {
+	switch n := n.(type) {
+	case row:
+		return l.row(n)
+	case *symbol:
+		if l.font == "" || (n.kind != kindOrd && n.kind != kindNumber) {
+			return oneLine(n.text)
+		}
+		var b strings.Builder
+		for _, r := range n.text {
+			b.WriteRune(styleRune(l.font, r))
+		}
+		return oneLine(b.String())
+	case *script:
+		return l.script(n)
+	case *fraction:
+		return l.fraction(n)
+	case *radical:
+		return l.radical(n)
+	case *fenced:
+		return l.fenced(n)
+	case *matrix:
+		return l.matrix(n)
+	case *accent:
+		return oneLine(l.accent(n))
+	case *styled:
+		l.font = n.font
+		return l.box(n.body)
+	case *space:
+		return oneLine(strings.Repeat(" ", n.width))
+	case *text:
+		return oneLine(n.text)
+	}
+	return oneLine("")
+}
+
+// row sets nodes side by side, spaced around operators and relations
+func (l layout) row(r row) box {
+	boxes := make([]box, 0, 2*len(r))
+	for i, n := range r {
+		if i > 0 && spaced(r, i, l.tight) {
+			boxes = append(boxes, oneLine(" "))
+		}
+		boxes = append(boxes, l.box(n))
+	}
+	if len(boxes) == 0 {
+		return oneLine("")
+	}
+	return hcat(boxes...)
+}
+
+// class is the kind of symbol a node is spaced as
+func class(n node) symbolKind {
+	switch n := n.(type) {
+	case *symbol:
+		return n.kind
+	case *script:
+		return class(n.base)
+	case *styled:
+		return class(n.body)
+	}
+	return kindOrd
+}
+
+// spaced reports whether a space goes between r[i-1] and r[i]. Tight
+// rows only keep the space that sets a function name apart.
+func spaced(r row, i int, tight bool) bool {
+	_, before := r[i-1].(*space)
+	_, after := r[i].(*space)
+	if before || after {
+		return false
+	}
+	prev, next := class(r[i-1]), class(r[i])
+	switch {
+	case tight && prev == kindFunction:
+		return next == kindOrd || next == kindNumber
+	case tight:
+		return false
+	case prev == kindRel || next == kindRel:
+		return true
+	case next == kindOp:
+		return binary(r, i)
+	case prev == kindOp:
+		return binary(r, i-1)
+	case prev == kindPunct || prev == kindLarge:
+		return next != kindPunct && next != kindClose
+	case prev == kindFunction:
+		return next == kindOrd || next == kindNumber || next == kindFunction || next == kindLarge
+	case next == kindFunction:
+		return prev == kindOrd || prev == kindNumber || prev == kindClose
+	}
+	return false
+}
+
+// binary reports whether the operator r[i] stands between two operands,
+// rather than being a sign such as the minus in -x
+func binary(r row, i int) bool {
+	if i == 0 || i == len(r)-1 {
+		return false
+	}
+	prev := class(r[i-1])
+	return prev == kindOrd || prev == kindNumber || prev == kindClose
+}
+
+// script writes scripts as Unicode superscripts and subscripts when it
+// can. Otherwise they are stacked beside the base, or written with ^ and
+// _ in flat layouts.
+func (l layout) script(s *script) box {
+	base := l.box(s.base)
+	sub, sup := "", ""
+	if s.sub != nil {
+		sub = l.small().line(s.sub)
+	}
+	if s.sup != nil {
+		sup = l.small().line(s.sup)
+	}
+
+	if b, ok := s.base.(*symbol); ok && b.limits && !l.flat {
+		boxes := []box{base}
+		offset := 0
+		if sup != "" {
+			boxes = append([]box{oneLine(sup)}, boxes...)
+			offset = 1
+		}
+		if sub != "" {
+			boxes = append(boxes, oneLine(sub))
+		}
+		return stack(boxes, offset+base.base)
+	}
+
+	lowered, subOK := convert(sub, subscripts)
+	raised, supOK := convert(sup, superscripts)
+	switch {
+	case subOK && supOK:
+		return hcat(base, oneLine(lowered+raised))
+	case l.flat:
+		s := base.lines[0]
+		if sub != "" {
+			s += "_" + group(sub)
+		}
+		if sup != "" {
+			s += "^" + group(sup)
+		}
+		return oneLine(s)
+	}
+
+	var lines []string
+	offset := 0
+	if sup != "" {
+		lines = append(lines, sup)
+		offset = 1
+	}
+	for range base.lines {
+		lines = append(lines, "")
+	}
+	if sub != "" {
+		lines = append(lines, sub)
+	}
+	return hcat(base, newBox(lines, offset+base.base))
+}
+
+// convert maps every character of s through table, and reports whether
+// they all had a match
+func convert(s string, table map[rune]rune) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := table[r]
+		if !ok {
+			return "", false
+		}
+		b.WriteRune(c)
+	}
+	return b.String(), true
+}
+
+// group puts parentheses around s when it is more than one character
+func group(s string) string {
+	if utf8.RuneCountInString(s) > 1 {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// operand puts parentheses around s unless it is a single number or a
+// run of letters, digits and primes
+func operand(s string) string {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '.' && r != '′' {
+			return group(s)
+		}
+	}
+	return s
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// fraction stacks the numerator over a rule and the denominator. Flat
+// layouts use a fraction character when there is one, or a slash.
+func (l layout) fraction(f *fraction) box {
+	if l.flat {
+		num, den := l.line(f.num), l.line(f.den)
+		if !f.line {
+			return oneLine(num + "," + den)
+		}
+		if v, ok := fractions[num+"/"+den]; ok {
+			return oneLine(v)
+		}
+		if isDigits(num) && isDigits(den) {
+			raised, _ := convert(num, superscripts)
+			lowered, _ := convert(den, subscripts)
+			return oneLine(raised + "⁄" + lowered)
+		}
+		return oneLine(operand(num) + "/" + operand(den))
+	}
+
+	num, den := l.box(f.num), l.box(f.den)
+	if !f.line {
+		return stack([]box{num, den}, len(num.lines)-1)
+	}
+	rule := oneLine(strings.Repeat("─", max(num.width(), den.width())+2))
+	return stack([]box{num, rule, den}, len(num.lines))
+}
+
+// radical writes a root sign before its body, with a bar over the body
+// in block layouts
+func (l layout) radical(r *radical) box {
+	sign := "√"
+	if r.index != nil {
+		index := l.small().line(r.index)
+		switch raised, ok := convert(index, superscripts); {
+		case index == "3":
+			sign = "∛"
+		case index == "4":
+			sign = "∜"
+		case ok:
+			sign = raised + "√"
+		default:
+			sign = "(" + index + ")√"
+		}
+	}
+
+	body := l.box(r.body)
+	if l.flat {
+		return oneLine(sign + group(body.lines[0]))
+	}
+	pad := strings.Repeat(" ", runewidth.StringWidth(sign)-1)
+	lines := []string{pad + " " + strings.Repeat("_", body.width())}
+	for i, line := range body.lines {
+		if i == len(body.lines)-1 {
+			lines = append(lines, sign+line)
+		} else {
+			lines = append(lines, pad+"│"+line)
+		}
+	}
+	return box{lines: lines, base: body.base + 1}
+}
+
+// tallDelimiters are the pieces delimiters are built from when they span
+// several lines: top, filler, middle and bottom
+var tallDelimiters = map[string][4]string{
+	"(": {"⎛", "⎜", "⎜", "⎝"}, ")": {"⎞", "⎟", "⎟", "⎠"},
+	"[": {"⎡", "⎢", "⎢", "⎣"}, "]": {"⎤", "⎥", "⎥", "⎦"},
+	"{": {"⎧", "⎪", "⎨", "⎩"}, "}": {"⎫", "⎪", "⎬", "⎭"},
+	"⌊": {"⎢", "⎢", "⎢", "⎣"}, "⌋": {"⎥", "⎥", "⎥", "⎦"},
+	"⌈": {"⎡", "⎢", "⎢", "⎢"}, "⌉": {"⎤", "⎥", "⎥", "⎥"},
+	"|": {"│", "│", "│", "│"}, "‖": {"‖", "‖", "‖", "‖"},
+}
+
+// tall returns delimiter d stretched over height lines. Delimiters with
+// no tall form sit on the baseline.
+func tall(d string, height, base int) box {
+	lines := make([]string, height)
+	pieces, ok := tallDelimiters[d]
+	for i := range lines {
+		switch {
+		case !ok && i == base:
+			lines[i] = d
+		case !ok:
+			lines[i] = ""
+		case i == 0:
+			lines[i] = pieces[0]
+		case i == height-1:
+			lines[i] = pieces[3]
+		case i == height/2:
+			lines[i] = pieces[2]
+		default:
+			lines[i] = pieces[1]
+		}
+	}
+	return newBox(lines, base)
+}
+
+// fenced sets a body between delimiters as tall as it is
+func (l layout) fenced(f *fenced) box {
+	if binom, ok := f.body.(*fraction); ok && !binom.line && l.flat {
+		return oneLine("C(" + l.small().line(binom.num) + "," + l.small().line(binom.den) + ")")
+	}
+	body := l.box(f.body)
+	if _, ok := f.body.(*matrix); ok && !l.flat {
+		body = hcat(oneLine(" "), body, oneLine(" "))
+	}
+	if len(body.lines) == 1 {
+		return hcat(oneLine(f.open), body, oneLine(f.close))
+	}
+	height := len(body.lines)
+	return hcat(tall(f.open, height, body.base), body, tall(f.close, height, body.base))
+}
+
+// matrix lines cells up in columns. Flat layouts separate cells with
+// commas and rows with semicolons.
+func (l layout) matrix(m *matrix) box {
+	l.tight = false
+	separator := ", "
+	if m.kind == "aligned" {
+		separator = " "
+	}
+	if l.flat {
+		rows := make([]string, len(m.cells))
+		for i, cells := range m.cells {
+			parts := make([]string, len(cells))
+			for j, cell := range cells {
+				parts[j] = l.line(cell)
+			}
+			rows[i] = strings.TrimSpace(strings.Join(parts, separator))
+		}
+		return oneLine(strings.Join(rows, "; "))
+	}
+
+	boxes := make([][]box, len(m.cells))
+	var widths []int
+	tallRows := false
+	for i, cells := range m.cells {
+		for j, cell := range cells {
+			b := l.box(cell)
+			boxes[i] = append(boxes[i], b)
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], b.width())
+			tallRows = tallRows || len(b.lines) > 1
+		}
+	}
+
+	var lines []string
+	for i, cells := range boxes {
+		if i > 0 && tallRows {
+			lines = append(lines, "")
+		}
+		var parts []box
+		for j, w := range widths {
+			b := oneLine("")
+			if j < len(cells) {
+				b = cells[j]
+			}
+			if j > 0 {
+				parts = append(parts, oneLine(strings.Repeat(" ", columnGap(m.kind, j))))
+			}
+			parts = append(parts, align(b, w, columnAlign(m.kind, j)))
+		}
+		lines = append(lines, hcat(parts...).lines...)
+	}
+	return box{lines: lines, base: (len(lines) - 1) / 2}
+}
+
+// columnAlign returns how cells in column j are aligned. Aligned
+// environments pair a right-aligned column with a left-aligned one.
+func columnAlign(kind string, j int) byte {
+	switch {
+	case kind == "cases", kind == "aligned" && j%2 == 1:
+		return 'l'
+	case kind == "aligned":
+		return 'r'
+	}
+	return 'c'
+}
+
+// columnGap returns the spaces before column j
+func columnGap(kind string, j int) int {
+	switch {
+	case kind == "aligned" && j%2 == 1:
+		return 1
+	case kind == "aligned":
+		return 3
+	}
+	return 2
+}
+
+// accent puts the accent's combining mark after every character of its
+// body
+func (l layout) accent(a *accent) string {
+	mark := accents[a.name]
+	var b strings.Builder
+	for _, r := range l.small().line(a.body) {
+		b.WriteRune(r)
+		if r != ' ' {
+			b.WriteRune(mark)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,224 @@
+package tex
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// accentMarks are the spacing forms of accents, set over or under the
+// body in MathML
+var accentMarks = map[string]string{
+	"hat": "^", "widehat": "^", "bar": "¯", "overline": "¯", "vec": "→", "dot": "˙", "ddot": "¨",
+	"tilde": "~", "widetilde": "~", "acute": "´", "grave": "`", "breve": "˘", "check": "ˇ",
+	"underline": "_",
+}
+
+// spaceWidths maps space widths in columns to their width in MathML
+var spaceWidths = map[int]string{0: "-0.1667em", 1: "0.2222em", 2: "1em", 4: "2em"}
+
+// ordOperators are the ordinary symbols MathML sets as operators
+const ordOperators = "|/!.?′…⋯⋮⋱¬‖"
+
+// columnAligns maps matrix kinds to the alignment of their columns
+var columnAligns = map[string]string{"cases": "left", "aligned": "right left", "matrix": "center", "gathered": "center"}
+
+// MathML converts math to a MathML element that browsers show without
+// scripts. Display math is set as a block with limits above and below.
+func MathML(source string, display bool) (string, error) {
+	n, err := parse(source)
+	if err != nil {
+		return "", err
+	}
+	w := &mathMLWriter{display: display}
+	if display {
+		w.b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML" display="block">`)
+	} else {
+		w.b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML">`)
+	}
+	w.node(n)
+	w.b.WriteString("</math>")
+	return w.b.String(), nil
+}
+
+type mathMLWriter struct {
+	b       strings.Builder
+	display bool
+	// font is the alphabet letters and digits are set in
+	font string
+}
+
+// element writes an element with the given attributes around children,
+// each of which becomes a single child element
+func (w *mathMLWriter) element(name, attrs string, children ...node) {
+	w.b.WriteString("<" + name + attrs + ">")
+	for _, c := range children {
+		w.group(c)
+	}
+	w.b.WriteString("</" + name + ">")
+}
+
+// group writes n as a single element, wrapping rows in mrow
+func (w *mathMLWriter) group(n node) {
+	if r, ok := n.(row); ok && len(r) != 1 {
+		w.b.WriteString("<mrow>")
+		w.row(r)
+		w.b.WriteString("</mrow>")
+		return
+	}
+	w.node(n)
+}
+
+func (w *mathMLWriter) node(n node) {
+	switch n := n.(type) {
+	case row:
+		w.row(n)
+	case *symbol:
+		w.symbol(n)
+	case *script:
+		w.script(n)
+	case *fraction:
+		attrs := ""
+		if !n.line {
+			attrs = ` linethickness="0"`
+		}
+		w.element("mfrac", attrs, n.num, n.den)
+	case *radical:
+		if n.index == nil {
+			w.element("msqrt", "", n.body)
+		} else {
+			w.element("mroot", "", n.body, n.index)
+		}
+	case *fenced:
+		w.b.WriteString("<mrow>")
+		w.fence(n.open)
+		w.group(n.body)
+		w.fence(n.close)
+		w.b.WriteString("</mrow>")
+	case *matrix:
+		w.matrix(n)
+	case *accent:
+		mark := "<mo>" + html.EscapeString(accentMarks[n.name]) + "</mo>"
+		if n.name == "underline" {
+			w.b.WriteString(`<munder accentunder="true">`)
+			w.group(n.body)
+			w.b.WriteString(mark + "</munder>")
+			return
+		}
+		w.b.WriteString(`<mover accent="true">`)
+		w.group(n.body)
+		w.b.WriteString(mark + "</mover>")
+	case *styled:
+		font := w.font
+		w.font = n.font
+		w.group(n.body)
+		w.font = font
+	case *space:
+		w.b.WriteString(`<mspace width="` + spaceWidths[n.width] + `"/>`)
+	case *text:
+		w.b.WriteString("<mtext>" + html.EscapeString(n.text) + "</mtext>")
+	}
+}
+
+// row writes nodes one after another, joining runs of digits into one
+// number
+func (w *mathMLWriter) row(r row) {
+	for i := 0; i < len(r); i++ {
+		s, ok := r[i].(*symbol)
+		if !ok || s.kind != kindNumber {
+			w.node(r[i])
+			continue
+		}
+		number := s.text
+		for i+1 < len(r) {
+			next, ok := r[i+1].(*symbol)
+			if !ok || (next.kind != kindNumber && !(next.text == "." && i+2 < len(r) && isNumber(r[i+2]))) {
+				break
+			}
+			number += next.text
+			i++
+		}
+		w.symbol(&symbol{text: number, kind: kindNumber})
+	}
+}
+
+func isNumber(n node) bool {
+	s, ok := n.(*symbol)
+	return ok && s.kind == kindNumber
+}
+
+func (w *mathMLWriter) symbol(s *symbol) {
+	text := s.text
+	if s.kind == kindOrd || s.kind == kindNumber {
+		text = strings.Map(func(r rune) rune { return styleRune(w.font, r) }, text)
+	}
+	text = html.EscapeString(text)
+	switch s.kind {
+	case kindNumber:
+		w.b.WriteString("<mn>" + text + "</mn>")
+	case kindOrd:
+		if strings.Contains(ordOperators, s.text) {
+			w.b.WriteString("<mo>" + text + "</mo>")
+		} else if w.font == "mathrm" {
+			w.b.WriteString(`<mi mathvariant="normal">` + text + "</mi>")
+		} else {
+			w.b.WriteString("<mi>" + text + "</mi>")
+		}
+	case kindFunction:
+		if utf8.RuneCountInString(s.text) == 1 {
+			w.b.WriteString(`<mi mathvariant="normal">` + text + "</mi>")
+		} else {
+			w.b.WriteString("<mi>" + text + "</mi>")
+		}
+	case kindOpen, kindClose:
+		w.b.WriteString(`<mo stretchy="false">` + text + "</mo>")
+	case kindLarge:
+		w.b.WriteString(`<mo largeop="true">` + text + "</mo>")
+	default:
+		w.b.WriteString("<mo>" + text + "</mo>")
+	}
+}
+
+// script writes scripts beside the base, or above and below it for
+// operators with limits in display math
+func (w *mathMLWriter) script(s *script) {
+	base := s.base
+	if b, ok := base.(*symbol); ok && b.limits && w.display {
+		switch {
+		case s.sub != nil && s.sup != nil:
+			w.element("munderover", "", base, s.sub, s.sup)
+		case s.sub != nil:
+			w.element("munder", "", base, s.sub)
+		default:
+			w.element("mover", "", base, s.sup)
+		}
+		return
+	}
+	switch {
+	case s.sub != nil && s.sup != nil:
+		w.element("msubsup", "", base, s.sub, s.sup)
+	case s.sub != nil:
+		w.element("msub", "", base, s.sub)
+	default:
+		w.element("msup", "", base, s.sup)
+	}
+}
+
+// fence writes a delimiter that stretches with the body, if there is one
+func (w *mathMLWriter) fence(d string) {
+	if d != "" {
+		w.b.WriteString(`<mo fence="true" stretchy="true">` + html.EscapeString(d) + "</mo>")
+	}
+}
+
+func (w *mathMLWriter) matrix(m *matrix) {
+	w.b.WriteString(`<mtable columnalign="` + columnAligns[m.kind] + `">`)
+	for _, cells := range m.cells {
+		w.b.WriteString("<mtr>")
+		for _, cell := range cells {
+			w.element("mtd", "", cell)
+		}
+		w.b.WriteString("</mtr>")
+	}
+	w.b.WriteString("</mtable>")
+}
@@ -0,0 +1,526 @@
+package tex
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// node is one part of a parsed expression
+type node interface{}
+
+// row is a list of nodes set one after the other. Braced groups are rows
+// of their own.
+type row []node
+
+// symbol is a single character, operator or function name
+type symbol struct {
+	text string
+	kind symbolKind
+	// limits is set for operators whose scripts go above and below
+	limits bool
+}
+
+// script is a base with a subscript, a superscript or both
+type script struct {
+	base, sub, sup node
+}
+
+// fraction is a numerator over a denominator. Binomials have no line.
+type fraction struct {
+	num, den node
+	line     bool
+}
+
+// radical is a square root, or a root of another index
+type radical struct {
+	index, body node
+}
+
+// fenced is a body between delimiters that grow with it. Either delimiter
+// may be empty.
+type fenced struct {
+	open, close string
+	body        node
+}
+
+// matrix is a grid of cells from an environment such as pmatrix or
+// aligned. The kind decides how cells are aligned.
+type matrix struct {
+	kind  string
+	cells [][]node
+}
+
+// accent is a mark over or under its body
+type accent struct {
+	name string
+	body node
+}
+
+// styled is a body set in another alphabet, such as \mathbb
+type styled struct {
+	font string
+	body node
+}
+
+// space is horizontal space of a width in columns
+type space struct {
+	width int
+}
+
+// text is upright text from \text and its relatives
+type text struct {
+	text string
+}
+
+// environments maps supported environments to their kind of matrix and
+// the delimiters around them
+var environments = map[string]struct {
+	kind, open, close string
+}{
+	"matrix": {"matrix", "", ""}, "smallmatrix": {"matrix", "", ""}, "array": {"matrix", "", ""},
+	"pmatrix": {"matrix", "(", ")"}, "bmatrix": {"matrix", "[", "]"}, "Bmatrix": {"matrix", "{", "}"},
+	"vmatrix": {"matrix", "|", "|"}, "Vmatrix": {"matrix", "‖", "‖"},
+	"cases":   {"cases", "{", ""},
+	"aligned": {"aligned", "", ""}, "align": {"aligned", "", ""}, "align*": {"aligned", "", ""},
+	"split": {"aligned", "", ""}, "alignat": {"aligned", "", ""},
+	"gathered": {"gathered", "", ""}, "gather": {"gathered", "", ""}, "gather*": {"gathered", "", ""},
+	"equation": {"gathered", "", ""}, "equation*": {"gathered", "", ""},
+}
+
+// Error is TeX that cannot be converted, at a byte offset in the source
+type Error struct {
+	Offset  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Message, e.Offset)
+}
+
+type parser struct {
+	src string
+	pos int
+	// end is where the token that ended the last row starts
+	end int
+}
+
+// parse reads an expression. Top-level rows split by \\ or & become a
+// matrix of their own.
+func parse(src string) (node, error) {
+	p := &parser{src: src}
+	cells, err := p.cells("")
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 1 && len(cells[0]) == 1 {
+		return cells[0][0], nil
+	}
+	kind := "gathered"
+	for _, r := range cells {
+		if len(r) > 1 {
+			kind = "aligned"
+		}
+	}
+	return &matrix{kind: kind, cells: cells}, nil
+}
+
+func (p *parser) errorf(offset int, format string, args ...any) error {
+	return &Error{Offset: offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// skipSpace moves past spaces and comments, which math mode ignores
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		case '%':
+			if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+				p.pos += i + 1
+			} else {
+				p.pos = len(p.src)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next returns the next token: a command with its backslash, or a single
+// character
+func (p *parser) next() string {
+	start := p.pos
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	if r != '\\' || p.pos >= len(p.src) {
+		return p.src[start:p.pos]
+	}
+	if !isLetter(p.src[p.pos]) {
+		_, size = utf8.DecodeRuneInString(p.src[p.pos:])
+		p.pos += size
+		return p.src[start:p.pos]
+	}
+	for p.pos < len(p.src) && isLetter(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// cells reads rows of cells split by & and \\ until the given end: "" for
+// the end of the source, or \end for an environment
+func (p *parser) cells(until string) ([][]node, error) {
+	var cells [][]node
+	var current []node
+	for {
+		cell, end, err := p.row()
+		if err != nil {
+			return nil, err
+		}
+		current = append(current, cell)
+		switch end {
+		case "&":
+		case `\\`:
+			cells = append(cells, current)
+			current = nil
+		case until:
+			// A \\ after the last row leaves an empty one behind
+			if len(current) > 1 || len(cell) > 0 || len(cells) == 0 {
+				cells = append(cells, current)
+			}
+			return cells, nil
+		default:
+			return nil, p.unexpected(end)
+		}
+	}
+}
+
+func (p *parser) unexpected(end string) error {
+	if end == "" {
+		return p.errorf(p.end, "unexpected end of input")
+	}
+	return p.errorf(p.end, "unexpected %s", end)
+}
+
+// row reads nodes up to the token that ends the row, which it returns:
+// "" at the end of the source, or one of } & \\ \right \end
+func (p *parser) row() (row, string, error) {
+	var r row
+	for {
+		p.skipSpace()
+		start := p.pos
+		if p.pos >= len(p.src) {
+			p.end = start
+			return r, "", nil
+		}
+		token := p.next()
+		switch token {
+		case "}", "&", `\\`, `\right`, `\end`:
+			p.end = start
+			return r, token, nil
+		case "^", "_":
+			arg, err := p.argument()
+			if err != nil {
+				return nil, "", err
+			}
+			var base node = row{}
+			if len(r) > 0 {
+				base, r = r[len(r)-1], r[:len(r)-1]
+			}
+			s, ok := base.(*script)
+			if !ok {
+				s = &script{base: base}
+			}
+			if token == "^" && s.sup == nil {
+				s.sup = arg
+			} else if token == "_" && s.sub == nil {
+				s.sub = arg
+			} else if token == "^" {
+				return nil, "", p.errorf(start, "double superscript")
+			} else {
+				return nil, "", p.errorf(start, "double subscript")
+			}
+			r = append(r, s)
+		case "'":
+			prime := &symbol{text: "′", kind: kindOrd}
+			var base node = row{}
+			if len(r) > 0 {
+				base, r = r[len(r)-1], r[:len(r)-1]
+			}
+			s, ok := base.(*script)
+			if !ok {
+				s = &script{base: base}
+			}
+			// Primes join the superscript row rather than nesting, so a run
+			// of them stays flat
+			switch sup := s.sup.(type) {
+			case nil:
+				s.sup = row{prime}
+			case row:
+				s.sup = append(sup, prime)
+			default:
+				s.sup = row{sup, prime}
+			}
+			r = append(r, s)
+		default:
+			n, err := p.atom(token, start)
+			if err != nil {
+				return nil, "", err
+			}
+			if n != nil {
+				r = append(r, n)
+			}
+		}
+	}
+}
+
+// argument reads the argument of a command or script: a braced group or
+// a single token
+func (p *parser) argument() (node, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.pos >= len(p.src) {
+		return nil, p.errorf(start, "missing argument")
+	}
+	token := p.next()
+	switch token {
+	case "}", "&", `\\`, `\right`, `\end`, "^", "_":
+		return nil, p.errorf(start, "missing argument")
+	}
+	n, err := p.atom(token, start)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return row{}, nil
+	}
+	return n, nil
+}
+
+// braced reads a braced group as it is written, for text and names
+func (p *parser) braced() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		return "", p.errorf(p.pos, "missing {")
+	}
+	depth := 0
+	for i := p.pos; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				s := p.src[p.pos+1 : i]
+				p.pos = i + 1
+				return s, nil
+			}
+		}
+	}
+	return "", p.errorf(p.pos, "missing }")
+}
+
+// atom reads the node that starts with token, which began at start. It
+// returns nil for tokens that produce nothing.
+func (p *parser) atom(token string, start int) (node, error) {
+	if token == "{" {
+		r, end, err := p.row()
+		if err != nil {
+			return nil, err
+		}
+		if end != "}" {
+			return nil, p.errorf(start, "missing }")
+		}
+		return r, nil
+	}
+	if !strings.HasPrefix(token, `\`) || token == `\` {
+		return character(token), nil
+	}
+
+	name := token[1:]
+	switch {
+	case symbols[name] != "":
+		kind := kindOrd
+		if openers[symbols[name]] {
+			kind = kindOpen
+		} else if closers[symbols[name]] {
+			kind = kindClose
+		}
+		return &symbol{text: symbols[name], kind: kind}, nil
+	case operators[name] != "":
+		return &symbol{text: operators[name], kind: kindOp}, nil
+	case relations[name] != "":
+		return &symbol{text: relations[name], kind: kindRel}, nil
+	case largeOperators[name].text != "":
+		return &symbol{text: largeOperators[name].text, kind: kindLarge, limits: largeOperators[name].limits}, nil
+	case ignored[name]:
+		return nil, nil
+	}
+	if limits, ok := functions[name]; ok {
+		return &symbol{text: name, kind: kindFunction, limits: limits}, nil
+	}
+	if width, ok := spaces[name]; ok {
+		return &space{width: width}, nil
+	}
+	if _, ok := accents[name]; ok {
+		body, err := p.argument()
+		return &accent{name: name, body: body}, err
+	}
+	if _, ok := fonts[name]; ok || name == "mathrm" || name == "mathit" || name == "mathnormal" {
+		body, err := p.argument()
+		return &styled{font: name, body: body}, err
+	}
+
+	switch name {
+	case "text", "textrm", "textit", "textbf", "textsf", "texttt", "mbox":
+		s, err := p.braced()
+		return &text{text: s}, err
+	case "operatorname":
+		s, err := p.braced()
+		return &symbol{text: s, kind: kindFunction}, err
+	case "frac", "dfrac", "tfrac", "cfrac", "binom", "dbinom", "tbinom":
+		num, err := p.argument()
+		if err != nil {
+			return nil, err
+		}
+		den, err := p.argument()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(name, "binom") {
+			return &fenced{open: "(", close: ")", body: &fraction{num: num, den: den}}, nil
+		}
+		return &fraction{num: num, den: den, line: true}, nil
+	case "sqrt":
+		var index node
+		if p.skipSpace(); strings.HasPrefix(p.src[p.pos:], "[") {
+			end := strings.IndexByte(p.src[p.pos:], ']')
+			if end < 0 {
+				return nil, p.errorf(p.pos, "missing ]")
+			}
+			var err error
+			if index, err = parse(p.src[p.pos+1 : p.pos+end]); err != nil {
+				err.(*Error).Offset += p.pos + 1
+				return nil, err
+			}
+			p.pos += end + 1
+		}
+		body, err := p.argument()
+		return &radical{index: index, body: body}, err
+	case "not":
+		n, err := p.argument()
+		if s, ok := n.(*symbol); ok {
+			return &symbol{text: s.text + "̸", kind: s.kind}, err
+		}
+		return n, err
+	case "left":
+		return p.fenced(start)
+	case "begin":
+		return p.environment(start)
+	}
+	return nil, p.errorf(start, "unsupported command %s", token)
+}
+
+// character returns the symbol for a character typed as it is
+func character(c string) *symbol {
+	switch {
+	case c >= "0" && c <= "9":
+		return &symbol{text: c, kind: kindNumber}
+	case c == "-":
+		return &symbol{text: "−", kind: kindOp}
+	case c == "*":
+		return &symbol{text: "∗", kind: kindOp}
+	case c == "+":
+		return &symbol{text: c, kind: kindOp}
+	case c == "=" || c == "<" || c == ">" || c == ":":
+		return &symbol{text: c, kind: kindRel}
+	case c == "," || c == ";":
+		return &symbol{text: c, kind: kindPunct}
+	case openers[c]:
+		return &symbol{text: c, kind: kindOpen}
+	case closers[c]:
+		return &symbol{text: c, kind: kindClose}
+	case c == "~":
+		return &symbol{text: " ", kind: kindOrd}
+	}
+	return &symbol{text: c, kind: kindOrd}
+}
+
+// fenced reads the rest of \left ... \right
+func (p *parser) fenced(start int) (node, error) {
+	open, err := p.delimiter()
+	if err != nil {
+		return nil, err
+	}
+	body, end, err := p.row()
+	if err != nil {
+		return nil, err
+	}
+	if end != `\right` {
+		return nil, p.errorf(start, `\left without \right`)
+	}
+	close, err := p.delimiter()
+	if err != nil {
+		return nil, err
+	}
+	return &fenced{open: open, close: close, body: body}, nil
+}
+
+// delimiter reads the delimiter after \left or \right. A dot stands for
+// none.
+func (p *parser) delimiter() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.pos >= len(p.src) {
+		return "", p.errorf(start, "missing delimiter")
+	}
+	token := p.next()
+	if token == "." {
+		return "", nil
+	}
+	if !strings.HasPrefix(token, `\`) {
+		return token, nil
+	}
+	if s, ok := symbols[token[1:]]; ok {
+		return s, nil
+	}
+	return "", p.errorf(start, "unsupported delimiter %s", token)
+}
+
+// environment reads the rest of \begin{name} ... \end{name}
+func (p *parser) environment(start int) (node, error) {
+	name, err := p.braced()
+	if err != nil {
+		return nil, err
+	}
+	env, ok := environments[name]
+	if !ok {
+		return nil, p.errorf(start, "unsupported environment %s", name)
+	}
+	if name == "array" || name == "alignat" {
+		// The column spec or count only sets alignment and rules
+		if _, err := p.braced(); err != nil {
+			return nil, err
+		}
+	}
+	cells, err := p.cells(`\end`)
+	if err != nil {
+		return nil, err
+	}
+	end := p.end
+	if closing, err := p.braced(); err != nil || closing != name {
+		return nil, p.errorf(end, `\begin{%s} ended by \end{%s}`, name, closing)
+	}
+
+	var n node = &matrix{kind: env.kind, cells: cells}
+	if env.open != "" || env.close != "" {
+		n = &fenced{open: env.open, close: env.close, body: n}
+	}
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package tex
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePrimes(t *testing.T) {
+	tests := []struct {
+		source string
+		primes int
+		sub    bool
+	}{
+		{"f'", 1, false},
+		{"f'''", 3, false},
+		{"f_1''", 2, true},
+		{"f" + strings.Repeat("'", 1000), 1000, false},
+	}
+
+	for _, tt := range tests {
+		n, err := parse(tt.source)
+		if err != nil {
+			t.Fatalf("parse(%.10q): %v", tt.source, err)
+		}
+		r := n.(row)
+		if len(r) != 1 {
+			t.Fatalf("parse(%.10q) = %d nodes, want 1", tt.source, len(r))
+		}
+		s, ok := r[0].(*script)
+		if !ok {
+			t.Fatalf("parse(%.10q) = %T, want a script", tt.source, r[0])
+		}
+		if (s.sub != nil) != tt.sub {
+			t.Errorf("parse(%.10q) subscript = %v, want %v", tt.source, s.sub != nil, tt.sub)
+		}
+		sup, ok := s.sup.(row)
+		if !ok || len(sup) != tt.primes {
+			t.Fatalf("parse(%.10q) superscript = %#v, want a row of %d primes", tt.source, s.sup, tt.primes)
+		}
+		for _, p := range sup {
+			if sym, ok := p.(*symbol); !ok || sym.text != "′" {
+				t.Fatalf("parse(%.10q) superscript holds %#v, want only primes", tt.source, p)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{"x^{", "missing } at offset 2"},
+		{`\frac{1}`, "missing argument at offset 8"},
+		{"x^1^2", "double superscript at offset 3"},
+		{"x_1_2", "double subscript at offset 3"},
+		{`\left( x \right`, "missing delimiter at offset 15"},
+		{"a}", "unexpected } at offset 1"},
+	}
+
+	for _, tt := range tests {
+		_, err := parse(tt.source)
+		if err == nil {
+			t.Errorf("parse(%q) succeeded, want %q", tt.source, tt.err)
+			continue
+		}
+		if err.Error() != tt.err {
+			t.Errorf("parse(%q) error = %q, want %q", tt.source, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package tex
+
+// symbolKind decides how a symbol is spaced and laid out
+type symbolKind int
+
+const (
+	kindOrd symbolKind = iota
+	kindNumber
+	// kindOp is a binary operator, spaced on both sides unless it is unary
+	kindOp
+	// kindRel is a relation or arrow, spaced on both sides
+	kindRel
+	kindPunct
+	kindOpen
+	kindClose
+	// kindLarge is a big operator such as a sum or integral
+	kindLarge
+	// kindFunction is a named function such as sin or lim
+	kindFunction
+)
+
+// symbols maps commands to the characters they stand for
+var symbols = map[string]string{
+	// Greek
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "omicron": "ο", "pi": "π", "varpi": "ϖ",
+	"rho": "ρ", "varrho": "ϱ", "sigma": "σ", "varsigma": "ς", "tau": "τ", "upsilon": "υ",
+	"phi": "ϕ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	// Letter-like and miscellaneous
+	"infty": "∞", "partial": "∂", "nabla": "∇", "forall": "∀", "exists": "∃", "nexists": "∄",
+	"emptyset": "∅", "varnothing": "∅", "hbar": "ℏ", "ell": "ℓ", "Re": "ℜ", "Im": "ℑ",
+	"aleph": "ℵ", "wp": "℘", "prime": "′", "angle": "∠", "triangle": "△", "degree": "°",
+	"ldots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱", "dots": "…",
+	"neg": "¬", "lnot": "¬", "top": "⊤", "bot": "⊥", "checkmark": "✓",
+	"{": "{", "}": "}", "%": "%", "$": "$", "#": "#", "&": "&", "_": "_", "|": "‖",
+	"lbrace": "{", "rbrace": "}", "langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋",
+	"lceil": "⌈", "rceil": "⌉", "vert": "|", "Vert": "‖", "backslash": "\\",
+}
+
+// operators maps commands to binary operators
+var operators = map[string]string{
+	"pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "⋅", "ast": "∗", "star": "⋆",
+	"circ": "∘", "bullet": "∙", "oplus": "⊕", "ominus": "⊖", "otimes": "⊗", "odot": "⊙",
+	"cup": "∪", "cap": "∩", "setminus": "∖", "wedge": "∧", "land": "∧", "vee": "∨", "lor": "∨",
+}
+
+// relations maps commands to relations and arrows
+var relations = map[string]string{
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅", "propto": "∝", "ll": "≪", "gg": "≫",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "supset": "⊃", "subseteq": "⊆",
+	"supseteq": "⊇", "mid": "∣", "parallel": "∥", "perp": "⊥", "models": "⊨", "vdash": "⊢",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←", "leftrightarrow": "↔",
+	"Rightarrow": "⇒", "Leftarrow": "⇐", "Leftrightarrow": "⇔", "implies": "⟹", "iff": "⟺",
+	"mapsto": "↦", "longrightarrow": "⟶", "longleftarrow": "⟵", "uparrow": "↑", "downarrow": "↓",
+	"colon": ":", "coloneqq": "≔",
+}
+
+// largeOperators maps commands to big operators. Those with limits take
+// their scripts above and below in display math.
+var largeOperators = map[string]struct {
+	text   string
+	limits bool
+}{
+	"sum": {"∑", true}, "prod": {"∏", true}, "coprod": {"∐", true},
+	"bigcup": {"⋃", true}, "bigcap": {"⋂", true}, "bigoplus": {"⨁", true}, "bigotimes": {"⨂", true},
+	"bigvee": {"⋁", true}, "bigwedge": {"⋀", true},
+	"int": {"∫", false}, "iint": {"∬", false}, "iiint": {"∭", false}, "oint": {"∮", false},
+}
+
+// functions are the named functions set upright, and whether they take
+// limits like lim
+var functions = map[string]bool{
+	"sin": false, "cos": false, "tan": false, "cot": false, "sec": false, "csc": false,
+	"arcsin": false, "arccos": false, "arctan": false, "sinh": false, "cosh": false, "tanh": false,
+	"log": false, "ln": false, "lg": false, "exp": false, "deg": false, "dim": false, "ker": false,
+	"arg": false, "hom": false, "gcd": true, "det": true, "Pr": true,
+	"lim": true, "limsup": true, "liminf": true, "max": true, "min": true, "sup": true, "inf": true,
+}
+
+// spaces maps spacing commands to their width in columns
+var spaces = map[string]int{
+	",": 1, ":": 1, ";": 1, " ": 1, "!": 0, "quad": 2, "qquad": 4,
+	"thinspace": 1, "medspace": 1, "thickspace": 1, "enspace": 1,
+}
+
+// accents maps accent commands to combining characters
+var accents = map[string]rune{
+	"hat": '̂', "widehat": '̂', "bar": '̄', "overline": '̅',
+	"vec": '⃗', "dot": '̇', "ddot": '̈', "tilde": '̃', "widetilde": '̃',
+	"acute": '́', "grave": '̀', "breve": '̆', "check": '̌',
+	"underline": '̲',
+}
+
+// ignored are commands that only change sizes or spacing TeX picks anyway
+var ignored = map[string]bool{
+	"displaystyle": true, "textstyle": true, "scriptstyle": true, "limits": true, "nolimits": true,
+	"big": true, "Big": true, "bigg": true, "Bigg": true,
+	"bigl": true, "bigr": true, "Bigl": true, "Bigr": true, "biggl": true, "biggr": true,
+}
+
+// superscripts and subscripts map characters to their raised and lowered forms
+var (
+	superscripts = map[rune]rune{
+		'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+		'+': '⁺', '−': '⁻', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾',
+		'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ', 'f': 'ᶠ', 'g': 'ᵍ', 'h': 'ʰ', 'i': 'ⁱ',
+		'j': 'ʲ', 'k': 'ᵏ', 'l': 'ˡ', 'm': 'ᵐ', 'n': 'ⁿ', 'o': 'ᵒ', 'p': 'ᵖ', 'r': 'ʳ', 's': 'ˢ',
+		't': 'ᵗ', 'u': 'ᵘ', 'v': 'ᵛ', 'w': 'ʷ', 'x': 'ˣ', 'y': 'ʸ', 'z': 'ᶻ',
+		'A': 'ᴬ', 'B': 'ᴮ', 'D': 'ᴰ', 'E': 'ᴱ', 'G': 'ᴳ', 'H': 'ᴴ', 'I': 'ᴵ', 'J': 'ᴶ', 'K': 'ᴷ',
+		'L': 'ᴸ', 'M': 'ᴹ', 'N': 'ᴺ', 'O': 'ᴼ', 'P': 'ᴾ', 'R': 'ᴿ', 'T': 'ᵀ', 'U': 'ᵁ', 'V': 'ⱽ', 'W': 'ᵂ',
+		'α': 'ᵅ', 'β': 'ᵝ', 'γ': 'ᵞ', 'δ': 'ᵟ', 'θ': 'ᶿ', 'ϕ': 'ᵠ', 'φ': 'ᵠ', 'χ': 'ᵡ',
+		'′': '′', '∗': '*', '∘': '°',
+	}
+	subscripts = map[rune]rune{
+		'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+		'+': '₊', '−': '₋', '-': '₋', '=': '₌', '(': '₍', ')': '₎',
+		'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ', 'j': 'ⱼ', 'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ',
+		'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ', 's': 'ₛ', 't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ',
+		'β': 'ᵦ', 'γ': 'ᵧ', 'ρ': 'ᵨ', 'ϕ': 'ᵩ', 'φ': 'ᵩ', 'χ': 'ᵪ',
+	}
+)
+
+// fractions are the vulgar fractions with a character of their own
+var fractions = map[string]string{
+	"1/2": "½", "1/3": "⅓", "2/3": "⅔", "1/4": "¼", "3/4": "¾", "1/5": "⅕", "2/5": "⅖",
+	"3/5": "⅗", "4/5": "⅘", "1/6": "⅙", "5/6": "⅚", "1/7": "⅐", "1/8": "⅛", "3/8": "⅜",
+	"5/8": "⅝", "7/8": "⅞", "1/9": "⅑", "1/10": "⅒",
+}
+
+// fonts maps font commands to the first capital, small letter and digit
+// of their Unicode alphabet, with the letters that live elsewhere
+var fonts = map[string]struct {
+	capital, small, digit rune
+	exceptions            map[rune]rune
+}{
+	"mathbb": {0x1D538, 0x1D552, 0x1D7D8, map[rune]rune{
+		'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ'}},
+	"mathbf":     {0x1D400, 0x1D41A, 0x1D7CE, nil},
+	"boldsymbol": {0x1D400, 0x1D41A, 0x1D7CE, nil},
+	"mathcal": {0x1D49C, 0x1D4B6, 0, map[rune]rune{
+		'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ',
+		'e': 'ℯ', 'g': 'ℊ', 'o': 'ℴ'}},
+	"mathfrak": {0x1D504, 0x1D51E, 0, map[rune]rune{
+		'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ'}},
+	"mathsf": {0x1D5A0, 0x1D5BA, 0x1D7E2, nil},
+	"mathtt": {0x1D670, 0x1D68A, 0x1D7F6, nil},
+}
+
+// styleRune returns r in the named font, or r when the font has no form of it
+func styleRune(font string, r rune) rune {
+	f, ok := fonts[font]
+	if !ok {
+		return r
+	}
+	if e, ok := f.exceptions[r]; ok {
+		return e
+	}
+	switch {
+	case r >= 'A' && r <= 'Z':
+		return f.capital + r - 'A'
+	case r >= 'a' && r <= 'z':
+		return f.small + r - 'a'
+	case r >= '0' && r <= '9' && f.digit != 0:
+		return f.digit + r - '0'
+	}
+	return r
+}
+
+// openers and closers are the characters that open and close a group
+var (
+	openers = map[string]bool{"(": true, "[": true, "{": true, "⟨": true, "⌊": true, "⌈": true}
+	closers = map[string]bool{")": true, "]": true, "}": true, "⟩": true, "⌋": true, "⌉": true}
+)
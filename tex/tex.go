@@ -0,0 +1,29 @@
+// Package tex converts LaTeX math to Unicode text for the terminal, with
+// Greek letters, scripts, fractions, roots, big operators and matrices.
+package tex
+
+import "strings"
+
+// Inline converts math to a single line of text, writing fractions with
+// a slash and scripts with Unicode characters where they exist
+func Inline(source string) (string, error) {
+	n, err := parse(source)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(layout{flat: true}.line(n)), nil
+}
+
+// Block converts display math to lines of text, stacking fractions,
+// limits and matrix rows over several lines
+func Block(source string) ([]string, error) {
+	n, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+	lines := layout{}.box(n).lines
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines, nil
+}
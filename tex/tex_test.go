@@ -0,0 +1,82 @@
+package tex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInline(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"x^2", "x²"},
+		{"f'''", "f′′′"},
+		{"f_1''", "f₁′′"},
+		{`\alpha+\beta`, "α + β"},
+		{`\frac{a}{b}`, "a/b"},
+		{`\sqrt{x}`, "√x"},
+		{`\sum_{i=1}^{n} i`, "∑ᵢ₌₁ⁿ i"},
+		{`\mathbb{R}`, "ℝ"},
+	}
+
+	for _, tt := range tests {
+		got, err := Inline(tt.source)
+		if err != nil {
+			t.Errorf("Inline(%q): %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Inline(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestBlock(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{"x", []string{"x"}},
+		{`\frac{a}{b}`, []string{" a", "───", " b"}},
+		{`\sum_{i=1}^{n} i`, []string{" n", " ∑  i", "i=1"}},
+		{`\begin{pmatrix} 1 & 0 \\ 0 & 1 \end{pmatrix}`, []string{"⎛ 1  0 ⎞", "⎝ 0  1 ⎠"}},
+	}
+
+	for _, tt := range tests {
+		got, err := Block(tt.source)
+		if err != nil {
+			t.Errorf("Block(%q): %v", tt.source, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Block(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestMathML(t *testing.T) {
+	tests := []struct {
+		source  string
+		display bool
+		want    string
+	}{
+		{"f'''", false, "<msup><mi>f</mi><mrow><mo>′</mo><mo>′</mo><mo>′</mo></mrow></msup>"},
+		{"3.14", false, "<mn>3.14</mn>"},
+		{`\sum_{i=1}^{n}`, true, "<munderover><mo largeop=\"true\">∑</mo>"},
+		{`\sum_{i=1}^{n}`, false, "<msubsup><mo largeop=\"true\">∑</mo>"},
+		{"a<b", false, "<mo>&lt;</mo>"},
+	}
+
+	for _, tt := range tests {
+		got, err := MathML(tt.source, tt.display)
+		if err != nil {
+			t.Errorf("MathML(%q): %v", tt.source, err)
+			continue
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("MathML(%q) = %s, want it to contain %s", tt.source, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package themes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CSS returns a stylesheet applying the palette to the elements inside selector
+func CSS(colors ThemeColors, selector string) string {
+	var b strings.Builder
+	rule := func(elements, body string) {
+		var scoped []string
+		for _, el := range strings.Split(elements, ",") {
+			scoped = append(scoped, strings.TrimSpace(selector+" "+strings.TrimSpace(el)))
+		}
+		fmt.Fprintf(&b, "%s { %s }\n", strings.Join(scoped, ", "), body)
+	}
+
+	fmt.Fprintf(&b, "%s {\n", selector)
+	fmt.Fprintf(&b, "  --mdcli-primary: %s;\n", colors.Primary)
+	fmt.Fprintf(&b, "  --mdcli-secondary: %s;\n", colors.Secondary)
+	fmt.Fprintf(&b, "  --mdcli-accent: %s;\n", colors.Accent)
+	fmt.Fprintf(&b, "  --mdcli-background: %s;\n", colors.Background)
+	fmt.Fprintf(&b, "  --mdcli-text: %s;\n", colors.Text)
+	fmt.Fprintf(&b, "  --mdcli-link: %s;\n", colors.Link)
+	fmt.Fprintf(&b, "  --mdcli-code: %s;\n", colors.Code)
+	fmt.Fprintf(&b, "  --mdcli-header: %s;\n", colors.Header)
+	b.WriteString("  color: var(--mdcli-text);\n")
+	b.WriteString("  background: var(--mdcli-background);\n")
+	b.WriteString("  padding: 1.5rem 2rem;\n")
+	b.WriteString("  border-radius: 0.5rem;\n")
+	b.WriteString("}\n")
+
+	rule("h1, h2, h3, h4, h5, h6", "color: var(--mdcli-header);")
+	rule("h1, h2", "border-bottom: 1px solid var(--mdcli-secondary); padding-bottom: 0.3em;")
+	rule("a", "color: var(--mdcli-link);")
+	rule("code", "color: var(--mdcli-code);")
+	rule("pre code", "color: inherit;")
+	rule("blockquote", "margin-left: 0; padding-left: 1em; border-left: 4px solid var(--mdcli-primary); color: var(--mdcli-secondary);")
+	rule("hr", "border: 0; border-top: 1px solid var(--mdcli-secondary);")
+	rule("th, td", "border: 1px solid var(--mdcli-secondary); padding: 0.4em 0.8em;")
+	rule("li::marker", "color: var(--mdcli-accent);")
+	rule(".math-error", "color: #e5534b; font-family: monospace; border-bottom: 1px dotted; cursor: help;")
+
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/styles"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition describes a user theme as written in the config file or in a
+// theme file
+type Definition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Inherits names a built-in theme providing the unset colors and style.
+	// Custom themes cannot be inherited from.
+	Inherits    string      `yaml:"inherits"`
+	SyntaxStyle string      `yaml:"syntax_style"`
+	Colors      ThemeColors `yaml:"colors"`
+}
+
+// customThemes holds the user themes registered at startup
+var customThemes = map[string]Theme{}
+
+// Build resolves inheritance and validates the definition
+func (d Definition) Build() (Theme, error) {
+	name := normalizeName(d.Name)
+	if name == "" {
+		return Theme{}, fmt.Errorf("theme has no name")
+	}
+
+	base := d.Inherits
+	if base == "" {
+		if _, builtin := AvailableThemes[name]; builtin {
+			// Redefining a built-in only overrides what is set
+			base = name
+		}
+	}
+
+	theme := Theme{
+		Name:        d.Name,
+		Description: d.Description,
+		Colors:      d.Colors,
+		SyntaxStyle: d.SyntaxStyle,
+		Custom:      true,
+	}
+
+	if base != "" {
+		parent, ok := AvailableThemes[normalizeName(base)]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme '%s' inherits from unknown built-in theme '%s'", name, base)
+		}
+		theme.Colors = mergeColors(parent.Colors, d.Colors)
+		if strings.EqualFold(base, name) {
+			theme.Name = parent.Name
+		}
+		if theme.Description == "" {
+			theme.Description = parent.Description
+			if !strings.EqualFold(base, name) {
+				theme.Description = fmt.Sprintf("Based on %s", parent.Name)
+			}
+		}
+		if theme.SyntaxStyle == "" {
+			theme.SyntaxStyle = GetSyntaxHighlightingStyle(base)
+		}
+	}
+
+	if theme.SyntaxStyle == "" {
+		theme.SyntaxStyle = "dracula"
+	}
+	if _, ok := styles.Registry[theme.SyntaxStyle]; !ok {
+		return Theme{}, fmt.Errorf("theme '%s' uses unknown syntax style '%s'", name, theme.SyntaxStyle)
+	}
+
+	for _, c := range []struct{ field, value string }{
+		{"primary", theme.Colors.Primary},
+		{"secondary", theme.Colors.Secondary},
+		{"accent", theme.Colors.Accent},
+		{"background", theme.Colors.Background},
+		{"text", theme.Colors.Text},
+		{"link", theme.Colors.Link},
+		{"code", theme.Colors.Code},
+		{"header", theme.Colors.Header},
+	} {
+		if c.value == "" {
+			return Theme{}, fmt.Errorf("theme '%s' is missing the %s color", name, c.field)
+		}
+		if _, _, _, err := HexToRGB(c.value); err != nil {
+			return Theme{}, fmt.Errorf("theme '%s' has an invalid %s color: %w", name, c.field, err)
+		}
+	}
+
+	return theme, nil
+}
+
+// mergeColors returns base with every color set in override replaced
+func mergeColors(base, override ThemeColors) ThemeColors {
+	pick := func(b, o string) string {
+		if o != "" {
+			return o
+		}
+		return b
+	}
+	return ThemeColors{
+		Primary:    pick(base.Primary, override.Primary),
+		Secondary:  pick(base.Secondary, override.Secondary),
+		Accent:     pick(base.Accent, override.Accent),
+		Background: pick(base.Background, override.Background),
+		Text:       pick(base.Text, override.Text),
+		Link:       pick(base.Link, override.Link),
+		Code:       pick(base.Code, override.Code),
+		Header:     pick(base.Header, override.Header),
+	}
+}
+
+// Register validates a definition and makes it available under its name
+func Register(def Definition) error {
+	theme, err := def.Build()
+	if err != nil {
+		return err
+	}
+	customThemes[normalizeName(def.Name)] = theme
+	return nil
+}
+
+// ParseDefinitions decodes a YAML mapping of theme name to definition
+func ParseDefinitions(data []byte) ([]Definition, error) {
+	var raw map[string]Definition
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid theme definitions: %w", err)
+	}
+
+	var defs []Definition
+	for name, def := range raw {
+		if def.Name == "" {
+			def.Name = name
+		}
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, nil
+}
+
+// LoadFile reads a single theme definition. The theme is named after the
+// file unless it sets a name.
+func LoadFile(path string) (Definition, error) {
+	var def Definition
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return def, err
+	}
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return def, fmt.Errorf("invalid theme file %s: %w", path, err)
+	}
+	if def.Name == "" {
+		def.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return def, nil
+}
+
+// LoadDir reads every *.yaml and *.yml theme file in dir. A missing
+// directory is not an error.
+func LoadDir(dir string) ([]Definition, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+
+	var defs []Definition
+	var errs []error
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		def, err := LoadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defs = append(defs, def)
+	}
+	return defs, errs
+}
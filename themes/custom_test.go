@@ -0,0 +1,65 @@
+package themes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	defer func() { customThemes = map[string]Theme{} }()
+
+	err := Register(Definition{
+		Name:     "Midnight",
+		Inherits: "Nord",
+		Colors:   ThemeColors{Primary: "#d08770"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"midnight", "Midnight", " MIDNIGHT "} {
+		theme, err := GetTheme(name)
+		if err != nil {
+			t.Errorf("GetTheme(%q): %v", name, err)
+			continue
+		}
+		if theme.Colors.Primary != "#d08770" || theme.Colors.Link != AvailableThemes["nord"].Colors.Link {
+			t.Errorf("GetTheme(%q) colors = %+v, want nord with a custom primary", name, theme.Colors)
+		}
+		if style := GetSyntaxHighlightingStyle(name); style != "nord" {
+			t.Errorf("GetSyntaxHighlightingStyle(%q) = %q, want nord", name, style)
+		}
+	}
+
+	if _, err := GetTheme("Nord"); err != nil {
+		t.Errorf("GetTheme(\"Nord\"): %v", err)
+	}
+	if _, err := GetTheme("missing"); err == nil {
+		t.Error("GetTheme(\"missing\") succeeded")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	defer func() { customThemes = map[string]Theme{} }()
+	if err := Register(Definition{Name: "base", Inherits: "nord"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		def  Definition
+		want string
+	}{
+		{Definition{Inherits: "nord"}, "theme has no name"},
+		{Definition{Name: "child", Inherits: "base"}, "inherits from unknown built-in theme 'base'"},
+		{Definition{Name: "bare"}, "missing the primary color"},
+		{Definition{Name: "bad", Inherits: "nord", Colors: ThemeColors{Link: "#12"}}, "invalid link color"},
+		{Definition{Name: "style", Inherits: "nord", SyntaxStyle: "nope"}, "unknown syntax style 'nope'"},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.def.Build()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Build(%+v) error = %v, want %q", tt.def, err, tt.want)
+		}
+	}
+}
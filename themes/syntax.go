@@ -0,0 +1,76 @@
+package themes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/styles"
+)
+
+// syntaxCounterparts pairs chroma styles that have a light and dark variant
+var syntaxCounterparts = map[string]string{
+	"solarized-light": "solarized-dark",
+	"solarized-dark":  "solarized-light",
+	"monokai":         "monokailight",
+	"monokailight":    "monokai",
+	"paraiso-light":   "paraiso-dark",
+	"paraiso-dark":    "paraiso-light",
+	"xcode":           "xcode-dark",
+	"xcode-dark":      "xcode",
+}
+
+// SyntaxStyles returns the chroma styles for code in light and dark mode.
+// The theme's own style is used for the mode matching its background; the
+// other mode uses its counterpart, or github/dracula when it has none.
+func SyntaxStyles(themeName string) (light, dark string) {
+	name := GetSyntaxHighlightingStyle(themeName)
+	other, paired := syntaxCounterparts[name]
+
+	if isDarkStyle(styles.Get(name)) {
+		if !paired {
+			other = "github"
+		}
+		return other, name
+	}
+	if !paired {
+		other = "dracula"
+	}
+	return name, other
+}
+
+// isDarkStyle reports whether a style has a dark background
+func isDarkStyle(style *chroma.Style) bool {
+	bg := style.Get(chroma.Background).Background
+	return bg.IsSet() && bg.Brightness() < 0.5
+}
+
+// SyntaxCSS returns the stylesheet for code highlighted with CSS classes.
+// The light style applies by default and the dark one when the page root
+// has the "dark" class.
+func SyntaxCSS(themeName string) (string, error) {
+	light, dark := SyntaxStyles(themeName)
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+
+	var b strings.Builder
+	for _, variant := range []struct{ style, scope string }{
+		{light, "html:not(.dark)"},
+		{dark, "html.dark"},
+	} {
+		var css strings.Builder
+		if err := formatter.WriteCSS(&css, styles.Get(variant.style)); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "/* %s */\n", variant.style)
+		for _, line := range strings.Split(strings.TrimSpace(css.String()), "\n") {
+			// Lines look like "/* Keyword */ .chroma .k { ... }"
+			comment, rule, ok := strings.Cut(line, "*/ ")
+			if !ok {
+				continue
+			}
+			b.WriteString(comment + "*/ " + variant.scope + " " + rule + "\n")
+		}
+	}
+	return b.String(), nil
+}
@@ -2,6 +2,9 @@ package themes
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // Theme represents a color theme for terminal output
@@ -9,18 +12,22 @@ type Theme struct {
 	Name        string
 	Description string
 	Colors      ThemeColors
+	// SyntaxStyle is the chroma style of a user theme
+	SyntaxStyle string
+	// Custom is set for themes loaded from configuration
+	Custom bool
 }
 
 // ThemeColors defines the color scheme
 type ThemeColors struct {
-	Primary    string
-	Secondary  string
-	Accent     string
-	Background string
-	Text       string
-	Link       string
-	Code       string
-	Header     string
+	Primary    string `yaml:"primary"`
+	Secondary  string `yaml:"secondary"`
+	Accent     string `yaml:"accent"`
+	Background string `yaml:"background"`
+	Text       string `yaml:"text"`
+	Link       string `yaml:"link"`
+	Code       string `yaml:"code"`
+	Header     string `yaml:"header"`
 }
 
 // Available themes
@@ -97,8 +104,13 @@ var AvailableThemes = map[string]Theme{
 	},
 }
 
-// GetTheme returns a theme by name
+// GetTheme returns a theme by name, ignoring case. User themes take
+// precedence over built-in themes of the same name.
 func GetTheme(name string) (Theme, error) {
+	name = normalizeName(name)
+	if theme, exists := customThemes[name]; exists {
+		return theme, nil
+	}
 	if theme, exists := AvailableThemes[name]; exists {
 		return theme, nil
 	}
@@ -111,11 +123,21 @@ func ListThemes() []string {
 	for name := range AvailableThemes {
 		themes = append(themes, name)
 	}
+	for name := range customThemes {
+		if _, builtin := AvailableThemes[name]; !builtin {
+			themes = append(themes, name)
+		}
+	}
+	sort.Strings(themes)
 	return themes
 }
 
 // GetSyntaxHighlightingStyle returns the appropriate syntax highlighting style for a theme
 func GetSyntaxHighlightingStyle(themeName string) string {
+	themeName = normalizeName(themeName)
+	if theme, exists := customThemes[themeName]; exists && theme.SyntaxStyle != "" {
+		return theme.SyntaxStyle
+	}
 	switch themeName {
 	case "github":
 		return "github"
@@ -129,3 +151,24 @@ func GetSyntaxHighlightingStyle(themeName string) string {
 		return "dracula"
 	}
 }
+
+// normalizeName returns the key a theme is registered under
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// HexToRGB converts a "#rrggbb" or "#rgb" color string into its RGB components
+func HexToRGB(hex string) (r, g, b int, err error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("invalid color '%s'", hex)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid color '%s'", hex)
+	}
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff), nil
+}
@@ -9,11 +9,14 @@ import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
 import (
+	"strconv"
+
 	"github.com/tacheraSasi/mdcli/components/badge"
 	"github.com/tacheraSasi/mdcli/components/button"
 	"github.com/tacheraSasi/mdcli/components/card"
 	"github.com/tacheraSasi/mdcli/components/icon"
 	"github.com/tacheraSasi/mdcli/components/separator"
+	"github.com/tacheraSasi/mdcli/renderer"
 )
 
 // FileEntry represents a file or directory in the file tree.
@@ -33,6 +36,30 @@ type ServeData struct {
 	IsDirectoryMode bool
 	CurrentPath     string
 	Files           []FileEntry
+	// BasePath prefixes root-relative links, for sites not hosted at /
+	BasePath string
+	// Static marks pages written by `site build`, which have no live server
+	Static bool
+	// LastModified is the document modification time shown in the header
+	LastModified string
+	// Editable shows the editor (serve --edit)
+	Editable bool
+	// Outline lists the document headings for the sidebar
+	Outline []renderer.Heading
+}
+
+// sidebarLevel is the deepest heading level listed in the sidebar
+const sidebarLevel = 4
+
+// sidebarOutline returns the headings shown in the sidebar
+func sidebarOutline(outline []renderer.Heading) []renderer.Heading {
+	var headings []renderer.Heading
+	for _, h := range outline {
+		if h.Level <= sidebarLevel && h.ID != "" {
+			headings = append(headings, h)
+		}
+	}
+	return headings
 }
 
 // ServePage renders the full HTML page for the live preview.
@@ -64,13 +91,91 @@ func ServePage(data ServeData) templ.Component {
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(data.Title)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 37, Col: 22}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 64, Col: 22}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " – mdcli Preview</title><link rel=\"icon\" type=\"image/x-icon\" href=\"/assets/icons/favicon.ico\"><link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"/assets/icons/apple-touch-icon.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"192x192\" href=\"/assets/icons/icon-192.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"512x512\" href=\"/assets/icons/icon-512.png\"><link rel=\"stylesheet\" href=\"/assets/css/output.css\"><link rel=\"stylesheet\" href=\"https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/styles/github-dark.min.css\"><script src=\"https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/highlight.min.js\"></script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " – mdcli Preview</title><link rel=\"icon\" type=\"image/x-icon\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 templ.SafeURL
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(data.BasePath + "/assets/icons/favicon.ico"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 65, Col: 101}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\"><link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 templ.SafeURL
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(data.BasePath + "/assets/icons/apple-touch-icon.png"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 66, Col: 118}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\"><link rel=\"icon\" type=\"image/png\" sizes=\"192x192\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 templ.SafeURL
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(data.BasePath + "/assets/icons/icon-192.png"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 67, Col: 115}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\"><link rel=\"icon\" type=\"image/png\" sizes=\"512x512\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 templ.SafeURL
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(data.BasePath + "/assets/icons/icon-512.png"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 68, Col: 115}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\"><link rel=\"stylesheet\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var7 templ.SafeURL
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(data.BasePath + "/assets/css/output.css"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 69, Col: 84}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\"><link rel=\"stylesheet\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var8 templ.SafeURL
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(data.BasePath + "/assets/syntax/" + data.ThemeName + ".css"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 70, Col: 103}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -80,15 +185,34 @@ func ServePage(data ServeData) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</head><body class=\"bg-background text-foreground min-h-screen flex flex-col\"><!-- Live indicator badge -->")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</head><body class=\"bg-background text-foreground min-h-screen flex flex-col\" data-path=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var9 string
+		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(data.CurrentPath)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 75, Col: 101}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = liveIndicator().Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<div class=\"flex flex-1 min-h-0\"><!-- Sidebar -->")
+		if !data.Static {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<!-- Live indicator badge --> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = liveIndicator().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<div class=\"flex flex-1 min-h-0\"><!-- Sidebar -->")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -98,12 +222,12 @@ func ServePage(data ServeData) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		} else {
-			templ_7745c5c3_Err = tocSidebar().Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = tocSidebar(data.Outline).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<!-- Main content area --><main class=\"flex-1 flex flex-col min-w-0\"><div class=\"flex-1 max-w-4xl mx-auto px-6 py-8 w-full\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<!-- Main content area --><main class=\"flex-1 flex flex-col min-w-0\"><div id=\"doc-container\" class=\"flex-1 max-w-4xl mx-auto px-6 py-8 w-full\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -115,11 +239,21 @@ func ServePage(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<div id=\"doc-body\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if data.Editable {
+			templ_7745c5c3_Err = editorPane().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
 		templ_7745c5c3_Err = articleContent(data).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -127,7 +261,7 @@ func ServePage(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</main></div><!-- Floating action buttons -->")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</main></div><!-- Floating action buttons -->")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -135,11 +269,78 @@ func ServePage(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<!-- Diagram and math renderers, served from the binary --><script src=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var10 string
+		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(data.BasePath + "/assets/js/mermaid.min.js")
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 105, Col: 60}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"></script><script src=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var11 string
+		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(data.BasePath + "/assets/js/math.js")
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 106, Col: 53}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\"></script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = pageScripts().Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</body></html>")
+		if data.Editable {
+			templ_7745c5c3_Err = editorScript().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</body></html>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// searchBox queries /search as the user types and lists the results in
+// place of the file tree.
+func searchBox() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var12 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var12 == nil {
+			templ_7745c5c3_Var12 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<div class=\"mb-4\"><input id=\"search-input\" type=\"search\" placeholder=\"Search (press /)\" autocomplete=\"off\" aria-label=\"Search documents\" class=\"w-full rounded-md border border-input bg-background px-3 py-1.5 text-sm text-foreground placeholder:text-muted-foreground focus:outline-none focus:ring-2\"><ul id=\"search-results\" class=\"hidden mt-2 space-y-1 text-sm\"></ul></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -164,16 +365,16 @@ func liveIndicator() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var3 == nil {
-			templ_7745c5c3_Var3 = templ.NopComponent
+		templ_7745c5c3_Var13 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var13 == nil {
+			templ_7745c5c3_Var13 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<div class=\"fixed top-4 right-4 z-50\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<div class=\"fixed top-4 right-4 z-50\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var4 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var14 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -185,7 +386,7 @@ func liveIndicator() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<span class=\"relative flex h-2 w-2\"><span class=\"animate-ping absolute inline-flex h-full w-full rounded-full bg-primary-foreground opacity-75\"></span> <span class=\"relative inline-flex rounded-full h-2 w-2 bg-primary-foreground\"></span></span> LIVE")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "<span class=\"relative flex h-2 w-2\"><span class=\"animate-ping absolute inline-flex h-full w-full rounded-full bg-primary-foreground opacity-75\"></span> <span class=\"relative inline-flex rounded-full h-2 w-2 bg-primary-foreground\"></span></span> LIVE")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -193,11 +394,11 @@ func liveIndicator() templ.Component {
 		})
 		templ_7745c5c3_Err = badge.Badge(badge.Props{
 			Class: "gap-2 px-3 py-1.5 text-sm shadow-lg",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var4), templ_7745c5c3_Buffer)
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var14), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -222,12 +423,12 @@ func fileTreeSidebar(data ServeData) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var5 == nil {
-			templ_7745c5c3_Var5 = templ.NopComponent
+		templ_7745c5c3_Var15 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var15 == nil {
+			templ_7745c5c3_Var15 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<aside id=\"toc\" class=\"sidebar hidden lg:block w-64 shrink-0 sticky top-0 h-screen overflow-y-auto border-r border-border bg-card p-4 transition-all duration-300 ease-in-out\"><div class=\"flex items-center justify-between mb-4\"><div class=\"flex items-center gap-2 truncate sidebar-header-text\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "<aside id=\"toc\" class=\"sidebar hidden lg:block w-64 shrink-0 sticky top-0 h-screen overflow-y-auto border-r border-border bg-card p-4 transition-all duration-300 ease-in-out\"><div class=\"flex items-center justify-between mb-4\"><div class=\"flex items-center gap-2 truncate sidebar-header-text\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -235,7 +436,7 @@ func fileTreeSidebar(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<h3 class=\"text-sm font-semibold text-card-foreground\">Files</h3></div><button id=\"sidebar-collapse-btn\" class=\"rounded-md p-1.5 hover:bg-accent hover:text-accent-foreground transition-colors shrink-0 cursor-pointer\" type=\"button\" aria-label=\"Toggle sidebar\" title=\"Toggle sidebar\"><span id=\"sidebar-collapse-icon\" class=\"inline-flex transition-transform duration-300\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<h3 class=\"text-sm font-semibold text-card-foreground\">Files</h3></div><button id=\"sidebar-collapse-btn\" class=\"rounded-md p-1.5 hover:bg-accent hover:text-accent-foreground transition-colors shrink-0 cursor-pointer\" type=\"button\" aria-label=\"Toggle sidebar\" title=\"Toggle sidebar\"><span id=\"sidebar-collapse-icon\" class=\"inline-flex transition-transform duration-300\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -243,17 +444,27 @@ func fileTreeSidebar(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "</span></button></div><div id=\"sidebar-body\"><nav><ul class=\"space-y-0.5 text-sm text-muted-foreground\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "</span></button></div><div id=\"sidebar-body\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if !data.Static {
+			templ_7745c5c3_Err = searchBox().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<nav id=\"file-tree\"><ul class=\"space-y-0.5 text-sm text-muted-foreground\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, entry := range data.Files {
-			templ_7745c5c3_Err = fileTreeEntry(entry, data.CurrentPath).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = fileTreeEntry(entry, data.CurrentPath, data.BasePath).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "</ul></nav><!-- TOC placeholder for JS (hidden in file tree mode) --><div class=\"mt-6 pt-4 border-t border-border\"><div class=\"flex items-center gap-2 mb-3\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "</ul></nav><div class=\"mt-6 pt-4 border-t border-border\"><div class=\"flex items-center gap-2 mb-3\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -261,7 +472,15 @@ func fileTreeSidebar(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<h4 class=\"text-xs font-semibold text-muted-foreground uppercase tracking-wide\">On this page</h4></div><ul id=\"toc-list\" class=\"space-y-1 text-sm text-muted-foreground\"></ul></div></div></aside>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "<h4 class=\"text-xs font-semibold text-muted-foreground uppercase tracking-wide\">On this page</h4></div><ul id=\"toc-list\" class=\"space-y-1 text-sm text-muted-foreground\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = outlineItems(data.Outline).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "</ul></div></div></aside>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -270,7 +489,7 @@ func fileTreeSidebar(data ServeData) templ.Component {
 }
 
 // fileTreeEntry renders a single file or directory entry in the tree.
-func fileTreeEntry(entry FileEntry, currentPath string) templ.Component {
+func fileTreeEntry(entry FileEntry, currentPath, basePath string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -286,17 +505,17 @@ func fileTreeEntry(entry FileEntry, currentPath string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var6 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var6 == nil {
-			templ_7745c5c3_Var6 = templ.NopComponent
+		templ_7745c5c3_Var16 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var16 == nil {
+			templ_7745c5c3_Var16 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<li>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "<li>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if entry.IsDir {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<details open><summary class=\"flex items-center gap-1.5 py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground cursor-pointer transition-colors\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "<details open><summary class=\"flex items-center gap-1.5 py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground cursor-pointer transition-colors\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -304,49 +523,49 @@ func fileTreeEntry(entry FileEntry, currentPath string) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<span>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "<span>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var7 string
-			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(entry.Name)
+			var templ_7745c5c3_Var17 string
+			templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(entry.Name)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 132, Col: 23}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 191, Col: 23}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</span></summary><ul class=\"ml-3 pl-3 border-l border-border space-y-0.5\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "</span></summary><ul class=\"ml-3 pl-3 border-l border-border space-y-0.5\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			for _, child := range entry.Children {
-				templ_7745c5c3_Err = fileTreeEntry(child, currentPath).Render(ctx, templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = fileTreeEntry(child, currentPath, basePath).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</ul></details>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</ul></details>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		} else {
 			if entry.Path == currentPath {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<a href=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<a href=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var8 templ.SafeURL
-				templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL("/" + entry.Path))
+				var templ_7745c5c3_Var18 templ.SafeURL
+				templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(basePath + "/" + entry.Path))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 143, Col: 39}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 202, Col: 50}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\" class=\"flex items-center gap-1.5 py-1 px-2 rounded-md bg-accent text-accent-foreground font-medium transition-colors\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\" class=\"flex items-center gap-1.5 py-1 px-2 rounded-md bg-accent text-accent-foreground font-medium transition-colors\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -354,38 +573,38 @@ func fileTreeEntry(entry FileEntry, currentPath string) templ.Component {
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var9 string
-				templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(entry.Name)
+				var templ_7745c5c3_Var19 string
+				templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(entry.Name)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 147, Col: 23}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 206, Col: 23}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</span></a>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "</span></a>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<a href=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<a href=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var10 templ.SafeURL
-				templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL("/" + entry.Path))
+				var templ_7745c5c3_Var20 templ.SafeURL
+				templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(basePath + "/" + entry.Path))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 151, Col: 39}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 210, Col: 50}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "\" class=\"flex items-center gap-1.5 py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground transition-colors\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "\" class=\"flex items-center gap-1.5 py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground transition-colors\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -393,26 +612,26 @@ func fileTreeEntry(entry FileEntry, currentPath string) templ.Component {
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "<span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var11 string
-				templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(entry.Name)
+				var templ_7745c5c3_Var21 string
+				templ_7745c5c3_Var21, templ_7745c5c3_Err = templ.JoinStringErrs(entry.Name)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 155, Col: 23}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 214, Col: 23}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var21))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "</span></a>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "</span></a>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "</li>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "</li>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -421,7 +640,7 @@ func fileTreeEntry(entry FileEntry, currentPath string) templ.Component {
 }
 
 // tocSidebar renders the table of contents sidebar (single file mode).
-func tocSidebar() templ.Component {
+func tocSidebar(outline []renderer.Heading) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -437,12 +656,12 @@ func tocSidebar() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var12 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var12 == nil {
-			templ_7745c5c3_Var12 = templ.NopComponent
+		templ_7745c5c3_Var22 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var22 == nil {
+			templ_7745c5c3_Var22 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "<aside id=\"toc\" class=\"sidebar hidden lg:block w-64 shrink-0 sticky top-0 h-screen overflow-y-auto border-r border-border bg-card p-4 transition-all duration-300 ease-in-out\"><div class=\"flex items-center justify-between mb-4\"><div class=\"flex items-center gap-2 truncate sidebar-header-text\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "<aside id=\"toc\" class=\"sidebar hidden lg:block w-64 shrink-0 sticky top-0 h-screen overflow-y-auto border-r border-border bg-card p-4 transition-all duration-300 ease-in-out\"><div class=\"flex items-center justify-between mb-4\"><div class=\"flex items-center gap-2 truncate sidebar-header-text\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -450,7 +669,7 @@ func tocSidebar() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "<h3 class=\"text-sm font-semibold text-card-foreground\">Contents</h3></div><button id=\"sidebar-collapse-btn\" class=\"rounded-md p-1.5 hover:bg-accent hover:text-accent-foreground transition-colors shrink-0 cursor-pointer\" type=\"button\" aria-label=\"Toggle sidebar\" title=\"Toggle sidebar\"><span id=\"sidebar-collapse-icon\" class=\"inline-flex transition-transform duration-300\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "<h3 class=\"text-sm font-semibold text-card-foreground\">Contents</h3></div><button id=\"sidebar-collapse-btn\" class=\"rounded-md p-1.5 hover:bg-accent hover:text-accent-foreground transition-colors shrink-0 cursor-pointer\" type=\"button\" aria-label=\"Toggle sidebar\" title=\"Toggle sidebar\"><span id=\"sidebar-collapse-icon\" class=\"inline-flex transition-transform duration-300\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -458,7 +677,15 @@ func tocSidebar() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "</span></button></div><div id=\"sidebar-body\"><nav><ul id=\"toc-list\" class=\"space-y-1 text-sm text-muted-foreground\"></ul></nav></div></aside>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "</span></button></div><div id=\"sidebar-body\"><nav><ul id=\"toc-list\" class=\"space-y-1 text-sm text-muted-foreground\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = outlineItems(outline).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "</ul></nav></div></aside>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -466,6 +693,85 @@ func tocSidebar() templ.Component {
 	})
 }
 
+// outlineItems renders the table of contents entries, indented by level.
+// pageScripts builds the same markup when the editor previews changes.
+func outlineItems(outline []renderer.Heading) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var23 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var23 == nil {
+			templ_7745c5c3_Var23 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if headings := sidebarOutline(outline); len(headings) > 0 {
+			for _, h := range headings {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "<li class=\"rounded-md\" style=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var24 string
+				templ_7745c5c3_Var24, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues("padding-left: " + strconv.Itoa((h.Level-1)*12) + "px")
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 250, Col: 88}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var24))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "\"><a href=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var25 templ.SafeURL
+				templ_7745c5c3_Var25, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL("#" + h.ID))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 251, Col: 35}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var25))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "\" class=\"block py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground transition-colors\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var26 string
+				templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(h.Text)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 251, Col: 144}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "</a></li>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "<li class=\"text-muted-foreground italic text-xs px-2\">No headings</li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		return nil
+	})
+}
+
 // docHeader renders the document title and metadata.
 func docHeader(data ServeData) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
@@ -483,12 +789,12 @@ func docHeader(data ServeData) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var13 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var13 == nil {
-			templ_7745c5c3_Var13 = templ.NopComponent
+		templ_7745c5c3_Var27 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var27 == nil {
+			templ_7745c5c3_Var27 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Var14 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var28 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -500,7 +806,7 @@ func docHeader(data ServeData) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Var15 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var29 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -512,7 +818,7 @@ func docHeader(data ServeData) templ.Component {
 					}()
 				}
 				ctx = templ.InitializeContext(ctx)
-				templ_7745c5c3_Var16 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_Var30 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 					if !templ_7745c5c3_IsBuffer {
@@ -524,26 +830,26 @@ func docHeader(data ServeData) templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					var templ_7745c5c3_Var17 string
-					templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(data.Title)
+					var templ_7745c5c3_Var31 string
+					templ_7745c5c3_Var31, templ_7745c5c3_Err = templ.JoinStringErrs(data.Title)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 189, Col: 16}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 264, Col: 16}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var31))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					return nil
 				})
-				templ_7745c5c3_Err = card.Title(card.TitleProps{Class: "text-3xl font-bold tracking-tight"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var16), templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = card.Title(card.TitleProps{ID: "doc-title", Class: "text-3xl font-bold tracking-tight"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var30), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, " ")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, " ")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Var18 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_Var32 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 					if !templ_7745c5c3_IsBuffer {
@@ -555,11 +861,11 @@ func docHeader(data ServeData) templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "<div class=\"flex items-center gap-4 flex-wrap\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "<div class=\"flex items-center gap-4 flex-wrap\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Var19 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+					templ_7745c5c3_Var33 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 						templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 						templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 						if !templ_7745c5c3_IsBuffer {
@@ -575,17 +881,17 @@ func docHeader(data ServeData) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, " mdcli v2")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, " mdcli v2")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
 						return nil
 					})
-					templ_7745c5c3_Err = badge.Badge(badge.Props{Variant: badge.VariantSecondary, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var19), templ_7745c5c3_Buffer)
+					templ_7745c5c3_Err = badge.Badge(badge.Props{Variant: badge.VariantSecondary, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var33), templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Var20 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+					templ_7745c5c3_Var34 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 						templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 						templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 						if !templ_7745c5c3_IsBuffer {
@@ -601,17 +907,41 @@ func docHeader(data ServeData) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, " <span id=\"file-mod-time\">loading...</span>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, " ")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
+						if data.LastModified != "" {
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "<span id=\"file-mod-time\">")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							var templ_7745c5c3_Var35 string
+							templ_7745c5c3_Var35, templ_7745c5c3_Err = templ.JoinStringErrs(data.LastModified)
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 275, Col: 51}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var35))
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "</span>")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+						} else {
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "<span id=\"file-mod-time\">loading...</span>")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+						}
 						return nil
 					})
-					templ_7745c5c3_Err = badge.Badge(badge.Props{Variant: badge.VariantOutline, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var20), templ_7745c5c3_Buffer)
+					templ_7745c5c3_Err = badge.Badge(badge.Props{Variant: badge.VariantOutline, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var34), templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Var21 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+					templ_7745c5c3_Var36 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 						templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 						templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 						if !templ_7745c5c3_IsBuffer {
@@ -627,26 +957,26 @@ func docHeader(data ServeData) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, " Theme: ")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, " Theme: ")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var22 string
-						templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(data.ThemeName)
+						var templ_7745c5c3_Var37 string
+						templ_7745c5c3_Var37, templ_7745c5c3_Err = templ.JoinStringErrs(data.ThemeName)
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `serve.templ`, Line: 203, Col: 29}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/serve.templ`, Line: 282, Col: 29}
 						}
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var37))
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
 						return nil
 					})
-					templ_7745c5c3_Err = badge.Badge(badge.Props{Variant: badge.VariantOutline, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var21), templ_7745c5c3_Buffer)
+					templ_7745c5c3_Err = badge.Badge(badge.Props{Variant: badge.VariantOutline, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var36), templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<button id=\"copy-doc-btn\" class=\"inline-flex items-center gap-1.5 rounded-md border border-border px-3 py-1 text-xs font-semibold transition-all duration-200 bg-card text-muted-foreground hover:bg-accent hover:text-accent-foreground cursor-pointer shadow-sm\" title=\"Copy entire document to clipboard\"><span id=\"copy-doc-icon\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "<button id=\"copy-doc-btn\" class=\"inline-flex items-center gap-1.5 rounded-md border border-border px-3 py-1 text-xs font-semibold transition-all duration-200 bg-card text-muted-foreground hover:bg-accent hover:text-accent-foreground cursor-pointer shadow-sm\" title=\"Copy entire document to clipboard\"><span id=\"copy-doc-icon\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -654,25 +984,125 @@ func docHeader(data ServeData) templ.Component {
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "</span> <span id=\"copy-doc-label\">Copy</span></button></div>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "</span> <span id=\"copy-doc-label\">Copy</span></button> ")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					if data.Editable {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "<button id=\"edit-btn\" class=\"inline-flex items-center gap-1.5 rounded-md border border-border px-3 py-1 text-xs font-semibold transition-all duration-200 bg-card text-muted-foreground hover:bg-accent hover:text-accent-foreground cursor-pointer shadow-sm\" title=\"Edit this document (Ctrl+E)\">")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = icon.SquarePen(icon.Props{Size: 14}).Render(ctx, templ_7745c5c3_Buffer)
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "<span>Edit</span></button>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "</div>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					return nil
 				})
-				templ_7745c5c3_Err = card.Description(card.DescriptionProps{Class: "pt-3"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var18), templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = card.Description(card.DescriptionProps{Class: "pt-3"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var32), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				return nil
 			})
-			templ_7745c5c3_Err = card.Header(card.HeaderProps{Class: "px-0"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var15), templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = card.Header(card.HeaderProps{Class: "px-0"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var29), templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = card.Card(card.Props{Class: "mb-0 border-none shadow-none bg-transparent"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var28), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// editorPane is the Markdown editor shown beside the preview while editing.
+func editorPane() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var38 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var38 == nil {
+			templ_7745c5c3_Var38 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "<style>\n\t\tbody.editing #doc-container { max-width: none; }\n\t\tbody.editing #doc-body { display: grid; grid-template-columns: minmax(0, 1fr) minmax(0, 1fr); gap: 1.5rem; align-items: start; }\n\t\tbody.editing #editor-pane { display: flex; }\n\t\t#editor-pane { position: sticky; top: 1rem; }\n\t\t#editor-input { height: calc(100vh - 8rem); font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; tab-size: 4; }\n\t\t#editor-status.error { color: var(--destructive, #dc2626); }\n\t\t@media (max-width: 1023px) {\n\t\t\tbody.editing #doc-body { grid-template-columns: minmax(0, 1fr); }\n\t\t\t#editor-pane { position: static; }\n\t\t}\n\t</style><section id=\"editor-pane\" class=\"hidden flex-col gap-2\" aria-label=\"Editor\"><div class=\"flex items-center justify-between gap-2\"><span id=\"editor-status\" class=\"text-xs text-muted-foreground truncate\"></span><div class=\"flex items-center gap-2\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Var39 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = icon.Save(icon.Props{Size: 14}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, " Save")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			return nil
 		})
-		templ_7745c5c3_Err = card.Card(card.Props{Class: "mb-0 border-none shadow-none bg-transparent"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var14), templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = button.Button(button.Props{ID: "editor-save", Size: button.SizeSm, Class: "gap-1.5"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var39), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Var40 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "Close")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = button.Button(button.Props{ID: "editor-close", Size: button.SizeSm, Variant: button.VariantOutline}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var40), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "</div></div><textarea id=\"editor-input\" spellcheck=\"false\" aria-label=\"Markdown source\" class=\"w-full resize-none rounded-md border border-input bg-background p-3 text-sm text-foreground focus:outline-none focus:ring-2\"></textarea></section>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -680,7 +1110,9 @@ func docHeader(data ServeData) templ.Component {
 	})
 }
 
-// articleContent renders the markdown content.
+// articleContent renders the markdown content. The page has its own light
+// and dark colors, so the theme palette of the rendered document gives way
+// to them, as slides do.
 func articleContent(data ServeData) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -697,12 +1129,12 @@ func articleContent(data ServeData) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var23 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var23 == nil {
-			templ_7745c5c3_Var23 = templ.NopComponent
+		templ_7745c5c3_Var41 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var41 == nil {
+			templ_7745c5c3_Var41 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<article id=\"article-content\" class=\"prose prose-neutral dark:prose-invert max-w-none\n\t\t\tprose-headings:scroll-mt-20\n\t\t\tprose-a:text-primary prose-a:no-underline hover:prose-a:underline\n\t\t\tprose-code:before:content-none prose-code:after:content-none\n\t\t\tprose-code:bg-muted prose-code:px-1.5 prose-code:py-0.5 prose-code:rounded-md prose-code:text-sm prose-code:font-normal\n\t\t\tprose-pre:bg-muted prose-pre:border prose-pre:rounded-lg\n\t\t\tprose-blockquote:border-l-primary prose-blockquote:bg-muted/50 prose-blockquote:rounded-r-lg\n\t\t\tprose-img:rounded-lg prose-img:shadow-md\n\t\t\tprose-table:overflow-hidden prose-table:rounded-lg prose-table:border\n\t\t\tprose-th:bg-muted prose-th:px-4 prose-th:py-2\n\t\t\tprose-td:px-4 prose-td:py-2\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "<style>\n\t\t#article-content .mdcli-document {\n\t\t\t--mdcli-primary: var(--primary);\n\t\t\t--mdcli-secondary: var(--muted-foreground);\n\t\t\t--mdcli-accent: var(--muted-foreground);\n\t\t\t--mdcli-text: var(--foreground);\n\t\t\t--mdcli-header: var(--foreground);\n\t\t\t--mdcli-link: var(--primary);\n\t\t\t--mdcli-code: var(--foreground);\n\t\t\tbackground: transparent;\n\t\t\tpadding: 0;\n\t\t}\n\t</style><article id=\"article-content\" class=\"prose prose-neutral dark:prose-invert max-w-none\n\t\t\tprose-headings:scroll-mt-20\n\t\t\tprose-a:text-primary prose-a:no-underline hover:prose-a:underline\n\t\t\tprose-code:before:content-none prose-code:after:content-none\n\t\t\tprose-code:bg-muted prose-code:px-1.5 prose-code:py-0.5 prose-code:rounded-md prose-code:text-sm prose-code:font-normal\n\t\t\tprose-pre:bg-muted prose-pre:border prose-pre:rounded-lg\n\t\t\tprose-blockquote:border-l-primary prose-blockquote:bg-muted/50 prose-blockquote:rounded-r-lg\n\t\t\tprose-img:rounded-lg prose-img:shadow-md\n\t\t\tprose-table:overflow-hidden prose-table:rounded-lg prose-table:border\n\t\t\tprose-th:bg-muted prose-th:px-4 prose-th:py-2\n\t\t\tprose-td:px-4 prose-td:py-2\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -710,7 +1142,7 @@ func articleContent(data ServeData) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "</article>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, "</article>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -735,12 +1167,12 @@ func footerCredits() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var24 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var24 == nil {
-			templ_7745c5c3_Var24 = templ.NopComponent
+		templ_7745c5c3_Var42 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var42 == nil {
+			templ_7745c5c3_Var42 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "<footer class=\"border-t border-border bg-card/50 py-8\"><div class=\"max-w-4xl mx-auto px-6 text-center\"><p class=\"text-sm text-muted-foreground\">Built by <a href=\"https://github.com/tacheraSasi\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"text-primary hover:underline font-medium\">Tachera Sasi</a></p><a href=\"https://github.com/tacheraSasi/mdcli\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"inline-flex items-center gap-1.5 text-xs text-muted-foreground hover:text-primary transition-colors mt-2\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 74, "<footer class=\"border-t border-border bg-card/50 py-8\"><div class=\"max-w-4xl mx-auto px-6 text-center\"><p class=\"text-sm text-muted-foreground\">Built by <a href=\"https://github.com/tacheraSasi\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"text-primary hover:underline font-medium\">Tachera Sasi</a></p><a href=\"https://github.com/tacheraSasi/mdcli\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"inline-flex items-center gap-1.5 text-xs text-muted-foreground hover:text-primary transition-colors mt-2\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -748,7 +1180,7 @@ func footerCredits() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "<span>mdcli</span>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 75, "<span>mdcli</span>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -756,7 +1188,7 @@ func footerCredits() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "</a></div></footer>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 76, "</a></div></footer>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -781,16 +1213,16 @@ func floatingActions() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var25 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var25 == nil {
-			templ_7745c5c3_Var25 = templ.NopComponent
+		templ_7745c5c3_Var43 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var43 == nil {
+			templ_7745c5c3_Var43 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "<div class=\"fixed bottom-6 right-6 flex flex-col gap-3 z-50\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 77, "<div class=\"fixed bottom-6 right-6 flex flex-col gap-3 z-50\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var26 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var44 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -802,7 +1234,7 @@ func floatingActions() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "<span id=\"theme-icon-moon\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 78, "<span id=\"theme-icon-moon\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -810,7 +1242,7 @@ func floatingActions() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "</span> <span id=\"theme-icon-sun\" class=\"hidden\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 79, "</span> <span id=\"theme-icon-sun\" class=\"hidden\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -818,7 +1250,7 @@ func floatingActions() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "</span>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 80, "</span>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -829,11 +1261,11 @@ func floatingActions() templ.Component {
 			Size:    button.SizeIcon,
 			Variant: button.VariantOutline,
 			Class:   "rounded-full h-11 w-11 shadow-lg bg-card",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var26), templ_7745c5c3_Buffer)
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var44), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var27 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var45 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -856,11 +1288,11 @@ func floatingActions() templ.Component {
 			Size:    button.SizeIcon,
 			Variant: button.VariantOutline,
 			Class:   "rounded-full h-11 w-11 shadow-lg bg-card lg:hidden",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var27), templ_7745c5c3_Buffer)
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var45), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var28 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var46 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -872,7 +1304,7 @@ func floatingActions() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "<span id=\"copy-doc-fab-icon\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 81, "<span id=\"copy-doc-fab-icon\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -880,7 +1312,7 @@ func floatingActions() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "</span>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 82, "</span>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -891,11 +1323,11 @@ func floatingActions() templ.Component {
 			Size:    button.SizeIcon,
 			Variant: button.VariantOutline,
 			Class:   "rounded-full h-11 w-11 shadow-lg bg-card",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var28), templ_7745c5c3_Buffer)
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var46), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var29 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var47 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -918,11 +1350,11 @@ func floatingActions() templ.Component {
 			Size:    button.SizeIcon,
 			Variant: button.VariantOutline,
 			Class:   "rounded-full h-11 w-11 shadow-lg bg-card",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var29), templ_7745c5c3_Buffer)
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var47), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 83, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -930,7 +1362,8 @@ func floatingActions() templ.Component {
 	})
 }
 
-// autoReloadScript injects the auto-reload polling script.
+// autoReloadScript subscribes to /events and swaps the article in place
+// when the open document changes, keeping the scroll position.
 func autoReloadScript() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -947,12 +1380,44 @@ func autoReloadScript() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var30 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var30 == nil {
-			templ_7745c5c3_Var30 = templ.NopComponent
+		templ_7745c5c3_Var48 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var48 == nil {
+			templ_7745c5c3_Var48 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 84, "<script>\n\t\t(function() {\n\t\t\tconst source = new EventSource('/events');\n\t\t\tlet pending = null;\n\n\t\t\tfunction swapPage() {\n\t\t\t\tfetch(location.href, { cache: 'no-store' })\n\t\t\t\t\t.then(r => {\n\t\t\t\t\t\tif (!r.ok) throw new Error(r.statusText);\n\t\t\t\t\t\treturn r.text();\n\t\t\t\t\t})\n\t\t\t\t\t.then(text => {\n\t\t\t\t\t\tconst next = new DOMParser().parseFromString(text, 'text/html');\n\t\t\t\t\t\tconst x = window.scrollX;\n\t\t\t\t\t\tconst y = window.scrollY;\n\t\t\t\t\t\t['article-content', 'toc-list', 'file-tree', 'doc-title', 'file-mod-time'].forEach(id => {\n\t\t\t\t\t\t\tconst current = document.getElementById(id);\n\t\t\t\t\t\t\tconst fresh = next.getElementById(id);\n\t\t\t\t\t\t\tif (current && fresh) current.innerHTML = fresh.innerHTML;\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdocument.title = next.title;\n\t\t\t\t\t\twindow.dispatchEvent(new Event('mdcli:content'));\n\t\t\t\t\t\twindow.scrollTo(x, y);\n\t\t\t\t\t})\n\t\t\t\t\t.catch(() => location.reload());\n\t\t\t}\n\n\t\t\tsource.addEventListener('change', e => {\n\t\t\t\tconst ev = JSON.parse(e.data);\n\t\t\t\tconst current = document.body.dataset.path || '';\n\t\t\t\t// The editor owns the preview while it is open\n\t\t\t\tif (document.body.classList.contains('editing')) {\n\t\t\t\t\twindow.dispatchEvent(new CustomEvent('mdcli:changed', { detail: ev }));\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\t// An empty path is the only document in single-file mode\n\t\t\t\tif (ev.path === '' || ev.path === current || ev.tree) {\n\t\t\t\t\t// Coalesce the bursts of events a single save can produce\n\t\t\t\t\tclearTimeout(pending);\n\t\t\t\t\tpending = setTimeout(swapPage, 50);\n\t\t\t\t}\n\t\t\t});\n\t\t})();\n\t</script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// editorScript drives the --edit mode editor: it loads the source from
+// /api/file, previews through /api/render as you type and saves with a
+// conflict check.
+func editorScript() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var49 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var49 == nil {
+			templ_7745c5c3_Var49 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "<script>\n\t\tlet lastModified = 0;\n\t\tfunction checkForUpdates() {\n\t\t\tfetch('/status')\n\t\t\t\t.then(r => r.json())\n\t\t\t\t.then(data => {\n\t\t\t\t\tif (data.lastModified !== lastModified && lastModified !== 0) {\n\t\t\t\t\t\tlocation.reload();\n\t\t\t\t\t}\n\t\t\t\t\tlastModified = data.lastModified;\n\t\t\t\t})\n\t\t\t\t.catch(() => {});\n\t\t}\n\t\tsetInterval(checkForUpdates, 1000);\n\t\tcheckForUpdates();\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 85, "<script>\n\t\t(function() {\n\t\t\tconst editBtn = document.getElementById('edit-btn');\n\t\t\tconst textarea = document.getElementById('editor-input');\n\t\t\tconst status = document.getElementById('editor-status');\n\t\t\tconst article = document.getElementById('article-content');\n\t\t\tconst path = document.body.dataset.path || '';\n\t\t\tif (!editBtn || !textarea) return;\n\n\t\t\t// modTime of the file version the editor content is based on\n\t\t\tlet baseline = 0;\n\t\t\tlet saved = '';\n\t\t\tlet previewTimer = null;\n\t\t\tlet previewSeq = 0;\n\t\t\t// The save in flight, awaited before reacting to change events\n\t\t\tlet pendingSave = null;\n\n\t\t\tfunction setStatus(text, isError) {\n\t\t\t\tstatus.textContent = text;\n\t\t\t\tstatus.classList.toggle('error', !!isError);\n\t\t\t}\n\n\t\t\tfunction dirty() {\n\t\t\t\treturn textarea.value !== saved;\n\t\t\t}\n\n\t\t\tasync function request(url, options) {\n\t\t\t\tconst res = await fetch(url, options);\n\t\t\t\tconst data = await res.json().catch(() => ({ error: res.statusText }));\n\t\t\t\treturn { status: res.status, data };\n\t\t\t}\n\n\t\t\tasync function load() {\n\t\t\t\tconst { status: code, data } = await request('/api/file?path=' + encodeURIComponent(path));\n\t\t\t\tif (code !== 200) throw new Error(data.error || 'could not load the document');\n\t\t\t\ttextarea.value = data.content || '';\n\t\t\t\tsaved = textarea.value;\n\t\t\t\tbaseline = data.modTime;\n\t\t\t}\n\n\t\t\tasync function open() {\n\t\t\t\ttry {\n\t\t\t\t\tawait load();\n\t\t\t\t} catch (err) {\n\t\t\t\t\talert('Cannot edit: ' + err.message);\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tdocument.body.classList.add('editing');\n\t\t\t\tsetStatus('Editing ' + (path || document.title));\n\t\t\t\ttextarea.focus();\n\t\t\t}\n\n\t\t\tfunction close() {\n\t\t\t\tif (dirty() && !confirm('Discard unsaved changes?')) return;\n\t\t\t\tdocument.body.classList.remove('editing');\n\t\t\t\t// Show the saved document again instead of the draft\n\t\t\t\tlocation.reload();\n\t\t\t}\n\n\t\t\tasync function preview() {\n\t\t\t\tconst seq = ++previewSeq;\n\t\t\t\tconst { status: code, data } = await request('/api/render', {\n\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\tbody: JSON.stringify({ markdown: textarea.value }),\n\t\t\t\t}).catch(() => ({ status: 0, data: {} }));\n\t\t\t\tif (code !== 200 || seq !== previewSeq) return;\n\t\t\t\tarticle.innerHTML = data.output;\n\t\t\t\twindow.dispatchEvent(new CustomEvent('mdcli:content', { detail: { outline: data.outline } }));\n\t\t\t}\n\n\t\t\tfunction save(force) {\n\t\t\t\tpendingSave = doSave(force);\n\t\t\t\treturn pendingSave;\n\t\t\t}\n\n\t\t\tasync function doSave(force) {\n\t\t\t\tsetStatus('Saving…');\n\t\t\t\tconst content = textarea.value;\n\t\t\t\tconst { status: code, data } = await request('/api/file', {\n\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\tbody: JSON.stringify({ path, content, modTime: baseline, force: !!force }),\n\t\t\t\t}).catch((err) => ({ status: 0, data: { error: err.message } }));\n\n\t\t\t\tif (code === 409) {\n\t\t\t\t\tsetStatus('The file changed on disk since it was opened.', true);\n\t\t\t\t\tif (confirm('The file changed on disk since you opened it. Overwrite it with your version?')) {\n\t\t\t\t\t\tsave(true);\n\t\t\t\t\t}\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tif (code !== 200) {\n\t\t\t\t\tsetStatus('Save failed: ' + (data.error || 'server unreachable'), true);\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tbaseline = data.modTime;\n\t\t\t\tsaved = content;\n\t\t\t\tsetStatus('Saved at ' + new Date().toLocaleTimeString());\n\t\t\t}\n\n\t\t\teditBtn.addEventListener('click', open);\n\t\t\tdocument.getElementById('editor-save').addEventListener('click', () => save(false));\n\t\t\tdocument.getElementById('editor-close').addEventListener('click', close);\n\n\t\t\ttextarea.addEventListener('input', () => {\n\t\t\t\tsetStatus(dirty() ? 'Unsaved changes' : 'No changes');\n\t\t\t\tclearTimeout(previewTimer);\n\t\t\t\tpreviewTimer = setTimeout(preview, 250);\n\t\t\t});\n\n\t\t\t// Tab inserts a tab instead of leaving the editor\n\t\t\ttextarea.addEventListener('keydown', (e) => {\n\t\t\t\tif (e.key === 'Tab' && !e.shiftKey) {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\tdocument.execCommand('insertText', false, '\\t');\n\t\t\t\t}\n\t\t\t});\n\n\t\t\tdocument.addEventListener('keydown', (e) => {\n\t\t\t\tconst mod = e.ctrlKey || e.metaKey;\n\t\t\t\tif (mod && e.key === 's' && document.body.classList.contains('editing')) {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\tsave(false);\n\t\t\t\t} else if (mod && e.key === 'e' && !document.body.classList.contains('editing')) {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\topen();\n\t\t\t\t}\n\t\t\t});\n\n\t\t\t// Warn when the file changes on disk while the editor is open\n\t\t\twindow.addEventListener('mdcli:changed', async (e) => {\n\t\t\t\tif (e.detail.path !== path) return;\n\t\t\t\ttry {\n\t\t\t\t\tawait pendingSave;\n\t\t\t\t\tconst { status: code, data } = await request('/api/file?path=' + encodeURIComponent(path));\n\t\t\t\t\tif (code === 200 && data.modTime !== baseline) {\n\t\t\t\t\t\tsetStatus('The file changed on disk. Saving will ask before overwriting it.', true);\n\t\t\t\t\t}\n\t\t\t\t} catch (err) {}\n\t\t\t});\n\n\t\t\twindow.addEventListener('beforeunload', (e) => {\n\t\t\t\tif (document.body.classList.contains('editing') && dirty()) {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\te.returnValue = '';\n\t\t\t\t}\n\t\t\t});\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -960,7 +1425,7 @@ func autoReloadScript() templ.Component {
 	})
 }
 
-// pageScripts contains the client-side JS for TOC generation, theme toggling, etc.
+// pageScripts contains the client-side JS for TOC highlighting, theme toggling, etc.
 func pageScripts() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -977,12 +1442,12 @@ func pageScripts() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var31 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var31 == nil {
-			templ_7745c5c3_Var31 = templ.NopComponent
+		templ_7745c5c3_Var50 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var50 == nil {
+			templ_7745c5c3_Var50 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "<script>\n\t\t(function() {\n\t\t\t// ========== THEME TOGGLE ==========\n\t\t\tconst themeToggle = document.getElementById('theme-toggle');\n\t\t\tconst moonIcon = document.getElementById('theme-icon-moon');\n\t\t\tconst sunIcon = document.getElementById('theme-icon-sun');\n\t\t\tconst html = document.documentElement;\n\n\t\t\tfunction setDark(isDark) {\n\t\t\t\thtml.classList.toggle('dark', isDark);\n\t\t\t\tmoonIcon.classList.toggle('hidden', isDark);\n\t\t\t\tsunIcon.classList.toggle('hidden', !isDark);\n\t\t\t\tlocalStorage.setItem('theme', isDark ? 'dark' : 'light');\n\t\t\t}\n\n\t\t\tconst storedTheme = localStorage.getItem('theme');\n\t\t\tif (storedTheme === 'light') {\n\t\t\t\tsetDark(false);\n\t\t\t} else if (storedTheme === 'dark') {\n\t\t\t\tsetDark(true);\n\t\t\t} else {\n\t\t\t\tsetDark(window.matchMedia('(prefers-color-scheme: dark)').matches);\n\t\t\t}\n\n\t\t\tthemeToggle.addEventListener('click', () => {\n\t\t\t\tsetDark(!html.classList.contains('dark'));\n\t\t\t});\n\n\t\t\t// ========== SIDEBAR COLLAPSE (desktop) ==========\n\t\t\tconst sidebarCollapseBtn = document.getElementById('sidebar-collapse-btn');\n\t\t\tconst toc = document.getElementById('toc');\n\t\t\tconst sidebarBody = document.getElementById('sidebar-body');\n\t\t\tconst collapseIcon = document.getElementById('sidebar-collapse-icon');\n\n\t\t\tif (sidebarCollapseBtn && toc && sidebarBody && collapseIcon) {\n\t\t\t\tfunction setSidebarCollapsed(collapsed) {\n\t\t\t\t\ttoc.classList.toggle('w-64', !collapsed);\n\t\t\t\t\ttoc.classList.toggle('w-16', collapsed);\n\t\t\t\t\tsidebarBody.classList.toggle('hidden', collapsed);\n\t\t\t\t\tcollapseIcon.classList.toggle('rotate-180', collapsed);\n\t\t\t\t\tlocalStorage.setItem('sidebarCollapsed', collapsed);\n\t\t\t\t}\n\n\t\t\t\t// Restore sidebar state from localStorage\n\t\t\t\tconst savedCollapsed = localStorage.getItem('sidebarCollapsed') === 'true';\n\t\t\t\tsetSidebarCollapsed(savedCollapsed);\n\n\t\t\t\tsidebarCollapseBtn.addEventListener('click', () => {\n\t\t\t\t\t// Don't toggle when in mobile overlay mode\n\t\t\t\t\tif (toc.classList.contains('fixed')) return;\n\t\t\t\t\tconst isCollapsed = toc.classList.contains('w-16');\n\t\t\t\t\tsetSidebarCollapsed(!isCollapsed);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\t// ========== MOBILE SIDEBAR OVERLAY ==========\n\t\t\tconst mobileToggle = document.getElementById('sidebar-mobile-toggle');\n\t\t\tif (mobileToggle && toc) {\n\t\t\t\tmobileToggle.addEventListener('click', () => {\n\t\t\t\t\tconst isOverlay = toc.classList.contains('fixed');\n\t\t\t\t\tif (isOverlay) {\n\t\t\t\t\t\ttoc.classList.remove('fixed', 'inset-0', 'z-40', 'w-full');\n\t\t\t\t\t\ttoc.classList.add('hidden');\n\t\t\t\t\t} else {\n\t\t\t\t\t\ttoc.classList.remove('hidden', 'w-16');\n\t\t\t\t\t\ttoc.classList.add('fixed', 'inset-0', 'z-40', 'w-full');\n\t\t\t\t\t\t// Ensure sidebar body is visible in overlay mode\n\t\t\t\t\t\tif (sidebarBody) sidebarBody.classList.remove('hidden');\n\t\t\t\t\t\tif (collapseIcon) collapseIcon.classList.remove('rotate-180');\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\t// Close overlay when clicking outside (on the main content)\n\t\t\t\tdocument.addEventListener('click', (e) => {\n\t\t\t\t\tif (toc.classList.contains('fixed') && !toc.contains(e.target) && !mobileToggle.contains(e.target)) {\n\t\t\t\t\t\ttoc.classList.remove('fixed', 'inset-0', 'z-40', 'w-full');\n\t\t\t\t\t\ttoc.classList.add('hidden');\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\n\t\t\t// ========== TABLE OF CONTENTS ==========\n\t\t\tconst tocList = document.getElementById('toc-list');\n\t\t\tconst headings = document.querySelectorAll('#article-content h1, #article-content h2, #article-content h3, #article-content h4');\n\n\t\t\tif (tocList) {\n\t\t\t\tif (headings.length > 0) {\n\t\t\t\t\theadings.forEach((heading, index) => {\n\t\t\t\t\t\tif (!heading.id) {\n\t\t\t\t\t\t\theading.id = heading.tagName.toLowerCase() + '-' + index;\n\t\t\t\t\t\t}\n\t\t\t\t\t\tconst li = document.createElement('li');\n\t\t\t\t\t\tconst level = parseInt(heading.tagName[1]);\n\t\t\t\t\t\tconst indent = (level - 1) * 12;\n\t\t\t\t\t\tli.style.paddingLeft = indent + 'px';\n\t\t\t\t\t\tli.className = 'rounded-md';\n\t\t\t\t\t\tconst a = document.createElement('a');\n\t\t\t\t\t\ta.href = '#' + heading.id;\n\t\t\t\t\t\ta.textContent = heading.textContent;\n\t\t\t\t\t\ta.className = 'block py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground transition-colors';\n\t\t\t\t\t\tli.appendChild(a);\n\t\t\t\t\t\ttocList.appendChild(li);\n\t\t\t\t\t});\n\t\t\t\t} else {\n\t\t\t\t\ttocList.innerHTML = '<li class=\"text-muted-foreground italic text-xs px-2\">No headings</li>';\n\t\t\t\t}\n\n\t\t\t\t// Highlight active TOC item on scroll\n\t\t\t\tfunction setActiveTOC() {\n\t\t\t\t\tconst scrollPos = window.scrollY + 100;\n\t\t\t\t\tlet current = null;\n\t\t\t\t\tfor (let i = headings.length - 1; i >= 0; i--) {\n\t\t\t\t\t\tif (headings[i].offsetTop <= scrollPos) {\n\t\t\t\t\t\t\tcurrent = headings[i];\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t\ttocList.querySelectorAll('a').forEach(a => {\n\t\t\t\t\t\ta.classList.remove('bg-accent', 'text-accent-foreground', 'font-medium');\n\t\t\t\t\t});\n\t\t\t\t\tif (current) {\n\t\t\t\t\t\tconst activeLink = tocList.querySelector('a[href=\"#' + current.id + '\"]');\n\t\t\t\t\t\tif (activeLink) {\n\t\t\t\t\t\t\tactiveLink.classList.add('bg-accent', 'text-accent-foreground', 'font-medium');\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\twindow.addEventListener('scroll', setActiveTOC);\n\t\t\t\tsetActiveTOC();\n\t\t\t}\n\n\t\t\t// ========== COPY CODE BUTTONS ==========\n\t\t\tdocument.querySelectorAll('#article-content pre').forEach(pre => {\n\t\t\t\tconst wrapper = document.createElement('div');\n\t\t\t\twrapper.className = 'relative group';\n\t\t\t\tpre.parentNode.insertBefore(wrapper, pre);\n\t\t\t\twrapper.appendChild(pre);\n\n\t\t\t\tconst btn = document.createElement('button');\n\t\t\t\tbtn.className = 'absolute top-2 right-2 opacity-0 group-hover:opacity-100 transition-opacity inline-flex items-center justify-center rounded-md text-sm h-8 w-8 border bg-card text-muted-foreground hover:text-foreground hover:bg-accent cursor-pointer';\n\t\t\t\tbtn.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><rect width=\"14\" height=\"14\" x=\"8\" y=\"8\" rx=\"2\" ry=\"2\"/><path d=\"M4 16c-1.1 0-2-.9-2-2V4c0-1.1.9-2 2-2h10c1.1 0 2 .9 2 2\"/></svg>';\n\t\t\t\tbtn.addEventListener('click', () => {\n\t\t\t\t\tconst code = pre.querySelector('code');\n\t\t\t\t\tconst text = code ? code.innerText : pre.innerText;\n\t\t\t\t\tnavigator.clipboard.writeText(text).then(() => {\n\t\t\t\t\t\tbtn.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M20 6 9 17l-5-5\"/></svg>';\n\t\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\t\tbtn.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><rect width=\"14\" height=\"14\" x=\"8\" y=\"8\" rx=\"2\" ry=\"2\"/><path d=\"M4 16c-1.1 0-2-.9-2-2V4c0-1.1.9-2 2-2h10c1.1 0 2 .9 2 2\"/></svg>';\n\t\t\t\t\t\t}, 2000);\n\t\t\t\t\t});\n\t\t\t\t});\n\t\t\t\twrapper.appendChild(btn);\n\t\t\t});\n\n\t\t\t// ========== COPY ENTIRE DOCUMENT ==========\n\t\t\tfunction copyEntireDoc() {\n\t\t\t\tconst article = document.getElementById('article-content');\n\t\t\t\tif (!article) return;\n\t\t\t\tconst text = article.innerText;\n\t\t\t\tnavigator.clipboard.writeText(text).then(() => {\n\t\t\t\t\t// Update header badge\n\t\t\t\t\tconst label = document.getElementById('copy-doc-label');\n\t\t\t\t\tconst icon = document.getElementById('copy-doc-icon');\n\t\t\t\t\tif (label) label.textContent = 'Copied!';\n\t\t\t\t\tif (icon) icon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M20 6 9 17l-5-5\"/></svg>';\n\t\t\t\t\t// Update floating button\n\t\t\t\t\tconst fabIcon = document.getElementById('copy-doc-fab-icon');\n\t\t\t\t\tif (fabIcon) fabIcon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M20 6 9 17l-5-5\"/></svg>';\n\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\t// Restore header icon and label\n\t\t\t\t\t\tif (label) label.textContent = 'Copy';\n\t\t\t\t\t\tif (icon) {\n\t\t\t\t\t\t\ticon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2\"/><rect width=\"8\" height=\"4\" x=\"8\" y=\"2\" rx=\"1\" ry=\"1\"/></svg>';\n\t\t\t\t\t\t}\n\t\t\t\t\t\t// Restore FAB icon\n\t\t\t\t\t\tif (fabIcon) {\n\t\t\t\t\t\t\tfabIcon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2\"/><rect width=\"8\" height=\"4\" x=\"8\" y=\"2\" rx=\"1\" ry=\"1\"/></svg>';\n\t\t\t\t\t\t}\n\t\t\t\t\t}, 2000);\n\t\t\t\t}).catch(() => {\n\t\t\t\t\tconst label = document.getElementById('copy-doc-label');\n\t\t\t\t\tif (label) label.textContent = 'Failed';\n\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\tif (label) label.textContent = 'Copy';\n\t\t\t\t\t}, 2000);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tconst copyDocBtn = document.getElementById('copy-doc-btn');\n\t\t\tif (copyDocBtn) {\n\t\t\t\tcopyDocBtn.addEventListener('click', copyEntireDoc);\n\t\t\t}\n\t\t\tconst copyDocFab = document.getElementById('copy-doc-fab');\n\t\t\tif (copyDocFab) {\n\t\t\t\tcopyDocFab.addEventListener('click', copyEntireDoc);\n\t\t\t}\n\n\t\t\t// ========== BACK TO TOP ==========\n\t\t\tdocument.getElementById('back-to-top').addEventListener('click', () => {\n\t\t\t\twindow.scrollTo({ top: 0, behavior: 'smooth' });\n\t\t\t});\n\n\t\t\t// ========== UPDATE LAST MODIFIED TIME ==========\n\t\t\tfunction updateModTime() {\n\t\t\t\tfetch('/status')\n\t\t\t\t\t.then(r => r.json())\n\t\t\t\t\t.then(data => {\n\t\t\t\t\t\tconst d = new Date(data.lastModified * 1000);\n\t\t\t\t\t\tdocument.getElementById('file-mod-time').textContent = d.toLocaleTimeString();\n\t\t\t\t\t})\n\t\t\t\t\t.catch(() => {});\n\t\t\t}\n\t\t\tupdateModTime();\n\t\t\tsetInterval(updateModTime, 5000);\n\n\t\t\t// ========== SYNTAX HIGHLIGHTING ==========\n\t\t\thljs.highlightAll();\n\t\t})();\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 86, "<script>\n\t\t(function() {\n\t\t\t// ========== THEME TOGGLE ==========\n\t\t\tconst themeToggle = document.getElementById('theme-toggle');\n\t\t\tconst moonIcon = document.getElementById('theme-icon-moon');\n\t\t\tconst sunIcon = document.getElementById('theme-icon-sun');\n\t\t\tconst html = document.documentElement;\n\n\t\t\tfunction setDark(isDark) {\n\t\t\t\thtml.classList.toggle('dark', isDark);\n\t\t\t\tmoonIcon.classList.toggle('hidden', isDark);\n\t\t\t\tsunIcon.classList.toggle('hidden', !isDark);\n\t\t\t\tlocalStorage.setItem('theme', isDark ? 'dark' : 'light');\n\t\t\t}\n\n\t\t\tconst storedTheme = localStorage.getItem('theme');\n\t\t\tif (storedTheme === 'light') {\n\t\t\t\tsetDark(false);\n\t\t\t} else if (storedTheme === 'dark') {\n\t\t\t\tsetDark(true);\n\t\t\t} else {\n\t\t\t\tsetDark(window.matchMedia('(prefers-color-scheme: dark)').matches);\n\t\t\t}\n\n\t\t\tthemeToggle.addEventListener('click', () => {\n\t\t\t\tsetDark(!html.classList.contains('dark'));\n\t\t\t\trenderDiagrams(true);\n\t\t\t});\n\n\t\t\t// ========== SIDEBAR COLLAPSE (desktop) ==========\n\t\t\tconst sidebarCollapseBtn = document.getElementById('sidebar-collapse-btn');\n\t\t\tconst toc = document.getElementById('toc');\n\t\t\tconst sidebarBody = document.getElementById('sidebar-body');\n\t\t\tconst collapseIcon = document.getElementById('sidebar-collapse-icon');\n\n\t\t\tif (sidebarCollapseBtn && toc && sidebarBody && collapseIcon) {\n\t\t\t\tfunction setSidebarCollapsed(collapsed) {\n\t\t\t\t\ttoc.classList.toggle('w-64', !collapsed);\n\t\t\t\t\ttoc.classList.toggle('w-16', collapsed);\n\t\t\t\t\tsidebarBody.classList.toggle('hidden', collapsed);\n\t\t\t\t\tcollapseIcon.classList.toggle('rotate-180', collapsed);\n\t\t\t\t\tlocalStorage.setItem('sidebarCollapsed', collapsed);\n\t\t\t\t}\n\n\t\t\t\t// Restore sidebar state from localStorage\n\t\t\t\tconst savedCollapsed = localStorage.getItem('sidebarCollapsed') === 'true';\n\t\t\t\tsetSidebarCollapsed(savedCollapsed);\n\n\t\t\t\tsidebarCollapseBtn.addEventListener('click', () => {\n\t\t\t\t\t// Don't toggle when in mobile overlay mode\n\t\t\t\t\tif (toc.classList.contains('fixed')) return;\n\t\t\t\t\tconst isCollapsed = toc.classList.contains('w-16');\n\t\t\t\t\tsetSidebarCollapsed(!isCollapsed);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\t// ========== MOBILE SIDEBAR OVERLAY ==========\n\t\t\tconst mobileToggle = document.getElementById('sidebar-mobile-toggle');\n\t\t\tif (mobileToggle && toc) {\n\t\t\t\tmobileToggle.addEventListener('click', () => {\n\t\t\t\t\tconst isOverlay = toc.classList.contains('fixed');\n\t\t\t\t\tif (isOverlay) {\n\t\t\t\t\t\ttoc.classList.remove('fixed', 'inset-0', 'z-40', 'w-full');\n\t\t\t\t\t\ttoc.classList.add('hidden');\n\t\t\t\t\t} else {\n\t\t\t\t\t\ttoc.classList.remove('hidden', 'w-16');\n\t\t\t\t\t\ttoc.classList.add('fixed', 'inset-0', 'z-40', 'w-full');\n\t\t\t\t\t\t// Ensure sidebar body is visible in overlay mode\n\t\t\t\t\t\tif (sidebarBody) sidebarBody.classList.remove('hidden');\n\t\t\t\t\t\tif (collapseIcon) collapseIcon.classList.remove('rotate-180');\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\t// Close overlay when clicking outside (on the main content)\n\t\t\t\tdocument.addEventListener('click', (e) => {\n\t\t\t\t\tif (toc.classList.contains('fixed') && !toc.contains(e.target) && !mobileToggle.contains(e.target)) {\n\t\t\t\t\t\ttoc.classList.remove('fixed', 'inset-0', 'z-40', 'w-full');\n\t\t\t\t\t\ttoc.classList.add('hidden');\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\n\t\t\t// ========== TABLE OF CONTENTS ==========\n\t\t\tconst tocList = document.getElementById('toc-list');\n\t\t\tlet headings = [];\n\n\t\t\t// The list is rendered by the server; the editor preview passes\n\t\t\t// the outline of the unsaved text to rebuild it\n\t\t\tfunction buildTOC(outline) {\n\t\t\t\tif (!tocList) return;\n\t\t\t\tif (outline) {\n\t\t\t\t\ttocList.innerHTML = '';\n\t\t\t\t\toutline.filter(h => h.level <= 4 && h.id).forEach(h => {\n\t\t\t\t\t\tconst li = document.createElement('li');\n\t\t\t\t\t\tli.style.paddingLeft = (h.level - 1) * 12 + 'px';\n\t\t\t\t\t\tli.className = 'rounded-md';\n\t\t\t\t\t\tconst a = document.createElement('a');\n\t\t\t\t\t\ta.href = '#' + h.id;\n\t\t\t\t\t\ta.textContent = h.text;\n\t\t\t\t\t\ta.className = 'block py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground transition-colors';\n\t\t\t\t\t\tli.appendChild(a);\n\t\t\t\t\t\ttocList.appendChild(li);\n\t\t\t\t\t});\n\t\t\t\t\tif (!tocList.children.length) {\n\t\t\t\t\t\ttocList.innerHTML = '<li class=\"text-muted-foreground italic text-xs px-2\">No headings</li>';\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\theadings = Array.from(document.querySelectorAll('#article-content h1, #article-content h2, #article-content h3, #article-content h4')).filter(h => h.id);\n\t\t\t\tsetActiveTOC();\n\t\t\t}\n\n\t\t\t// Highlight active TOC item on scroll\n\t\t\tfunction setActiveTOC() {\n\t\t\t\tif (!tocList) return;\n\t\t\t\tconst scrollPos = window.scrollY + 100;\n\t\t\t\tlet current = null;\n\t\t\t\tfor (let i = headings.length - 1; i >= 0; i--) {\n\t\t\t\t\tif (headings[i].offsetTop <= scrollPos) {\n\t\t\t\t\t\tcurrent = headings[i];\n\t\t\t\t\t\tbreak;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\ttocList.querySelectorAll('a').forEach(a => {\n\t\t\t\t\ta.classList.remove('bg-accent', 'text-accent-foreground', 'font-medium');\n\t\t\t\t});\n\t\t\t\tif (current) {\n\t\t\t\t\tconst activeLink = tocList.querySelector('a[href=\"#' + current.id + '\"]');\n\t\t\t\t\tif (activeLink) {\n\t\t\t\t\t\tactiveLink.classList.add('bg-accent', 'text-accent-foreground', 'font-medium');\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t}\n\t\t\twindow.addEventListener('scroll', setActiveTOC);\n\n\t\t\t// ========== COPY CODE BUTTONS ==========\n\t\t\tfunction addCopyButtons() {\n\t\t\t\tdocument.querySelectorAll('#article-content pre').forEach(pre => {\n\t\t\t\t\tconst wrapper = document.createElement('div');\n\t\t\t\t\twrapper.className = 'relative group';\n\t\t\t\t\tpre.parentNode.insertBefore(wrapper, pre);\n\t\t\t\t\twrapper.appendChild(pre);\n\n\t\t\t\t\tconst btn = document.createElement('button');\n\t\t\t\t\tbtn.className = 'absolute top-2 right-2 opacity-0 group-hover:opacity-100 transition-opacity inline-flex items-center justify-center rounded-md text-sm h-8 w-8 border bg-card text-muted-foreground hover:text-foreground hover:bg-accent cursor-pointer';\n\t\t\t\t\tbtn.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><rect width=\"14\" height=\"14\" x=\"8\" y=\"8\" rx=\"2\" ry=\"2\"/><path d=\"M4 16c-1.1 0-2-.9-2-2V4c0-1.1.9-2 2-2h10c1.1 0 2 .9 2 2\"/></svg>';\n\t\t\t\t\tbtn.addEventListener('click', () => {\n\t\t\t\t\t\tconst code = pre.querySelector('code');\n\t\t\t\t\t\tconst text = code ? code.innerText : pre.innerText;\n\t\t\t\t\t\tnavigator.clipboard.writeText(text).then(() => {\n\t\t\t\t\t\t\tbtn.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M20 6 9 17l-5-5\"/></svg>';\n\t\t\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\t\t\tbtn.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><rect width=\"14\" height=\"14\" x=\"8\" y=\"8\" rx=\"2\" ry=\"2\"/><path d=\"M4 16c-1.1 0-2-.9-2-2V4c0-1.1.9-2 2-2h10c1.1 0 2 .9 2 2\"/></svg>';\n\t\t\t\t\t\t\t}, 2000);\n\t\t\t\t\t\t});\n\t\t\t\t\t});\n\t\t\t\t\twrapper.appendChild(btn);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\t// ========== COPY ENTIRE DOCUMENT ==========\n\t\t\tfunction copyEntireDoc() {\n\t\t\t\tconst article = document.getElementById('article-content');\n\t\t\t\tif (!article) return;\n\t\t\t\tconst text = article.innerText;\n\t\t\t\tnavigator.clipboard.writeText(text).then(() => {\n\t\t\t\t\t// Update header badge\n\t\t\t\t\tconst label = document.getElementById('copy-doc-label');\n\t\t\t\t\tconst icon = document.getElementById('copy-doc-icon');\n\t\t\t\t\tif (label) label.textContent = 'Copied!';\n\t\t\t\t\tif (icon) icon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M20 6 9 17l-5-5\"/></svg>';\n\t\t\t\t\t// Update floating button\n\t\t\t\t\tconst fabIcon = document.getElementById('copy-doc-fab-icon');\n\t\t\t\t\tif (fabIcon) fabIcon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M20 6 9 17l-5-5\"/></svg>';\n\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\t// Restore header icon and label\n\t\t\t\t\t\tif (label) label.textContent = 'Copy';\n\t\t\t\t\t\tif (icon) {\n\t\t\t\t\t\t\ticon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"14\" height=\"14\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2\"/><rect width=\"8\" height=\"4\" x=\"8\" y=\"2\" rx=\"1\" ry=\"1\"/></svg>';\n\t\t\t\t\t\t}\n\t\t\t\t\t\t// Restore FAB icon\n\t\t\t\t\t\tif (fabIcon) {\n\t\t\t\t\t\t\tfabIcon.innerHTML = '<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><path d=\"M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2\"/><rect width=\"8\" height=\"4\" x=\"8\" y=\"2\" rx=\"1\" ry=\"1\"/></svg>';\n\t\t\t\t\t\t}\n\t\t\t\t\t}, 2000);\n\t\t\t\t}).catch(() => {\n\t\t\t\t\tconst label = document.getElementById('copy-doc-label');\n\t\t\t\t\tif (label) label.textContent = 'Failed';\n\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\tif (label) label.textContent = 'Copy';\n\t\t\t\t\t}, 2000);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tconst copyDocBtn = document.getElementById('copy-doc-btn');\n\t\t\tif (copyDocBtn) {\n\t\t\t\tcopyDocBtn.addEventListener('click', copyEntireDoc);\n\t\t\t}\n\t\t\tconst copyDocFab = document.getElementById('copy-doc-fab');\n\t\t\tif (copyDocFab) {\n\t\t\t\tcopyDocFab.addEventListener('click', copyEntireDoc);\n\t\t\t}\n\n\t\t\t// ========== SEARCH ==========\n\t\t\tconst searchInput = document.getElementById('search-input');\n\t\t\tconst searchResults = document.getElementById('search-results');\n\t\t\tconst fileTreeNav = document.getElementById('file-tree');\n\n\t\t\tif (searchInput && searchResults) {\n\t\t\t\tlet searchTimer = null;\n\t\t\t\tlet searchSeq = 0;\n\n\t\t\t\tfunction showTree(show) {\n\t\t\t\t\tsearchResults.classList.toggle('hidden', show);\n\t\t\t\t\tif (fileTreeNav) fileTreeNav.classList.toggle('hidden', !show);\n\t\t\t\t}\n\n\t\t\t\tasync function runSearch() {\n\t\t\t\t\tconst query = searchInput.value;\n\t\t\t\t\tconst seq = ++searchSeq;\n\t\t\t\t\tif (!query.trim()) {\n\t\t\t\t\t\tshowTree(true);\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\n\t\t\t\t\tlet data;\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst res = await fetch('/search?q=' + encodeURIComponent(query));\n\t\t\t\t\t\tif (!res.ok) return;\n\t\t\t\t\t\tdata = await res.json();\n\t\t\t\t\t} catch (err) {\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\t// Ignore responses to outdated queries\n\t\t\t\t\tif (seq !== searchSeq) return;\n\n\t\t\t\t\tsearchResults.innerHTML = '';\n\t\t\t\t\tif (data.results.length === 0) {\n\t\t\t\t\t\tsearchResults.innerHTML = '<li class=\"text-muted-foreground italic text-xs px-2\">No results</li>';\n\t\t\t\t\t}\n\t\t\t\t\tdata.results.forEach((result) => {\n\t\t\t\t\t\tconst li = document.createElement('li');\n\t\t\t\t\t\tconst a = document.createElement('a');\n\t\t\t\t\t\ta.href = '/' + result.path + (result.anchor ? '#' + result.anchor : '');\n\t\t\t\t\t\ta.className = 'block py-1 px-2 rounded-md hover:bg-accent hover:text-accent-foreground transition-colors';\n\t\t\t\t\t\t// The server escapes these and marks the query terms\n\t\t\t\t\t\tconst heading = result.heading && result.heading !== result.title ? ' › ' + result.headingHtml : '';\n\t\t\t\t\t\ta.innerHTML =\n\t\t\t\t\t\t\t'<span class=\"block font-medium text-foreground truncate\">' + result.titleHtml + heading + '</span>' +\n\t\t\t\t\t\t\t'<span class=\"block text-xs text-muted-foreground\">' + result.snippetHtml + '</span>';\n\t\t\t\t\t\tli.appendChild(a);\n\t\t\t\t\t\tsearchResults.appendChild(li);\n\t\t\t\t\t});\n\t\t\t\t\tif (data.total > data.results.length) {\n\t\t\t\t\t\tconst more = document.createElement('li');\n\t\t\t\t\t\tmore.className = 'text-muted-foreground italic text-xs px-2';\n\t\t\t\t\t\tmore.textContent = (data.total - data.results.length) + ' more results';\n\t\t\t\t\t\tsearchResults.appendChild(more);\n\t\t\t\t\t}\n\t\t\t\t\tshowTree(false);\n\t\t\t\t}\n\n\t\t\t\tsearchInput.addEventListener('input', () => {\n\t\t\t\t\tclearTimeout(searchTimer);\n\t\t\t\t\tsearchTimer = setTimeout(runSearch, 150);\n\t\t\t\t});\n\t\t\t\tsearchInput.addEventListener('keydown', (e) => {\n\t\t\t\t\tif (e.key === 'Escape') {\n\t\t\t\t\t\tsearchInput.value = '';\n\t\t\t\t\t\trunSearch();\n\t\t\t\t\t\tsearchInput.blur();\n\t\t\t\t\t} else if (e.key === 'Enter') {\n\t\t\t\t\t\tconst first = searchResults.querySelector('a');\n\t\t\t\t\t\tif (first) window.location.href = first.href;\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t\t// \"/\" focuses the search box\n\t\t\t\tdocument.addEventListener('keydown', (e) => {\n\t\t\t\t\tconst tag = document.activeElement ? document.activeElement.tagName : '';\n\t\t\t\t\tif (e.key === '/' && tag !== 'INPUT' && tag !== 'TEXTAREA') {\n\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\tsearchInput.focus();\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\n\t\t\t// ========== BACK TO TOP ==========\n\t\t\tdocument.getElementById('back-to-top').addEventListener('click', () => {\n\t\t\t\twindow.scrollTo({ top: 0, behavior: 'smooth' });\n\t\t\t});\n\n\t\t\t// ========== DIAGRAMS & MATH ==========\n\t\t\t// Mermaid replaces the diagram source with an SVG, so the source is\n\t\t\t// kept to redraw diagrams in the other color scheme\n\t\t\tfunction renderDiagrams(redraw) {\n\t\t\t\tif (!window.mermaid) return;\n\t\t\t\tconst diagrams = document.querySelectorAll('#article-content pre.mermaid');\n\t\t\t\tdiagrams.forEach((el) => {\n\t\t\t\t\tif (el.dataset.source === undefined) {\n\t\t\t\t\t\tel.dataset.source = el.textContent;\n\t\t\t\t\t} else if (redraw) {\n\t\t\t\t\t\tel.textContent = el.dataset.source;\n\t\t\t\t\t\tel.removeAttribute('data-processed');\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t\tmermaid.initialize({\n\t\t\t\t\tstartOnLoad: false,\n\t\t\t\t\ttheme: html.classList.contains('dark') ? 'dark' : 'default',\n\t\t\t\t});\n\t\t\t\tmermaid.run({ nodes: diagrams }).catch((err) => console.error('mermaid:', err));\n\t\t\t}\n\n\t\t\tfunction renderMath() {\n\t\t\t\tif (window.mdcliMath) {\n\t\t\t\t\tmdcliMath.renderAll(document.getElementById('article-content'));\n\t\t\t\t}\n\t\t\t}\n\n\t\t\t// ========== ARTICLE ENHANCEMENTS ==========\n\t\t\t// Re-run whenever live reload swaps in new content\n\t\t\tfunction enhanceArticle(e) {\n\t\t\t\tbuildTOC(e && e.detail ? e.detail.outline : null);\n\t\t\t\taddCopyButtons();\n\t\t\t\trenderDiagrams(false);\n\t\t\t\trenderMath();\n\t\t\t}\n\t\t\tenhanceArticle();\n\t\t\twindow.addEventListener('mdcli:content', enhanceArticle);\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -0,0 +1,253 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.977
+package views
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "strconv"
+
+// Slide is one rendered slide of a deck.
+type Slide struct {
+	Content string
+	Notes   string
+}
+
+// SlidesData holds typed data for the slide deck page.
+type SlidesData struct {
+	Title     string
+	ThemeName string
+	// ThemeCSS colors the slides with the theme palette
+	ThemeCSS   string
+	Slides     []Slide
+	AutoReload bool
+}
+
+// SlidesPage renders a slide deck. The same page is the presenter view
+// when opened with ?presenter.
+func SlidesPage(data SlidesData) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!doctype html><html lang=\"en\" class=\"dark\"><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var2 string
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(data.Title)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `slides.templ`, Line: 29, Col: 22}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " – mdcli Slides</title><link rel=\"icon\" type=\"image/x-icon\" href=\"/assets/icons/favicon.ico\"><link rel=\"stylesheet\" href=\"/assets/css/output.css\"><link rel=\"stylesheet\" href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 templ.SafeURL
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL("/assets/syntax/" + data.ThemeName + ".css"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `slides.templ`, Line: 32, Col: 87}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templ.Raw("<style>\n"+data.ThemeCSS+"</style>").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = slidesStyle().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if data.AutoReload {
+			templ_7745c5c3_Err = slidesReloadScript().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</head><body class=\"bg-background text-foreground\"><header id=\"presenter-bar\" class=\"presenter-only\"><span id=\"timer\" class=\"timer\">00:00</span> <button id=\"timer-toggle\" type=\"button\">Pause</button> <button id=\"timer-reset\" type=\"button\">Reset</button> <span id=\"clock\" class=\"muted\"></span> <span id=\"presenter-counter\" class=\"muted\"></span></header><main id=\"stage\"><div id=\"deck\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for i, slide := range data.Slides {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<section class=\"slide\" data-index=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var4 string
+			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(i))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `slides.templ`, Line: 50, Col: 57}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\"><article class=\"slide-content prose prose-neutral dark:prose-invert\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templ.Raw(slide.Content).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</article>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if slide.Notes != "" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<aside class=\"notes\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templ.Raw(slide.Notes).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</aside>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</section>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div><div id=\"presenter-side\" class=\"presenter-only\"><div class=\"label\">Next</div><div id=\"next-preview\"></div><div class=\"label\">Notes</div><div id=\"notes-view\" class=\"prose prose-neutral dark:prose-invert\"></div></div></main><div id=\"progress\"><div id=\"progress-bar\"></div></div><div id=\"counter\"></div><!-- Diagram and math renderers, served from the binary --><script src=\"/assets/js/mermaid.min.js\"></script><script src=\"/assets/js/math.js\"></script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = slidesScript().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</body></html>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// slidesStyle lays out one slide per screen, and the presenter view.
+func slidesStyle() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var5 == nil {
+			templ_7745c5c3_Var5 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<style>\n\t\thtml, body { height: 100%; overflow: hidden; }\n\t\t#stage { position: fixed; inset: 0; display: flex; }\n\t\t#deck { position: relative; flex: 1; min-width: 0; }\n\t\t.slide { position: absolute; inset: 0; display: none; align-items: center; justify-content: center; padding: 5vh 7vw; overflow: auto; border-radius: 0; }\n\t\t.slide.active { display: flex; }\n\t\t.slide .slide-content { width: 100%; max-width: 64rem; margin: auto; font-size: clamp(16px, 2.8vmin, 44px); }\n\t\t.slide .mdcli-document { background: transparent; padding: 0; }\n\t\t.slide img { max-height: 70vh; margin-inline: auto; }\n\t\t.slide .notes { display: none; }\n\t\t#progress { position: fixed; left: 0; right: 0; bottom: 0; height: 3px; }\n\t\t#progress-bar { height: 100%; width: 0; background: var(--mdcli-primary, var(--primary)); transition: width 0.2s; }\n\t\t#counter { position: fixed; right: 1rem; bottom: 0.75rem; font-size: 0.8rem; opacity: 0.6; }\n\t\t.presenter-only { display: none; }\n\t\t.muted { color: var(--muted-foreground); }\n\n\t\tbody.presenter #presenter-bar { position: fixed; top: 0; left: 0; right: 0; height: 3rem; display: flex; align-items: center; gap: 1rem; padding: 0 1rem; border-bottom: 1px solid var(--border); }\n\t\tbody.presenter #stage { top: 3rem; }\n\t\tbody.presenter #deck { flex: 3; border-right: 1px solid var(--border); }\n\t\tbody.presenter .slide .slide-content { font-size: clamp(12px, 1.8vmin, 28px); }\n\t\tbody.presenter #presenter-side { flex: 2; display: flex; flex-direction: column; gap: 0.5rem; padding: 1rem; min-width: 0; overflow: auto; }\n\t\tbody.presenter #counter, body.presenter #progress { display: none; }\n\t\t#presenter-bar .timer { font-size: 1.5rem; font-variant-numeric: tabular-nums; font-weight: 600; }\n\t\t#presenter-bar button { padding: 0.2rem 0.75rem; border: 1px solid var(--border); border-radius: 0.375rem; font-size: 0.85rem; }\n\t\t#presenter-side .label { font-size: 0.75rem; text-transform: uppercase; letter-spacing: 0.05em; color: var(--muted-foreground); }\n\t\t#next-preview { position: relative; flex: none; height: 36vh; overflow: hidden; border: 1px solid var(--border); border-radius: 0.5rem; }\n\t\t#next-preview .slide { padding: 1rem 1.5rem; }\n\t\t#next-preview .slide .slide-content { font-size: 10px; }\n\t\t#next-preview .end { display: flex; height: 100%; align-items: center; justify-content: center; color: var(--muted-foreground); }\n\t\t#notes-view { max-width: none; font-size: 1.1rem; }\n\t\t#notes-view .empty { color: var(--muted-foreground); }\n\t</style>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// slidesReloadScript subscribes to /events and swaps in the new slides
+// when the deck changes, staying on the current slide.
+func slidesReloadScript() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var6 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var6 == nil {
+			templ_7745c5c3_Var6 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<script>\n\t\t(function() {\n\t\t\tconst source = new EventSource('/events');\n\t\t\tlet pending = null;\n\n\t\t\tfunction swapDeck() {\n\t\t\t\tfetch(location.pathname, { cache: 'no-store' })\n\t\t\t\t\t.then(r => {\n\t\t\t\t\t\tif (!r.ok) throw new Error(r.statusText);\n\t\t\t\t\t\treturn r.text();\n\t\t\t\t\t})\n\t\t\t\t\t.then(text => {\n\t\t\t\t\t\tconst next = new DOMParser().parseFromString(text, 'text/html');\n\t\t\t\t\t\tdocument.getElementById('deck').innerHTML = next.getElementById('deck').innerHTML;\n\t\t\t\t\t\tdocument.title = next.title;\n\t\t\t\t\t\twindow.dispatchEvent(new Event('mdcli:content'));\n\t\t\t\t\t})\n\t\t\t\t\t.catch(() => location.reload());\n\t\t\t}\n\n\t\t\tsource.addEventListener('change', () => {\n\t\t\t\t// Coalesce the bursts of events a single save can produce\n\t\t\t\tclearTimeout(pending);\n\t\t\t\tpending = setTimeout(swapDeck, 50);\n\t\t\t});\n\t\t})();\n\t</script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// slidesScript handles navigation, the presenter view and its timer.
+// Windows showing the same deck follow each other's slide changes.
+func slidesScript() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var7 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var7 == nil {
+			templ_7745c5c3_Var7 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<script>\n\t\t(function() {\n\t\t\tconst html = document.documentElement;\n\t\t\tconst presenter = new URLSearchParams(location.search).has('presenter');\n\t\t\tdocument.body.classList.toggle('presenter', presenter);\n\n\t\t\t// Share the color scheme chosen in the preview\n\t\t\tconst storedTheme = localStorage.getItem('theme');\n\t\t\thtml.classList.toggle('dark', storedTheme\n\t\t\t\t? storedTheme === 'dark'\n\t\t\t\t: window.matchMedia('(prefers-color-scheme: dark)').matches);\n\n\t\t\tlet slides = [];\n\t\t\tlet current = Math.max(0, (parseInt(location.hash.slice(1), 10) || 1) - 1);\n\n\t\t\t// ========== SYNC ==========\n\t\t\t// Other tabs on the same server hear the channel; a presenter\n\t\t\t// window opened from a file also talks to its opener directly\n\t\t\tconst channel = 'BroadcastChannel' in window ? new BroadcastChannel('mdcli-slides') : null;\n\t\t\tlet peer = window.opener || null;\n\n\t\t\tfunction send(msg) {\n\t\t\t\tmsg.mdcliSlides = true;\n\t\t\t\tif (channel) channel.postMessage(msg);\n\t\t\t\tif (peer && !peer.closed) peer.postMessage(msg, '*');\n\t\t\t}\n\n\t\t\tfunction receive(msg) {\n\t\t\t\tif (msg && msg.mdcliSlides && msg.slide !== current) show(msg.slide, false);\n\t\t\t}\n\t\t\tif (channel) channel.onmessage = (e) => receive(e.data);\n\t\t\twindow.addEventListener('message', (e) => receive(e.data));\n\n\t\t\t// ========== RENDERING ==========\n\t\t\t// Diagrams are drawn when their slide is first shown, since\n\t\t\t// mermaid cannot measure hidden elements\n\t\t\tfunction renderDiagrams(root) {\n\t\t\t\tif (!window.mermaid || !root) return;\n\t\t\t\tconst diagrams = root.querySelectorAll('pre.mermaid:not([data-processed])');\n\t\t\t\tif (!diagrams.length) return;\n\t\t\t\tmermaid.initialize({\n\t\t\t\t\tstartOnLoad: false,\n\t\t\t\t\ttheme: html.classList.contains('dark') ? 'dark' : 'default',\n\t\t\t\t});\n\t\t\t\tmermaid.run({ nodes: diagrams }).catch((err) => console.error('mermaid:', err));\n\t\t\t}\n\n\t\t\tfunction load() {\n\t\t\t\tslides = Array.from(document.querySelectorAll('#deck > .slide'));\n\t\t\t\tif (window.mdcliMath) mdcliMath.renderAll(document.getElementById('deck'));\n\t\t\t\tshow(current, false);\n\t\t\t}\n\n\t\t\tfunction show(index, broadcast) {\n\t\t\t\tif (!slides.length) return;\n\t\t\t\tcurrent = Math.max(0, Math.min(index, slides.length - 1));\n\t\t\t\tslides.forEach((s, i) => s.classList.toggle('active', i === current));\n\t\t\t\trenderDiagrams(slides[current]);\n\n\t\t\t\tconst count = (current + 1) + ' / ' + slides.length;\n\t\t\t\tdocument.getElementById('counter').textContent = count;\n\t\t\t\tdocument.getElementById('presenter-counter').textContent = 'Slide ' + count;\n\t\t\t\tdocument.getElementById('progress-bar').style.width = ((current + 1) / slides.length * 100) + '%';\n\t\t\t\thistory.replaceState(null, '', location.pathname + location.search + '#' + (current + 1));\n\n\t\t\t\tif (presenter) updatePresenter();\n\t\t\t\tif (broadcast !== false) send({ slide: current });\n\t\t\t}\n\n\t\t\tfunction updatePresenter() {\n\t\t\t\tconst preview = document.getElementById('next-preview');\n\t\t\t\tpreview.innerHTML = '';\n\t\t\t\tconst next = slides[current + 1];\n\t\t\t\tif (next) {\n\t\t\t\t\tconst clone = next.cloneNode(true);\n\t\t\t\t\tclone.classList.add('active');\n\t\t\t\t\tpreview.appendChild(clone);\n\t\t\t\t\trenderDiagrams(clone);\n\t\t\t\t} else {\n\t\t\t\t\tpreview.innerHTML = '<p class=\"end\">End of deck</p>';\n\t\t\t\t}\n\t\t\t\tconst notes = slides[current].querySelector('.notes');\n\t\t\t\tdocument.getElementById('notes-view').innerHTML = notes\n\t\t\t\t\t? notes.innerHTML\n\t\t\t\t\t: '<p class=\"empty\">No notes for this slide</p>';\n\t\t\t}\n\n\t\t\t// ========== TIMER ==========\n\t\t\tlet started = Date.now();\n\t\t\tlet elapsed = 0;\n\t\t\tlet running = true;\n\t\t\tconst timerToggle = document.getElementById('timer-toggle');\n\n\t\t\tfunction tick() {\n\t\t\t\tconst total = Math.floor((running ? elapsed + Date.now() - started : elapsed) / 1000);\n\t\t\t\tconst pad = (n) => String(n).padStart(2, '0');\n\t\t\t\tconst h = Math.floor(total / 3600);\n\t\t\t\tconst m = Math.floor(total / 60) % 60;\n\t\t\t\tdocument.getElementById('timer').textContent = (h ? h + ':' : '') + pad(m) + ':' + pad(total % 60);\n\t\t\t\tdocument.getElementById('clock').textContent = new Date().toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' });\n\t\t\t}\n\n\t\t\tfunction toggleTimer() {\n\t\t\t\tif (running) {\n\t\t\t\t\telapsed += Date.now() - started;\n\t\t\t\t} else {\n\t\t\t\t\tstarted = Date.now();\n\t\t\t\t}\n\t\t\t\trunning = !running;\n\t\t\t\ttimerToggle.textContent = running ? 'Pause' : 'Resume';\n\t\t\t\ttick();\n\t\t\t}\n\n\t\t\tfunction resetTimer() {\n\t\t\t\telapsed = 0;\n\t\t\t\tstarted = Date.now();\n\t\t\t\ttick();\n\t\t\t}\n\n\t\t\tif (presenter) {\n\t\t\t\ttimerToggle.addEventListener('click', toggleTimer);\n\t\t\t\tdocument.getElementById('timer-reset').addEventListener('click', resetTimer);\n\t\t\t\ttick();\n\t\t\t\tsetInterval(tick, 500);\n\t\t\t}\n\n\t\t\t// ========== KEYBOARD & TOUCH ==========\n\t\t\t// Digits followed by Enter jump to a slide\n\t\t\tlet typed = '';\n\n\t\t\tdocument.addEventListener('keydown', (e) => {\n\t\t\t\tif (e.ctrlKey || e.metaKey || e.altKey) return;\n\t\t\t\tif (/^[0-9]$/.test(e.key)) {\n\t\t\t\t\ttyped += e.key;\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tswitch (e.key) {\n\t\t\t\t\tcase 'ArrowRight': case 'ArrowDown': case 'PageDown': case 'l': case 'j': case 'n':\n\t\t\t\t\t\tshow(current + 1);\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase ' ':\n\t\t\t\t\t\tshow(current + (e.shiftKey ? -1 : 1));\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'ArrowLeft': case 'ArrowUp': case 'PageUp': case 'Backspace': case 'h': case 'k': case 'p':\n\t\t\t\t\t\tshow(current - 1);\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'Home':\n\t\t\t\t\t\tshow(0);\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'End':\n\t\t\t\t\t\tshow(slides.length - 1);\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'Enter':\n\t\t\t\t\t\tif (typed) show(parseInt(typed, 10) - 1);\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'f':\n\t\t\t\t\t\tif (document.fullscreenElement) document.exitFullscreen();\n\t\t\t\t\t\telse html.requestFullscreen().catch(() => {});\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 's':\n\t\t\t\t\t\tif (!presenter) {\n\t\t\t\t\t\t\tpeer = window.open(location.pathname + '?presenter#' + (current + 1), 'mdcli-presenter', 'width=1280,height=800');\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 't':\n\t\t\t\t\t\tif (presenter) toggleTimer();\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'r':\n\t\t\t\t\t\tif (presenter) resetTimer();\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tdefault:\n\t\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\ttyped = '';\n\t\t\t\te.preventDefault();\n\t\t\t});\n\n\t\t\tlet touchX = null;\n\t\t\tdocument.addEventListener('touchstart', (e) => { touchX = e.touches[0].clientX; }, { passive: true });\n\t\t\tdocument.addEventListener('touchend', (e) => {\n\t\t\t\tif (touchX === null) return;\n\t\t\t\tconst dx = e.changedTouches[0].clientX - touchX;\n\t\t\t\tif (Math.abs(dx) > 50) show(current + (dx < 0 ? 1 : -1));\n\t\t\t\ttouchX = null;\n\t\t\t});\n\n\t\t\twindow.addEventListener('mdcli:content', load);\n\t\t\tload();\n\t\t})();\n\t</script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate